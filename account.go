@@ -0,0 +1,47 @@
+package runscope
+
+import (
+	"time"
+)
+
+// Account is the account the access token belongs to. See https://www.runscope.com/docs/api/resources/account
+type Account struct {
+	ID        string     `json:"id"`
+	UUID      string     `json:"uuid"`
+	Name      string     `json:"name"`
+	Email     string     `json:"email"`
+	CreatedAt *time.Time `json:"created_at"`
+	Teams     []*Team    `json:"teams"`
+}
+
+// ReadAccount reads the account the access token belongs to, e.g. to check the token before use or to show which
+// account a tool is connected as
+func (client *Client) ReadAccount() (*Account, error) {
+	resource, error := client.readResource("account", "current", "/account")
+	if error != nil {
+		return nil, error
+	}
+
+	account, error := getAccountFromResponse(resource.Data)
+	if error != nil {
+		return nil, error
+	}
+
+	return account, nil
+}
+
+// DefaultTeam returns the first team of the account, which is the team new buckets are created in by the Runscope
+// web interface, or nil if the account belongs to no team
+func (account *Account) DefaultTeam() *Team {
+	if len(account.Teams) == 0 {
+		return nil
+	}
+
+	return account.Teams[0]
+}
+
+func getAccountFromResponse(response interface{}) (*Account, error) {
+	account := new(Account)
+	err := decode(account, response)
+	return account, err
+}
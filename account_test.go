@@ -0,0 +1,73 @@
+package runscope
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestReadAccount(t *testing.T) {
+	testPreCheck(t)
+	client := clientConfigure()
+	account, err := client.ReadAccount()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(account.Email) <= 0 {
+		t.Errorf("Expected email got %s", account.Email)
+	}
+
+	if account.DefaultTeam() == nil {
+		t.Error("Expected a default team")
+	}
+}
+
+func TestReadAccountFromResponse(t *testing.T) {
+	responseBody := `
+{
+  "meta": {
+    "status": "success"
+  },
+  "data": {
+    "created_at": 1303149436,
+    "email": "grace@example.com",
+    "id": "4ee15ecc-7fe1-43cb-aa12-ef50420f2cf9",
+    "uuid": "4ee15ecc-7fe1-43cb-aa12-ef50420f2cf9",
+    "name": "Grace Hopper",
+    "teams": [
+      {
+        "name": "Acme",
+        "id": "ad5e9b5e-3aba-4ce9-b8a0-7e6b34a2d1ab"
+      },
+      {
+        "name": "Side project",
+        "id": "52e16b2e-7c32-4cb2-a1ce-1f7e2c1f2b8d"
+      }
+    ]
+  },
+  "error": null
+}
+`
+	responseMap := new(response)
+	if err := json.Unmarshal([]byte(responseBody), &responseMap); err != nil {
+		t.Error(err)
+	}
+
+	account, err := getAccountFromResponse(responseMap.Data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if account.Name != "Grace Hopper" || account.Email != "grace@example.com" {
+		t.Errorf("Expected Grace Hopper <grace@example.com>, actual %s <%s>", account.Name, account.Email)
+	}
+
+	if !account.CreatedAt.Equal(time.Unix(1303149436, 0)) {
+		t.Errorf("Expected created at %s, actual %s", time.Unix(1303149436, 0), account.CreatedAt)
+	}
+
+	if team := account.DefaultTeam(); team.ID != "ad5e9b5e-3aba-4ce9-b8a0-7e6b34a2d1ab" || team.Name != "Acme" {
+		t.Errorf("Expected default team Acme, actual %s %s", team.Name, team.ID)
+	}
+}
@@ -0,0 +1,144 @@
+package runscope
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Agent is a remote agent running tests from within a private network. See
+// https://www.runscope.com/docs/api/agents
+type Agent struct {
+	ID      string `json:"agent_id"`
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	// LastSeen is when the agent last connected to Runscope, nil when the api does not report it
+	LastSeen *time.Time `json:"last_seen"`
+}
+
+// ListAgents lists the remote agents of a team that are connected to Runscope
+func (client *Client) ListAgents(teamID string) ([]*Agent, error) {
+	resource, error := client.readResource("agents", teamID, fmt.Sprintf("/teams/%s/agents", teamID))
+	if error != nil {
+		return nil, error
+	}
+
+	agents, error := getAgentsFromResponse(resource.Data)
+	if error != nil {
+		return nil, error
+	}
+
+	return agents, nil
+}
+
+// LocalMachine returns a reference to the agent to add to Environment.RemoteAgents
+func (agent *Agent) LocalMachine() *LocalMachine {
+	return &LocalMachine{Name: agent.Name, UUID: agent.ID}
+}
+
+// AgentReport is the outcome of VerifyAgents
+type AgentReport struct {
+	Agents []*Agent
+	// Missing are the required agents that are not connected
+	Missing []string
+	// Outdated are the agents running an older version than the newest agent of the team, or not reporting one
+	Outdated []*Agent
+	// Unregistered are the environments referencing an agent that is no longer connected
+	Unregistered []*AgentReference
+}
+
+// AgentReference is an agent referenced by an environment
+type AgentReference struct {
+	Environment *EnvironmentReference
+	Agent       *LocalMachine
+}
+
+// Healthy reports whether no problem was found
+func (report *AgentReport) Healthy() bool {
+	return len(report.Missing) == 0 && len(report.Outdated) == 0 && len(report.Unregistered) == 0
+}
+
+// VerifyAgents checks that the required agents of a team, by name or ID, are connected and up to date, and that the
+// environments of the team don't reference agents that are gone, which silently stops on-premise tests from running
+func (client *Client) VerifyAgents(teamID string, required []string) (*AgentReport, error) {
+	agents, error := client.ListAgents(teamID)
+	if error != nil {
+		return nil, error
+	}
+
+	environments, error := client.ListTeamEnvironments(teamID)
+	if error != nil {
+		return nil, error
+	}
+
+	return newAgentReport(agents, required, environments), nil
+}
+
+func newAgentReport(agents []*Agent, required []string, environments []*EnvironmentReference) *AgentReport {
+	report := &AgentReport{Agents: agents}
+	registered := map[string]bool{}
+	latest := ""
+	for _, agent := range agents {
+		registered[agent.ID] = true
+		registered[agent.Name] = true
+		if compareVersions(agent.Version, latest) > 0 {
+			latest = agent.Version
+		}
+	}
+
+	for _, name := range required {
+		if !registered[name] {
+			report.Missing = append(report.Missing, name)
+		}
+	}
+
+	for _, agent := range agents {
+		if agent.Version == "" || compareVersions(agent.Version, latest) < 0 {
+			report.Outdated = append(report.Outdated, agent)
+		}
+	}
+
+	for _, environment := range environments {
+		for _, machine := range environment.Environment.RemoteAgents {
+			if !registered[machine.UUID] {
+				report.Unregistered = append(report.Unregistered,
+					&AgentReference{Environment: environment, Agent: machine})
+			}
+		}
+	}
+
+	return report
+}
+
+// compareVersions compares dotted version numbers such as 3.1.0, returning -1, 0 or 1
+func compareVersions(a, b string) int {
+	left := strings.Split(strings.TrimPrefix(a, "v"), ".")
+	right := strings.Split(strings.TrimPrefix(b, "v"), ".")
+	for i := 0; i < len(left) || i < len(right); i++ {
+		var l, r int
+		if i < len(left) {
+			l, _ = strconv.Atoi(left[i])
+		}
+
+		if i < len(right) {
+			r, _ = strconv.Atoi(right[i])
+		}
+
+		if l != r {
+			if l < r {
+				return -1
+			}
+
+			return 1
+		}
+	}
+
+	return 0
+}
+
+func getAgentsFromResponse(response interface{}) ([]*Agent, error) {
+	var agents []*Agent
+	err := decode(&agents, response)
+	return agents, err
+}
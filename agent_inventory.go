@@ -0,0 +1,93 @@
+package runscope
+
+import (
+	"sort"
+	"time"
+)
+
+// AgentInventory lists the remote agents of every team the client can access, see Client.AgentInventory
+type AgentInventory struct {
+	Agents []*TeamAgent
+	// Latest is the newest agent version running in any team
+	Latest string
+	// Outdated are the agents running an older version than Latest, or not reporting one
+	Outdated []*TeamAgent
+	// Stale are the agents not seen for longer than the maximum age
+	Stale []*TeamAgent
+}
+
+// TeamAgent is an agent along with the team it belongs to
+type TeamAgent struct {
+	Team  *Team
+	Agent *Agent
+}
+
+// AgentInventory lists the agents of every team of the account, and of the teams routed to other tokens with
+// RouteTeam or AddToken, flagging the agents running an outdated version or not seen for longer than maxAge. A
+// maxAge of zero doesn't flag agents by age
+func (client *Client) AgentInventory(maxAge time.Duration) (*AgentInventory, error) {
+	account, error := client.ReadAccount()
+	if error != nil {
+		return nil, error
+	}
+
+	var agents []*TeamAgent
+	for _, team := range client.inventoryTeams(account) {
+		teamAgents, error := client.ListAgents(team.ID)
+		if error != nil {
+			return nil, error
+		}
+
+		for _, agent := range teamAgents {
+			agents = append(agents, &TeamAgent{Team: team, Agent: agent})
+		}
+	}
+
+	return newAgentInventory(agents, maxAge, time.Now()), nil
+}
+
+// inventoryTeams returns the teams of the account followed by the routed teams it doesn't belong to
+func (client *Client) inventoryTeams(account *Account) []*Team {
+	teams := append([]*Team{}, account.Teams...)
+	known := map[string]bool{}
+	for _, team := range teams {
+		known[team.ID] = true
+	}
+
+	client.tokens.mu.RLock()
+	var routed []string
+	for teamID := range client.tokens.teams {
+		if !known[teamID] {
+			routed = append(routed, teamID)
+		}
+	}
+	client.tokens.mu.RUnlock()
+
+	sort.Strings(routed)
+	for _, teamID := range routed {
+		teams = append(teams, &Team{ID: teamID})
+	}
+
+	return teams
+}
+
+func newAgentInventory(agents []*TeamAgent, maxAge time.Duration, now time.Time) *AgentInventory {
+	inventory := &AgentInventory{Agents: agents}
+	for _, agent := range agents {
+		if compareVersions(agent.Agent.Version, inventory.Latest) > 0 {
+			inventory.Latest = agent.Agent.Version
+		}
+	}
+
+	for _, agent := range agents {
+		if agent.Agent.Version == "" || compareVersions(agent.Agent.Version, inventory.Latest) < 0 {
+			inventory.Outdated = append(inventory.Outdated, agent)
+		}
+
+		if maxAge > 0 && agent.Agent.LastSeen != nil && now.Sub(*agent.Agent.LastSeen) > maxAge {
+			inventory.Stale = append(inventory.Stale, agent)
+		}
+	}
+
+	return inventory
+}
@@ -0,0 +1,46 @@
+package runscope
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAgentInventory(t *testing.T) {
+	now := time.Date(2021, 5, 6, 10, 0, 0, 0, time.UTC)
+	yesterday := now.Add(-24 * time.Hour)
+	recently := now.Add(-time.Minute)
+	acme, platform := &Team{ID: "1", Name: "Acme"}, &Team{ID: "2", Name: "Platform"}
+
+	inventory := newAgentInventory([]*TeamAgent{
+		{Team: acme, Agent: &Agent{ID: "a", Name: "on-prem-1", Version: "3.1.0", LastSeen: &recently}},
+		{Team: platform, Agent: &Agent{ID: "b", Name: "on-prem-2", Version: "3.2.1", LastSeen: &yesterday}},
+		{Team: platform, Agent: &Agent{ID: "c", Name: "on-prem-3"}},
+	}, time.Hour, now)
+
+	if inventory.Latest != "3.2.1" {
+		t.Errorf("Expected latest version %s, actual %s", "3.2.1", inventory.Latest)
+	}
+
+	if len(inventory.Outdated) != 2 || inventory.Outdated[0].Agent.ID != "a" || inventory.Outdated[1].Agent.ID != "c" {
+		t.Errorf("Expected agents a and c to be outdated, actual %v", inventory.Outdated)
+	}
+
+	if len(inventory.Stale) != 1 || inventory.Stale[0].Team != platform {
+		t.Errorf("Expected agent b of Platform to be stale, actual %v", inventory.Stale)
+	}
+
+	if inventory = newAgentInventory(inventory.Agents, 0, now); len(inventory.Stale) != 0 {
+		t.Errorf("Expected no stale agents without a maximum age, actual %v", inventory.Stale)
+	}
+}
+
+func TestInventoryTeams(t *testing.T) {
+	client := NewClient(APIURL, "default")
+	client.RouteTeam("2", "other")
+	client.RouteTeam("3", "other")
+
+	teams := client.inventoryTeams(&Account{Teams: []*Team{{ID: "1"}, {ID: "2"}}})
+	if len(teams) != 3 || teams[2].ID != "3" {
+		t.Errorf("Expected teams 1, 2 and routed team 3, actual %v", teams)
+	}
+}
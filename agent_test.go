@@ -0,0 +1,83 @@
+package runscope
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestListAgents(t *testing.T) {
+	testPreCheck(t)
+	client := clientConfigure()
+	if _, err := client.ListAgents(teamID); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestListAgentsFromResponse(t *testing.T) {
+	responseBody := `
+{
+  "meta": {
+    "status": "success"
+  },
+  "data": [
+    {
+      "agent_id": "5e4a7f9b-2c3d-4e5f-8a9b-0c1d2e3f4a5b",
+      "name": "on-prem-1",
+      "version": "3.1.0"
+    }
+  ],
+  "error": null
+}
+`
+	responseMap := new(response)
+	if err := json.Unmarshal([]byte(responseBody), &responseMap); err != nil {
+		t.Error(err)
+	}
+
+	agents, err := getAgentsFromResponse(responseMap.Data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(agents) != 1 || agents[0].Name != "on-prem-1" || agents[0].Version != "3.1.0" {
+		t.Fatalf("Expected agent on-prem-1 3.1.0, actual %v", agents)
+	}
+
+	machine := agents[0].LocalMachine()
+	if machine.UUID != "5e4a7f9b-2c3d-4e5f-8a9b-0c1d2e3f4a5b" || machine.Name != "on-prem-1" {
+		t.Errorf("Expected local machine on-prem-1, actual %s %s", machine.Name, machine.UUID)
+	}
+}
+
+func TestNewAgentReport(t *testing.T) {
+	agents := []*Agent{
+		{ID: "1", Name: "on-prem-1", Version: "3.10.0"},
+		{ID: "2", Name: "on-prem-2", Version: "3.9.2"},
+		{ID: "3", Name: "on-prem-3"},
+	}
+	environments := []*EnvironmentReference{
+		{Bucket: &Bucket{Name: "prod"}, Environment: &Environment{Name: "dc1",
+			RemoteAgents: []*LocalMachine{{Name: "on-prem-1", UUID: "1"}, {Name: "old", UUID: "9"}}}},
+	}
+
+	report := newAgentReport(agents, []string{"on-prem-1", "2", "on-prem-4"}, environments)
+	if report.Healthy() {
+		t.Fatal("Expected report not to be healthy")
+	}
+
+	if len(report.Missing) != 1 || report.Missing[0] != "on-prem-4" {
+		t.Errorf("Expected on-prem-4 to be missing, actual %v", report.Missing)
+	}
+
+	if len(report.Outdated) != 2 || report.Outdated[0].Name != "on-prem-2" || report.Outdated[1].Name != "on-prem-3" {
+		t.Errorf("Expected on-prem-2 and on-prem-3 to be outdated, actual %v", report.Outdated)
+	}
+
+	if len(report.Unregistered) != 1 || report.Unregistered[0].Agent.UUID != "9" {
+		t.Errorf("Expected agent 9 to be unregistered, actual %v", report.Unregistered)
+	}
+
+	if report.Unregistered[0].Environment.String() != "prod/dc1" {
+		t.Errorf("Expected environment prod/dc1, actual %s", report.Unregistered[0].Environment)
+	}
+}
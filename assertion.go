@@ -0,0 +1,224 @@
+package runscope
+
+import (
+	"fmt"
+)
+
+// Assertion sources. See https://www.runscope.com/docs/api/steps#assertions
+const (
+	AssertionSourceResponseStatus  = "response_status"
+	AssertionSourceResponseHeaders = "response_headers"
+	AssertionSourceResponseJSON    = "response_json"
+	AssertionSourceResponseXML     = "response_xml"
+	AssertionSourceResponseText    = "response_text"
+	AssertionSourceResponseTime    = "response_time_ms"
+	AssertionSourceResponseSize    = "response_size_bytes"
+)
+
+// Assertion comparisons. See https://www.runscope.com/docs/api/steps#assertions
+const (
+	ComparisonEqual                = "equal"
+	ComparisonNotEqual             = "not_equal"
+	ComparisonEmpty                = "empty"
+	ComparisonNotEmpty             = "not_empty"
+	ComparisonContains             = "contains"
+	ComparisonDoesNotContain       = "does_not_contain"
+	ComparisonIsANumber            = "is_a_number"
+	ComparisonEqualNumber          = "equal_number"
+	ComparisonIsLessThan           = "is_less_than"
+	ComparisonIsLessThanOrEqual    = "is_less_than_or_equal"
+	ComparisonIsGreaterThan        = "is_greater_than"
+	ComparisonIsGreaterThanOrEqual = "is_greater_than_or_equal"
+	ComparisonHasKey               = "has_key"
+	ComparisonHasValue             = "has_value"
+	ComparisonIsNull               = "is_null"
+)
+
+var numericComparisons = map[string]bool{
+	ComparisonEqual:                true,
+	ComparisonNotEqual:             true,
+	ComparisonEqualNumber:          true,
+	ComparisonIsLessThan:           true,
+	ComparisonIsLessThanOrEqual:    true,
+	ComparisonIsGreaterThan:        true,
+	ComparisonIsGreaterThanOrEqual: true,
+}
+
+var textComparisons = map[string]bool{
+	ComparisonEqual:                true,
+	ComparisonNotEqual:             true,
+	ComparisonEmpty:                true,
+	ComparisonNotEmpty:             true,
+	ComparisonContains:             true,
+	ComparisonDoesNotContain:       true,
+	ComparisonIsANumber:            true,
+	ComparisonEqualNumber:          true,
+	ComparisonIsLessThan:           true,
+	ComparisonIsLessThanOrEqual:    true,
+	ComparisonIsGreaterThan:        true,
+	ComparisonIsGreaterThanOrEqual: true,
+}
+
+var jsonComparisons = map[string]bool{
+	ComparisonHasKey:   true,
+	ComparisonHasValue: true,
+	ComparisonIsNull:   true,
+}
+
+// AssertionBuilder builds an assertion fluently, e.g. AssertJSON("data.id").Equals("{{expected}}")
+type AssertionBuilder struct {
+	source   string
+	property string
+}
+
+// AssertStatus starts an assertion on the response status code
+func AssertStatus() *AssertionBuilder {
+	return &AssertionBuilder{source: AssertionSourceResponseStatus}
+}
+
+// AssertHeader starts an assertion on the named response header
+func AssertHeader(name string) *AssertionBuilder {
+	return &AssertionBuilder{source: AssertionSourceResponseHeaders, property: name}
+}
+
+// AssertJSON starts an assertion on the property of a json response body
+func AssertJSON(property string) *AssertionBuilder {
+	return &AssertionBuilder{source: AssertionSourceResponseJSON, property: property}
+}
+
+// AssertXML starts an assertion on the property of an xml response body
+func AssertXML(property string) *AssertionBuilder {
+	return &AssertionBuilder{source: AssertionSourceResponseXML, property: property}
+}
+
+// AssertText starts an assertion on the raw response body
+func AssertText() *AssertionBuilder {
+	return &AssertionBuilder{source: AssertionSourceResponseText}
+}
+
+// AssertResponseTime starts an assertion on the response time in milliseconds
+func AssertResponseTime() *AssertionBuilder {
+	return &AssertionBuilder{source: AssertionSourceResponseTime}
+}
+
+// AssertResponseSize starts an assertion on the response size in bytes
+func AssertResponseSize() *AssertionBuilder {
+	return &AssertionBuilder{source: AssertionSourceResponseSize}
+}
+
+// Compare completes the assertion with any comparison and value
+func (builder *AssertionBuilder) Compare(comparison string, value interface{}) *Assertion {
+	return &Assertion{
+		Source:     builder.source,
+		Property:   builder.property,
+		Comparison: comparison,
+		Value:      value,
+	}
+}
+
+// Equals asserts the value is equal to value
+func (builder *AssertionBuilder) Equals(value interface{}) *Assertion {
+	return builder.Compare(ComparisonEqual, value)
+}
+
+// NotEquals asserts the value is not equal to value
+func (builder *AssertionBuilder) NotEquals(value interface{}) *Assertion {
+	return builder.Compare(ComparisonNotEqual, value)
+}
+
+// IsEmpty asserts the value is empty
+func (builder *AssertionBuilder) IsEmpty() *Assertion {
+	return builder.Compare(ComparisonEmpty, nil)
+}
+
+// IsNotEmpty asserts the value is not empty
+func (builder *AssertionBuilder) IsNotEmpty() *Assertion {
+	return builder.Compare(ComparisonNotEmpty, nil)
+}
+
+// Contains asserts the value contains value
+func (builder *AssertionBuilder) Contains(value interface{}) *Assertion {
+	return builder.Compare(ComparisonContains, value)
+}
+
+// DoesNotContain asserts the value does not contain value
+func (builder *AssertionBuilder) DoesNotContain(value interface{}) *Assertion {
+	return builder.Compare(ComparisonDoesNotContain, value)
+}
+
+// IsANumber asserts the value is a number
+func (builder *AssertionBuilder) IsANumber() *Assertion {
+	return builder.Compare(ComparisonIsANumber, nil)
+}
+
+// EqualsNumber asserts the value is numerically equal to value
+func (builder *AssertionBuilder) EqualsNumber(value interface{}) *Assertion {
+	return builder.Compare(ComparisonEqualNumber, value)
+}
+
+// IsLessThan asserts the value is less than value
+func (builder *AssertionBuilder) IsLessThan(value interface{}) *Assertion {
+	return builder.Compare(ComparisonIsLessThan, value)
+}
+
+// IsLessThanOrEqual asserts the value is less than or equal to value
+func (builder *AssertionBuilder) IsLessThanOrEqual(value interface{}) *Assertion {
+	return builder.Compare(ComparisonIsLessThanOrEqual, value)
+}
+
+// IsGreaterThan asserts the value is greater than value
+func (builder *AssertionBuilder) IsGreaterThan(value interface{}) *Assertion {
+	return builder.Compare(ComparisonIsGreaterThan, value)
+}
+
+// IsGreaterThanOrEqual asserts the value is greater than or equal to value
+func (builder *AssertionBuilder) IsGreaterThanOrEqual(value interface{}) *Assertion {
+	return builder.Compare(ComparisonIsGreaterThanOrEqual, value)
+}
+
+// HasKey asserts the json object has the key value
+func (builder *AssertionBuilder) HasKey(value interface{}) *Assertion {
+	return builder.Compare(ComparisonHasKey, value)
+}
+
+// HasValue asserts the json array or object contains value
+func (builder *AssertionBuilder) HasValue(value interface{}) *Assertion {
+	return builder.Compare(ComparisonHasValue, value)
+}
+
+// IsNull asserts the json value is null
+func (builder *AssertionBuilder) IsNull() *Assertion {
+	return builder.Compare(ComparisonIsNull, nil)
+}
+
+// Validate checks the comparison can be used with the source and that a property is set when the source requires one,
+// assertions on sources without a constant, e.g. of subtest or Ghost Inspector steps, are passed on unchecked
+func (assertion *Assertion) Validate() error {
+	var allowed map[string]bool
+	switch assertion.Source {
+	case AssertionSourceResponseStatus, AssertionSourceResponseTime, AssertionSourceResponseSize:
+		allowed = numericComparisons
+	case AssertionSourceResponseHeaders, AssertionSourceResponseXML:
+		if assertion.Property == "" {
+			return fmt.Errorf("An assertion with source %q must specify 'Property'", assertion.Source)
+		}
+		allowed = textComparisons
+	case AssertionSourceResponseText:
+		allowed = textComparisons
+	case AssertionSourceResponseJSON:
+		if !textComparisons[assertion.Comparison] && !jsonComparisons[assertion.Comparison] {
+			return fmt.Errorf("Comparison %q can not be used with assertion source %q",
+				assertion.Comparison, assertion.Source)
+		}
+		return nil
+	default:
+		return nil
+	}
+
+	if !allowed[assertion.Comparison] {
+		return fmt.Errorf("Comparison %q can not be used with assertion source %q",
+			assertion.Comparison, assertion.Source)
+	}
+
+	return nil
+}
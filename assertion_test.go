@@ -0,0 +1,73 @@
+package runscope
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAssertionBuilder(t *testing.T) {
+	assertion := AssertJSON("data.id").Equals("{{expected}}")
+	if assertion.Source != AssertionSourceResponseJSON {
+		t.Errorf("Expected source %s, actual %s", AssertionSourceResponseJSON, assertion.Source)
+	}
+
+	if assertion.Property != "data.id" {
+		t.Errorf("Expected property %s, actual %s", "data.id", assertion.Property)
+	}
+
+	if assertion.Comparison != ComparisonEqual {
+		t.Errorf("Expected comparison %s, actual %s", ComparisonEqual, assertion.Comparison)
+	}
+
+	if assertion.Value != "{{expected}}" {
+		t.Errorf("Expected value %s, actual %v", "{{expected}}", assertion.Value)
+	}
+}
+
+func TestAssertionValidate(t *testing.T) {
+	valid := []*Assertion{
+		AssertStatus().EqualsNumber(200),
+		AssertResponseTime().IsLessThan(500),
+		AssertHeader("Content-Type").Contains("json"),
+		AssertJSON("items").HasValue("foo"),
+		AssertText().IsNotEmpty(),
+		{Source: "response_unknown", Comparison: ComparisonEqual},
+	}
+
+	for _, assertion := range valid {
+		if err := assertion.Validate(); err != nil {
+			t.Errorf("Expected assertion %#v to be valid, actual error %s", assertion, err)
+		}
+	}
+
+	invalid := []*Assertion{
+		AssertStatus().Contains("20"),
+		AssertResponseSize().HasKey("foo"),
+		AssertHeader("").Equals("foo"),
+		AssertText().IsNull(),
+	}
+
+	for _, assertion := range invalid {
+		if err := assertion.Validate(); err == nil {
+			t.Errorf("Expected assertion %#v to be invalid", assertion)
+		}
+	}
+}
+
+func TestValidationInvalidAssertion(t *testing.T) {
+	step := NewTestStep()
+	step.StepType = "request"
+	step.URL = "http://example.com"
+	step.Method = "GET"
+	step.Assertions = []*Assertion{AssertStatus().Contains("200")}
+
+	client := clientConfigure()
+	_, err := client.CreateTestStep(step, "foo", "ba")
+	if err == nil {
+		t.Fatal("Expected validation error for invalid assertion")
+	}
+
+	if !strings.Contains(err.Error(), "can not be used with assertion source") {
+		t.Errorf("Expected assertion validation error, actual %s", err)
+	}
+}
@@ -0,0 +1,229 @@
+// Package backup writes snapshots of every team, bucket, test, environment and schedule an access token can reach,
+// restores them into an account, and migrates buckets and tests between teams.
+package backup
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/ewilde/go-runscope"
+)
+
+// Version of the snapshot format written by Write, Read rejects snapshots of newer versions
+const Version = 1
+
+// Snapshot is the state of an account at CreatedAt
+type Snapshot struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	// Redacted reports whether secrets were replaced by runscope.RedactedValue, such a snapshot can't be restored
+	// as is
+	Redacted bool    `json:"redacted"`
+	Teams    []*Team `json:"teams"`
+}
+
+// Team is a team with its integrations and buckets
+type Team struct {
+	ID           string                  `json:"id"`
+	Name         string                  `json:"name"`
+	Integrations []*runscope.Integration `json:"integrations"`
+	Buckets      []*Bucket               `json:"buckets"`
+}
+
+// Bucket is a bucket with its shared environments and tests
+type Bucket struct {
+	Key          runscope.BucketKey      `json:"key"`
+	Name         string                  `json:"name"`
+	Environments []*runscope.Environment `json:"environments"`
+	Tests        []*Test                 `json:"tests"`
+}
+
+// Test is a test with its steps, its test environments in Test.Environments, and its schedules
+type Test struct {
+	Test      *runscope.Test       `json:"test"`
+	Schedules []*runscope.Schedule `json:"schedules"`
+}
+
+// Options of Create
+type Options struct {
+	// Teams limits the snapshot to the teams with these IDs, every team of the account when empty
+	Teams []string
+	// Redact replaces the secrets of tests and environments by runscope.RedactedValue, see runscope.Test.Redacted
+	Redact bool
+}
+
+// Create walks the teams of the account of client and reads everything they contain into a snapshot
+func Create(client runscope.ClientAPI, options *Options) (*Snapshot, error) {
+	if options == nil {
+		options = &Options{}
+	}
+
+	account, err := client.ReadAccount()
+	if err != nil {
+		return nil, err
+	}
+
+	selected := map[string]bool{}
+	for _, teamID := range options.Teams {
+		selected[teamID] = true
+	}
+
+	snapshot := &Snapshot{Version: Version, CreatedAt: time.Now().UTC(), Redacted: options.Redact}
+	for _, team := range account.Teams {
+		if len(selected) > 0 && !selected[team.ID] {
+			continue
+		}
+
+		teamSnapshot, err := createTeam(client, team, options.Redact)
+		if err != nil {
+			return nil, err
+		}
+
+		snapshot.Teams = append(snapshot.Teams, teamSnapshot)
+	}
+
+	return snapshot, nil
+}
+
+func createTeam(client runscope.ClientAPI, team *runscope.Team, redact bool) (*Team, error) {
+	runscope.DebugF(1, "backing up team %s", team.Name)
+	integrations, err := client.ListIntegrations(team.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	buckets, err := client.ListBuckets(&runscope.ListBucketsInput{TeamID: team.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &Team{ID: team.ID, Name: team.Name, Integrations: integrations}
+	for _, bucket := range buckets {
+		bucketSnapshot, err := createBucket(client, bucket, redact)
+		if err != nil {
+			return nil, err
+		}
+
+		snapshot.Buckets = append(snapshot.Buckets, bucketSnapshot)
+	}
+
+	return snapshot, nil
+}
+
+func createBucket(client runscope.ClientAPI, bucket *runscope.Bucket, redact bool) (*Bucket, error) {
+	runscope.DebugF(1, "backing up bucket %s", bucket.Name)
+	environments, err := client.ListSharedEnvironment(bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	tests, err := client.ListAllTests(&runscope.ListTestsInput{BucketKey: bucket.Key})
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &Bucket{Key: bucket.Key, Name: bucket.Name}
+	for _, environment := range environments {
+		if redact {
+			environment = environment.Redacted()
+		}
+
+		snapshot.Environments = append(snapshot.Environments, environment)
+	}
+
+	for _, test := range tests {
+		testSnapshot, err := createTest(client, bucket, test, redact)
+		if err != nil {
+			return nil, err
+		}
+
+		snapshot.Tests = append(snapshot.Tests, testSnapshot)
+	}
+
+	return snapshot, nil
+}
+
+func createTest(client runscope.ClientAPI, bucket *runscope.Bucket, test *runscope.Test, redact bool) (*Test, error) {
+	test.Bucket = bucket
+	detail, err := client.ReadTest(test)
+	if err != nil {
+		return nil, err
+	}
+
+	if detail.Environments, err = client.ListTestEnvironment(bucket, test); err != nil {
+		return nil, err
+	}
+
+	schedules, err := client.ListSchedules(bucket.Key, test.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if redact {
+		detail = detail.Redacted()
+	}
+
+	detail.LastRun = nil
+	return &Test{Test: detail, Schedules: schedules}, nil
+}
+
+// Write writes the snapshot as gzip compressed json
+func (snapshot *Snapshot) Write(writer io.Writer) error {
+	compressed := gzip.NewWriter(writer)
+	if err := json.NewEncoder(compressed).Encode(snapshot); err != nil {
+		return fmt.Errorf("Error writing snapshot: %s", err)
+	}
+
+	return compressed.Close()
+}
+
+// WriteFile writes the snapshot to a file, see Write
+func (snapshot *Snapshot) WriteFile(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err = snapshot.Write(file); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+// Read reads a snapshot written by Write
+func Read(reader io.Reader) (*Snapshot, error) {
+	compressed, err := gzip.NewReader(reader)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading snapshot: %s", err)
+	}
+	defer compressed.Close()
+
+	snapshot := new(Snapshot)
+	if err := json.NewDecoder(compressed).Decode(snapshot); err != nil {
+		return nil, fmt.Errorf("Error reading snapshot: %s", err)
+	}
+
+	if snapshot.Version < 1 || snapshot.Version > Version {
+		return nil, fmt.Errorf("Error reading snapshot: unsupported version %d, expected at most %d",
+			snapshot.Version, Version)
+	}
+
+	return snapshot, nil
+}
+
+// ReadFile reads a snapshot from a file, see Read
+func ReadFile(path string) (*Snapshot, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return Read(file)
+}
@@ -0,0 +1,97 @@
+package backup
+
+import (
+	"bytes"
+	"compress/gzip"
+	"testing"
+
+	"github.com/ewilde/go-runscope"
+)
+
+// fakeClient serves a single team with one bucket and test, calls of methods it doesn't implement panic
+type fakeClient struct {
+	runscope.ClientAPI
+	deleted []string
+}
+
+func (client *fakeClient) ReadAccount() (*runscope.Account, error) {
+	return &runscope.Account{Teams: []*runscope.Team{{ID: "acme", Name: "Acme"}, {ID: "other", Name: "Other"}}}, nil
+}
+
+func (client *fakeClient) ListIntegrations(teamID string) ([]*runscope.Integration, error) {
+	return []*runscope.Integration{{ID: "30", IntegrationType: runscope.IntegrationTypeSlack, Description: "Slack: #oncall"}}, nil
+}
+
+func (client *fakeClient) ListBuckets(input *runscope.ListBucketsInput) ([]*runscope.Bucket, error) {
+	return []*runscope.Bucket{{Name: "Production", Key: "z3n32gktzx94"}}, nil
+}
+
+func (client *fakeClient) ListSharedEnvironment(bucket *runscope.Bucket) ([]*runscope.Environment, error) {
+	return []*runscope.Environment{{ID: "1", Name: "prod", InitialVariables: map[string]string{"apiToken": "abc123"},
+		Integrations: []*runscope.EnvironmentIntegration{{ID: "30", IntegrationType: runscope.IntegrationTypeSlack}}}}, nil
+}
+
+func (client *fakeClient) ListAllTests(input *runscope.ListTestsInput) ([]*runscope.Test, error) {
+	return []*runscope.Test{{ID: "10", Name: "Health"}}, nil
+}
+
+func (client *fakeClient) ReadTest(test *runscope.Test) (*runscope.Test, error) {
+	return &runscope.Test{ID: test.ID, Name: test.Name, Bucket: test.Bucket, DefaultEnvironmentID: "2",
+		LastRun: &runscope.TestRun{Status: "completed"},
+		Steps: []*runscope.TestStep{{StepType: runscope.StepTypeSubtest, TestUUID: "11", BucketKey: test.Bucket.Key,
+			EnvironmentID: "1"}}}, nil
+}
+
+func (client *fakeClient) ListTestEnvironment(bucket *runscope.Bucket, test *runscope.Test) ([]*runscope.Environment, error) {
+	return []*runscope.Environment{{ID: "2", Name: "Test Settings", ParentEnvironmentID: "1"}}, nil
+}
+
+func (client *fakeClient) ListSchedules(bucketKey runscope.BucketKey, testID string) ([]*runscope.Schedule, error) {
+	return []*runscope.Schedule{{ID: "20", EnvironmentID: "2", Interval: "5m"}}, nil
+}
+
+func TestCreateAndRead(t *testing.T) {
+	snapshot, err := Create(&fakeClient{}, &Options{Teams: []string{"acme"}, Redact: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(snapshot.Teams) != 1 || snapshot.Teams[0].Name != "Acme" || !snapshot.Redacted {
+		t.Fatalf("Expected redacted snapshot of team Acme, actual %v", snapshot.Teams)
+	}
+
+	var buffer bytes.Buffer
+	if err := snapshot.Write(&buffer); err != nil {
+		t.Fatal(err)
+	}
+
+	read, err := Read(&buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bucket := read.Teams[0].Buckets[0]
+	if bucket.Environments[0].InitialVariables["apiToken"] != runscope.RedactedValue {
+		t.Errorf("Expected redacted apiToken, actual %v", bucket.Environments[0].InitialVariables)
+	}
+
+	test := bucket.Tests[0]
+	if test.Test.Name != "Health" || len(test.Test.Environments) != 1 || test.Schedules[0].Interval != "5m" {
+		t.Errorf("Expected test Health with its environment and schedule, actual %#v", test)
+	}
+
+	if test.Test.LastRun != nil {
+		t.Errorf("Expected last run not to be backed up, actual %v", test.Test.LastRun)
+	}
+}
+
+func TestReadUnsupportedVersion(t *testing.T) {
+	var buffer bytes.Buffer
+	compressed := gzip.NewWriter(&buffer)
+	compressed.Write([]byte(`{"version": 2}`))
+	compressed.Close()
+
+	if _, err := Read(&buffer); err == nil {
+		t.Error("Expected an error for a newer snapshot version")
+	}
+}
@@ -0,0 +1,161 @@
+package backup
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ewilde/go-runscope"
+)
+
+// TeamRef is a team along with a client of the account it belongs to
+type TeamRef struct {
+	Client runscope.ClientAPI
+	TeamID string
+}
+
+// Selection of the buckets and tests migrated by Migrate
+type Selection struct {
+	// Buckets are migrated whole, with their shared environments and tests, into new buckets
+	Buckets []runscope.BucketKey
+	// Tests, with their Bucket set, are migrated into the bucket of the destination team with the name of their
+	// bucket, which is created when missing. The shared environments they use are matched by name and created when
+	// missing
+	Tests []*runscope.Test
+	// Delete removes the selected buckets and tests from the source team once they were migrated, provided every
+	// reference could be mapped
+	Delete bool
+}
+
+// Migrate recreates the selected buckets and tests of a team in another team, which may belong to another account.
+// References between the migrated resources are remapped as by Restore, integrations are resolved by type and
+// description and agents by name, the returned mapping lists whatever could not be mapped
+func Migrate(src TeamRef, dst TeamRef, selection *Selection) (*Mapping, error) {
+	team, merged, err := selectTeam(src, selection)
+	if err != nil {
+		return nil, err
+	}
+
+	restorer := &restorer{client: dst.Client, mapping: newMapping(), merged: merged}
+	snapshot := &Snapshot{Version: Version, CreatedAt: time.Now().UTC(), Teams: []*Team{team}}
+	if err = restorer.restore(snapshot, map[string]string{src.TeamID: dst.TeamID}); err != nil {
+		return restorer.mapping, err
+	}
+
+	if !selection.Delete {
+		return restorer.mapping, nil
+	}
+
+	if len(restorer.mapping.Unmapped) > 0 {
+		return restorer.mapping, fmt.Errorf("Error migrating team: %s, %d references could not be mapped, "+
+			"the source was not deleted", src.TeamID, len(restorer.mapping.Unmapped))
+	}
+
+	for _, test := range selection.Tests {
+		if !merged[test.Bucket.Key] {
+			continue
+		}
+
+		if err = src.Client.DeleteTest(test); err != nil {
+			return restorer.mapping, err
+		}
+	}
+
+	for _, key := range selection.Buckets {
+		if err = src.Client.DeleteBucket(key); err != nil {
+			return restorer.mapping, err
+		}
+	}
+
+	return restorer.mapping, nil
+}
+
+// selectTeam reads the selection into a snapshot of the source team, along with the buckets only some tests of
+// were selected
+func selectTeam(src TeamRef, selection *Selection) (*Team, map[runscope.BucketKey]bool, error) {
+	integrations, err := src.Client.ListIntegrations(src.TeamID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	team := &Team{ID: src.TeamID, Integrations: integrations}
+	selected := map[runscope.BucketKey]bool{}
+	for _, key := range selection.Buckets {
+		bucket, err := src.Client.ReadBucket(key)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		bucketSnapshot, err := createBucket(src.Client, bucket, false)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		team.Buckets = append(team.Buckets, bucketSnapshot)
+		selected[key] = true
+	}
+
+	merged := map[runscope.BucketKey]bool{}
+	partial := map[runscope.BucketKey]*Bucket{}
+	for _, test := range selection.Tests {
+		if selected[test.Bucket.Key] {
+			continue
+		}
+
+		bucketSnapshot, ok := partial[test.Bucket.Key]
+		if !ok {
+			bucket, err := src.Client.ReadBucket(test.Bucket.Key)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			environments, err := src.Client.ListSharedEnvironment(bucket)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			bucketSnapshot = &Bucket{Key: bucket.Key, Name: bucket.Name, Environments: environments}
+			partial[bucket.Key] = bucketSnapshot
+			merged[bucket.Key] = true
+			team.Buckets = append(team.Buckets, bucketSnapshot)
+		}
+
+		testSnapshot, err := createTest(src.Client, &runscope.Bucket{Key: bucketSnapshot.Key, Name: bucketSnapshot.Name},
+			test, false)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		bucketSnapshot.Tests = append(bucketSnapshot.Tests, testSnapshot)
+	}
+
+	// only the shared environments the selected tests use are migrated along with them
+	for _, bucketSnapshot := range partial {
+		used := usedEnvironments(bucketSnapshot.Tests)
+		environments := bucketSnapshot.Environments
+		bucketSnapshot.Environments = nil
+		for _, environment := range environments {
+			if used[environment.ID] {
+				bucketSnapshot.Environments = append(bucketSnapshot.Environments, environment)
+			}
+		}
+	}
+
+	return team, merged, nil
+}
+
+// usedEnvironments returns the IDs of the environments tests inherit from, run with by default or are scheduled with
+func usedEnvironments(tests []*Test) map[string]bool {
+	used := map[string]bool{}
+	for _, test := range tests {
+		used[test.Test.DefaultEnvironmentID] = true
+		for _, environment := range test.Test.Environments {
+			used[environment.ParentEnvironmentID] = true
+		}
+
+		for _, schedule := range test.Schedules {
+			used[schedule.EnvironmentID] = true
+		}
+	}
+
+	return used
+}
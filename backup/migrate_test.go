@@ -0,0 +1,69 @@
+package backup
+
+import (
+	"testing"
+
+	"github.com/ewilde/go-runscope"
+)
+
+func (client *fakeClient) ReadBucket(key runscope.BucketKey) (*runscope.Bucket, error) {
+	return &runscope.Bucket{Name: "Production", Key: key}, nil
+}
+
+func (client *fakeClient) DeleteTest(test *runscope.Test) error {
+	client.deleted = append(client.deleted, test.ID)
+	return nil
+}
+
+func (client *fakeClient) DeleteBucket(key runscope.BucketKey) error {
+	client.deleted = append(client.deleted, key.String())
+	return nil
+}
+
+func (client *targetClient) EnsureBucket(team *runscope.Team, name string) (*runscope.Bucket, error) {
+	return &runscope.Bucket{Key: "existing", Name: name, Team: team}, nil
+}
+
+func (client *targetClient) ListSharedEnvironment(bucket *runscope.Bucket) ([]*runscope.Environment, error) {
+	return []*runscope.Environment{{ID: "77", Name: "prod"}}, nil
+}
+
+func TestMigrateTests(t *testing.T) {
+	source, target := &fakeClient{}, newTargetClient()
+	bucket := &runscope.Bucket{Key: "z3n32gktzx94"}
+	selection := &Selection{Tests: []*runscope.Test{{ID: "10", Bucket: bucket}, {ID: "11", Bucket: bucket}}, Delete: true}
+
+	mapping, err := Migrate(TeamRef{Client: source, TeamID: "acme"}, TeamRef{Client: target, TeamID: "other"}, selection)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if mapping.Buckets["z3n32gktzx94"] != "existing" || mapping.Environments["1"] != "77" {
+		t.Errorf("Expected tests to join the existing bucket and its prod environment, actual %#v", mapping)
+	}
+
+	if len(target.environments) != 2 || target.environments[0].ParentEnvironmentID != "77" {
+		t.Errorf("Expected only the test environments to be created, inheriting from 77, actual %v",
+			target.environments)
+	}
+
+	if len(source.deleted) != 2 {
+		t.Errorf("Expected migrated tests to be deleted, actual %v", source.deleted)
+	}
+}
+
+func TestMigrateUnmappedKeepsSource(t *testing.T) {
+	source := &fakeClient{}
+	selection := &Selection{Buckets: []runscope.BucketKey{"z3n32gktzx94"}, Delete: true}
+
+	mapping, err := Migrate(TeamRef{Client: source, TeamID: "acme"}, TeamRef{Client: newTargetClient(), TeamID: "other"},
+		selection)
+	if err == nil {
+		t.Fatal("Expected an error deleting a source with unmapped references")
+	}
+
+	if len(mapping.Unmapped) != 1 || len(source.deleted) != 0 {
+		t.Errorf("Expected subtest 11 to be unmapped and nothing deleted, actual %v, %v",
+			mapping.Unmapped, source.deleted)
+	}
+}
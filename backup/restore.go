@@ -0,0 +1,364 @@
+package backup
+
+import (
+	"fmt"
+
+	"github.com/ewilde/go-runscope"
+)
+
+// Mapping maps the IDs of a snapshot to the IDs of the resources recreated by Restore
+type Mapping struct {
+	Buckets      map[runscope.BucketKey]runscope.BucketKey
+	Tests        map[string]string
+	Environments map[string]string
+	Integrations map[string]string
+	Agents       map[string]string
+	// Unmapped describes the references that could not be remapped, they are kept as is for subtests and dropped
+	// for integrations and agents
+	Unmapped []string
+}
+
+// RestoreOptions of Restore
+type RestoreOptions struct {
+	// Teams maps the team IDs of the snapshot to the teams of the target account, teams that are not mapped are
+	// restored into the team with the same ID
+	Teams map[string]string
+	// AllowRedacted restores a redacted snapshot, its secrets are restored as runscope.RedactedValue
+	AllowRedacted bool
+}
+
+func newMapping() *Mapping {
+	return &Mapping{
+		Buckets:      map[runscope.BucketKey]runscope.BucketKey{},
+		Tests:        map[string]string{},
+		Environments: map[string]string{},
+		Integrations: map[string]string{},
+		Agents:       map[string]string{},
+	}
+}
+
+// restorer recreates the resources of a snapshot, recording their new IDs in mapping
+type restorer struct {
+	client  runscope.ClientAPI
+	mapping *Mapping
+	// merged are the buckets of the snapshot restored into an existing bucket with the same name, reusing its shared
+	// environments by name
+	merged map[runscope.BucketKey]bool
+	// agents are the agents of the target team, listed once an environment references one
+	agents []*runscope.Agent
+	tests  []*restoredTest
+}
+
+// restoredTest is a test of the snapshot along with the test recreated for it
+type restoredTest struct {
+	source *Test
+	test   *runscope.Test
+}
+
+// Restore recreates the buckets of a snapshot, their tests, environments and schedules, in the target account of
+// client. Resources get new IDs, references between them, subtest steps, parent environments, default
+// environments and schedules, are remapped to the new IDs. Integrations are matched by ID or by type and
+// description, agents by ID or by name. Restore stops at the first error, returning the mapping of what was
+// restored so far
+func Restore(client runscope.ClientAPI, snapshot *Snapshot, options *RestoreOptions) (*Mapping, error) {
+	if options == nil {
+		options = &RestoreOptions{}
+	}
+
+	if snapshot.Redacted && !options.AllowRedacted {
+		return nil, fmt.Errorf("Error restoring snapshot: secrets of the snapshot were redacted")
+	}
+
+	restorer := &restorer{client: client, mapping: newMapping()}
+	return restorer.mapping, restorer.restore(snapshot, options.Teams)
+}
+
+func (restorer *restorer) restore(snapshot *Snapshot, teams map[string]string) error {
+	for _, team := range snapshot.Teams {
+		teamID := team.ID
+		if target, ok := teams[team.ID]; ok {
+			teamID = target
+		}
+
+		if err := restorer.restoreTeam(team, teamID); err != nil {
+			return err
+		}
+	}
+
+	// tests are filled in once all of them exist, as subtests may reference tests of any bucket
+	for _, restored := range restorer.tests {
+		if err := restorer.restoreTestContents(restored); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (restorer *restorer) restoreTeam(team *Team, teamID string) error {
+	if len(team.Integrations) > 0 {
+		targets, err := restorer.client.ListIntegrations(teamID)
+		if err != nil {
+			return err
+		}
+
+		for _, integration := range team.Integrations {
+			if target := matchIntegration(integration, targets); target != nil {
+				restorer.mapping.Integrations[integration.ID] = target.ID
+			}
+		}
+	}
+
+	if referencesAgents(team) {
+		agents, err := restorer.client.ListAgents(teamID)
+		if err != nil {
+			return err
+		}
+		restorer.agents = agents
+	}
+
+	for _, bucket := range team.Buckets {
+		if err := restorer.restoreBucket(bucket, teamID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func matchIntegration(integration *runscope.Integration, targets []*runscope.Integration) *runscope.Integration {
+	var match *runscope.Integration
+	for _, target := range targets {
+		if target.ID == integration.ID {
+			return target
+		}
+
+		if target.IntegrationType == integration.IntegrationType && target.Description == integration.Description {
+			if match != nil {
+				return nil
+			}
+			match = target
+		}
+	}
+
+	return match
+}
+
+func matchAgent(machine *runscope.LocalMachine, agents []*runscope.Agent) *runscope.Agent {
+	var match *runscope.Agent
+	for _, agent := range agents {
+		if agent.ID == machine.UUID {
+			return agent
+		}
+
+		if agent.Name == machine.Name {
+			if match != nil {
+				return nil
+			}
+			match = agent
+		}
+	}
+
+	return match
+}
+
+func referencesAgents(team *Team) bool {
+	for _, bucket := range team.Buckets {
+		for _, environment := range bucket.Environments {
+			if len(environment.RemoteAgents) > 0 {
+				return true
+			}
+		}
+
+		for _, test := range bucket.Tests {
+			for _, environment := range test.Test.Environments {
+				if len(environment.RemoteAgents) > 0 {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+func (restorer *restorer) restoreBucket(source *Bucket, teamID string) error {
+	runscope.DebugF(1, "restoring bucket %s", source.Name)
+	team := &runscope.Team{ID: teamID}
+	existing := map[string]*runscope.Environment{}
+	var bucket *runscope.Bucket
+	var err error
+	if restorer.merged[source.Key] {
+		if bucket, err = restorer.client.EnsureBucket(team, source.Name); err != nil {
+			return err
+		}
+
+		environments, err := restorer.client.ListSharedEnvironment(bucket)
+		if err != nil {
+			return err
+		}
+
+		for _, environment := range environments {
+			existing[environment.Name] = environment
+		}
+	} else if bucket, err = restorer.client.CreateBucket(&runscope.Bucket{Name: source.Name, Team: team}); err != nil {
+		return err
+	}
+	restorer.mapping.Buckets[source.Key] = bucket.Key
+
+	for _, environment := range source.Environments {
+		if match, ok := existing[environment.Name]; ok {
+			restorer.mapping.Environments[environment.ID] = match.ID
+			continue
+		}
+
+		created, err := restorer.client.CreateSharedEnvironment(restorer.environment(environment, source.Name), bucket)
+		if err != nil {
+			return err
+		}
+		restorer.mapping.Environments[environment.ID] = created.ID
+	}
+
+	for _, test := range source.Tests {
+		created, err := restorer.client.CreateTest(&runscope.Test{
+			Name: test.Test.Name, Description: test.Test.Description, Bucket: bucket})
+		if err != nil {
+			return err
+		}
+
+		restorer.mapping.Tests[test.Test.ID] = created.ID
+		restorer.tests = append(restorer.tests, &restoredTest{source: test, test: created})
+	}
+
+	return nil
+}
+
+// restoreTestContents recreates the environments, steps and schedules of a test and sets its default environment
+func (restorer *restorer) restoreTestContents(restored *restoredTest) error {
+	client, mapping := restorer.client, restorer.mapping
+	source, test := restored.source.Test, restored.test
+	path := fmt.Sprintf("%s/%s", test.Bucket.Name, test.Name)
+	for _, environment := range source.Environments {
+		created, err := client.CreateTestEnvironment(restorer.environment(environment, path), test)
+		if err != nil {
+			return err
+		}
+		mapping.Environments[environment.ID] = created.ID
+	}
+
+	for _, step := range source.Steps {
+		if _, err := client.CreateTestStep(mapping.step(step, path), test.Bucket.Key, test.ID); err != nil {
+			return err
+		}
+	}
+
+	for _, schedule := range restored.source.Schedules {
+		environmentID, ok := mapping.Environments[schedule.EnvironmentID]
+		if !ok {
+			mapping.unmapped("%s: schedule %s references unknown environment %s", path, schedule.ID, schedule.EnvironmentID)
+			continue
+		}
+
+		newSchedule := &runscope.Schedule{EnvironmentID: environmentID, Interval: schedule.Interval, Note: schedule.Note}
+		if _, err := client.CreateSchedule(newSchedule, test.Bucket.Key, test.ID); err != nil {
+			return err
+		}
+	}
+
+	if environmentID, ok := mapping.Environments[source.DefaultEnvironmentID]; ok {
+		// the test returned by CreateTest has no steps yet, updating it would remove the restored steps
+		current, err := client.ReadTest(test)
+		if err != nil {
+			return err
+		}
+
+		current.DefaultEnvironmentID = environmentID
+		if _, err := client.UpdateTest(current); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// environment returns a copy of an environment of the snapshot without ID, its parent environment, integrations
+// and agents remapped
+func (restorer *restorer) environment(environment *runscope.Environment, path string) *runscope.Environment {
+	mapping := restorer.mapping
+	restored := *environment
+	restored.ID = ""
+	restored.TestID = ""
+	restored.ExportedAt = nil
+
+	if environment.ParentEnvironmentID != "" {
+		if parentID, ok := mapping.Environments[environment.ParentEnvironmentID]; ok {
+			restored.ParentEnvironmentID = parentID
+		} else {
+			mapping.unmapped("%s/%s: parent environment %s was not restored", path, environment.Name,
+				environment.ParentEnvironmentID)
+		}
+	}
+
+	restored.Integrations = nil
+	for _, integration := range environment.Integrations {
+		integrationID, ok := mapping.Integrations[integration.ID]
+		if !ok {
+			mapping.unmapped("%s/%s: integration %s %s has no match in the target team", path, environment.Name,
+				integration.IntegrationType, integration.Description)
+			continue
+		}
+
+		restored.Integrations = append(restored.Integrations, &runscope.EnvironmentIntegration{
+			ID: integrationID, IntegrationType: integration.IntegrationType, Description: integration.Description})
+	}
+
+	restored.RemoteAgents = nil
+	for _, machine := range environment.RemoteAgents {
+		agent := matchAgent(machine, restorer.agents)
+		if agent == nil {
+			mapping.unmapped("%s/%s: agent %s has no match in the target team", path, environment.Name, machine.Name)
+			continue
+		}
+
+		mapping.Agents[machine.UUID] = agent.ID
+		restored.RemoteAgents = append(restored.RemoteAgents, agent.LocalMachine())
+	}
+
+	return &restored
+}
+
+// step returns a copy of a step of the snapshot without ID, the test, bucket and environment of subtests remapped
+func (mapping *Mapping) step(step *runscope.TestStep, path string) *runscope.TestStep {
+	restored := step.Clone()
+	restored.ID = ""
+	mapping.remapSubtests([]*runscope.TestStep{restored}, path)
+	return restored
+}
+
+func (mapping *Mapping) remapSubtests(steps []*runscope.TestStep, path string) {
+	for _, step := range steps {
+		step.ID = ""
+		if step.StepType == runscope.StepTypeSubtest {
+			testID, ok := mapping.Tests[step.TestUUID]
+			if ok {
+				step.TestUUID = testID
+			} else {
+				mapping.unmapped("%s: subtest %s was not restored", path, step.TestUUID)
+			}
+
+			if bucketKey, ok := mapping.Buckets[step.BucketKey]; ok {
+				step.BucketKey = bucketKey
+			}
+
+			if environmentID, ok := mapping.Environments[step.EnvironmentID]; ok {
+				step.EnvironmentID = environmentID
+			}
+		}
+
+		mapping.remapSubtests(step.Steps, path)
+	}
+}
+
+func (mapping *Mapping) unmapped(format string, args ...interface{}) {
+	mapping.Unmapped = append(mapping.Unmapped, fmt.Sprintf(format, args...))
+}
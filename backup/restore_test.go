@@ -0,0 +1,149 @@
+package backup
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ewilde/go-runscope"
+)
+
+// targetClient records the resources restored into it, handing out sequential IDs
+type targetClient struct {
+	runscope.ClientAPI
+	next         int
+	environments []*runscope.Environment
+	steps        []*runscope.TestStep
+	schedules    []*runscope.Schedule
+	updated      []*runscope.Test
+	// testSteps are the steps of the created tests by ID, replaced by UpdateTest like the api does
+	testSteps map[string][]*runscope.TestStep
+}
+
+func newTargetClient() *targetClient {
+	return &targetClient{testSteps: map[string][]*runscope.TestStep{}}
+}
+
+func (client *targetClient) id() string {
+	client.next++
+	return fmt.Sprintf("new-%d", client.next)
+}
+
+func (client *targetClient) ListIntegrations(teamID string) ([]*runscope.Integration, error) {
+	return []*runscope.Integration{{ID: "90", IntegrationType: runscope.IntegrationTypeSlack, Description: "Slack: #oncall"}}, nil
+}
+
+func (client *targetClient) CreateBucket(bucket *runscope.Bucket) (*runscope.Bucket, error) {
+	return &runscope.Bucket{Key: runscope.BucketKey(client.id()), Name: bucket.Name, Team: bucket.Team}, nil
+}
+
+func (client *targetClient) CreateSharedEnvironment(environment *runscope.Environment, bucket *runscope.Bucket) (*runscope.Environment, error) {
+	return client.createEnvironment(environment)
+}
+
+func (client *targetClient) CreateTestEnvironment(environment *runscope.Environment, test *runscope.Test) (*runscope.Environment, error) {
+	return client.createEnvironment(environment)
+}
+
+func (client *targetClient) createEnvironment(environment *runscope.Environment) (*runscope.Environment, error) {
+	created := *environment
+	created.ID = client.id()
+	client.environments = append(client.environments, &created)
+	return &created, nil
+}
+
+func (client *targetClient) CreateTest(test *runscope.Test) (*runscope.Test, error) {
+	return &runscope.Test{ID: client.id(), Name: test.Name, Bucket: test.Bucket}, nil
+}
+
+func (client *targetClient) ReadTest(test *runscope.Test) (*runscope.Test, error) {
+	read := *test
+	read.Steps = client.testSteps[test.ID]
+	return &read, nil
+}
+
+func (client *targetClient) UpdateTest(test *runscope.Test) (*runscope.Test, error) {
+	client.updated = append(client.updated, test)
+	client.testSteps[test.ID] = test.Steps
+	return test, nil
+}
+
+func (client *targetClient) CreateTestStep(step *runscope.TestStep, bucketKey runscope.BucketKey, testID string) (*runscope.TestStep, error) {
+	client.steps = append(client.steps, step)
+	client.testSteps[testID] = append(client.testSteps[testID], step)
+	return step, nil
+}
+
+func (client *targetClient) CreateSchedule(schedule *runscope.Schedule, bucketKey runscope.BucketKey, testID string) (*runscope.Schedule, error) {
+	client.schedules = append(client.schedules, schedule)
+	return schedule, nil
+}
+
+func TestRestore(t *testing.T) {
+	snapshot, err := Create(&fakeClient{}, &Options{Teams: []string{"acme"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a second test, referenced by the subtest step of the first one
+	bucket := snapshot.Teams[0].Buckets[0]
+	bucket.Tests = append(bucket.Tests, &Test{Test: &runscope.Test{ID: "11", Name: "Login"}})
+
+	target := newTargetClient()
+	mapping, err := Restore(target, snapshot, &RestoreOptions{Teams: map[string]string{"acme": "target"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if mapping.Buckets["z3n32gktzx94"] != "new-1" || mapping.Environments["1"] != "new-2" ||
+		mapping.Tests["10"] != "new-3" || mapping.Tests["11"] != "new-4" || mapping.Environments["2"] != "new-5" {
+		t.Fatalf("Expected IDs to be mapped in order of creation, actual %#v", mapping)
+	}
+
+	if mapping.Integrations["30"] != "90" || target.environments[0].Integrations[0].ID != "90" {
+		t.Errorf("Expected integration 30 to be mapped to 90, actual %v", mapping.Integrations)
+	}
+
+	if target.environments[1].ParentEnvironmentID != "new-2" {
+		t.Errorf("Expected parent environment new-2, actual %s", target.environments[1].ParentEnvironmentID)
+	}
+
+	step := target.steps[0]
+	if step.TestUUID != "new-4" || step.BucketKey != "new-1" || step.EnvironmentID != "new-2" {
+		t.Errorf("Expected subtest new-4 in bucket new-1 with environment new-2, actual %#v", step)
+	}
+
+	if target.schedules[0].EnvironmentID != "new-5" || target.updated[0].DefaultEnvironmentID != "new-5" {
+		t.Errorf("Expected schedule and default environment new-5, actual %s, %s",
+			target.schedules[0].EnvironmentID, target.updated[0].DefaultEnvironmentID)
+	}
+
+	if len(target.testSteps["new-3"]) != 1 {
+		t.Errorf("Expected setting the default environment to keep the step, actual %v", target.testSteps["new-3"])
+	}
+
+	if len(mapping.Unmapped) != 0 {
+		t.Errorf("Expected all references to be mapped, actual %v", mapping.Unmapped)
+	}
+}
+
+func TestRestoreReportsUnmapped(t *testing.T) {
+	snapshot, err := Create(&fakeClient{}, &Options{Teams: []string{"acme"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mapping, err := Restore(newTargetClient(), snapshot, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(mapping.Unmapped) != 1 {
+		t.Errorf("Expected subtest 11 to be reported as unmapped, actual %v", mapping.Unmapped)
+	}
+}
+
+func TestRestoreRedacted(t *testing.T) {
+	if _, err := Restore(newTargetClient(), &Snapshot{Redacted: true}, nil); err == nil {
+		t.Error("Expected an error restoring a redacted snapshot")
+	}
+}
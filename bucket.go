@@ -14,20 +14,26 @@ import (
 const (
 	// DefaultPageSize is the max number of items fetched in each request
 	DefaultPageSize = 10
+
+	bucketKeyLength = 12
 )
 
+// BucketKey uniquely identifies a bucket, for example "z3n32gktzx94". Functions taking a bucket key and a test or
+// environment ID can't have them swapped, the IDs of tests, environments and steps are plain uuid strings though
+type BucketKey string
+
 // Bucket resources are a simple way to organize your requests and tests. See https://www.runscope.com/docs/api/buckets and https://www.runscope.com/docs/buckets
 type Bucket struct {
-	Name           string `json:"name,omitempty"`
-	Key            string `json:"key,omitempty"`
-	Default        bool   `json:"default,omitempty"`
-	AuthToken      string `json:"auth_token,omitempty"`
-	TestsURL       string `json:"tests_url,omitempty" mapstructure:"tests_url"`
-	CollectionsURL string `json:"collections_url,omitempty"`
-	MessagesURL    string `json:"messages_url,omitempty"`
-	TriggerURL     string `json:"trigger_url,omitempty"`
-	VerifySsl      bool   `json:"verify_ssl,omitempty"`
-	Team           *Team  `json:"team,omitempty"`
+	Name           string    `json:"name,omitempty"`
+	Key            BucketKey `json:"key,omitempty"`
+	Default        bool      `json:"default,omitempty"`
+	AuthToken      string    `json:"auth_token,omitempty"`
+	TestsURL       string    `json:"tests_url,omitempty" mapstructure:"tests_url"`
+	CollectionsURL string    `json:"collections_url,omitempty"`
+	MessagesURL    string    `json:"messages_url,omitempty"`
+	TriggerURL     string    `json:"trigger_url,omitempty"`
+	VerifySsl      bool      `json:"verify_ssl,omitempty"`
+	Team           *Team     `json:"team,omitempty"`
 }
 
 // CreateBucket creates a new bucket resource. See https://www.runscope.com/docs/api/buckets#bucket-create
@@ -44,6 +50,9 @@ func (client *Client) CreateBucket(bucket *Bucket) (*Bucket, error) {
 		return nil, err
 	}
 
+	token := client.teamToken(bucket.Team.ID)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+
 	DebugF(2, "%#v", req)
 	resp, err := client.HTTP.Do(req)
 	if err != nil {
@@ -68,12 +77,42 @@ func (client *Client) CreateBucket(bucket *Bucket) (*Bucket, error) {
 
 	response := new(response)
 	json.Unmarshal(bodyBytes, &response)
-	return getBucketFromResponse(response.Data)
+	newBucket, err := getBucketFromResponse(response.Data)
+	if err == nil && token != client.AccessToken {
+		client.RouteBucket(newBucket.Key, token)
+	}
+
+	return newBucket, err
+}
+
+// EnsureBucket returns the team's bucket with the given name, creating it if it does not exist. Concurrent calls on the
+// same client will not create duplicate buckets
+func (client *Client) EnsureBucket(team *Team, name string) (*Bucket, error) {
+	client.Lock()
+	defer client.Unlock()
+
+	buckets, err := client.ListBuckets(&ListBucketsInput{TeamID: team.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, bucket := range buckets {
+		if bucket.Name == name {
+			return bucket, nil
+		}
+	}
+
+	return client.CreateBucket(&Bucket{Name: name, Team: team})
 }
 
 // ReadBucket list details about an existing bucket resource. See https://www.runscope.com/docs/api/buckets#bucket-list
-func (client *Client) ReadBucket(key string) (*Bucket, error) {
-	resource, err := client.readResource("bucket", key, fmt.Sprintf("/buckets/%s", key))
+func (client *Client) ReadBucket(key BucketKey) (*Bucket, error) {
+	endpoint, err := bucketEndpoint(key, "")
+	if err != nil {
+		return nil, err
+	}
+
+	resource, err := client.readResource("bucket", key.String(), endpoint)
 	if err != nil {
 		return nil, err
 	}
@@ -83,14 +122,19 @@ func (client *Client) ReadBucket(key string) (*Bucket, error) {
 }
 
 // DeleteBucket deletes a bucket by key. See https://www.runscope.com/docs/api/buckets#bucket-delete
-func (client *Client) DeleteBucket(key string) error {
-	return client.deleteResource("bucket", key, fmt.Sprintf("/buckets/%s", key))
+func (client *Client) DeleteBucket(key BucketKey) error {
+	endpoint, err := bucketEndpoint(key, "")
+	if err != nil {
+		return err
+	}
+
+	return client.deleteResource("bucket", key.String(), endpoint)
 }
 
 // DeleteBuckets deletes all buckets matching the predicate
 func (client *Client) DeleteBuckets(predicate func(bucket *Bucket) bool) error {
 
-	buckets, err := client.ListBuckets()
+	buckets, err := client.ListBuckets(nil)
 	if err != nil {
 		return err
 	}
@@ -104,39 +148,91 @@ func (client *Client) DeleteBuckets(predicate func(bucket *Bucket) bool) error {
 	return nil
 }
 
-// ListBuckets lists all buckets for an account
-func (client *Client) ListBuckets() ([]*Bucket, error) {
-	resource, err := client.readResource("[]bucket", "", "/buckets")
+// ListBucketsInput represents the input to ListBuckets func
+type ListBucketsInput struct {
+	// TeamID when set only returns buckets owned by the team with this UUID
+	TeamID string
+}
+
+// ListBuckets lists all buckets for an account, each annotated with its owning team. Pass nil to list buckets for
+// every team the account belongs to
+func (client *Client) ListBuckets(input *ListBucketsInput) ([]*Bucket, error) {
+	lister := client
+	if input != nil && input.TeamID != "" {
+		lister = client.withToken(client.teamToken(input.TeamID))
+	}
+
+	resource, err := lister.readResource("[]bucket", "", "/buckets")
 	if err != nil {
 		return nil, err
 	}
 
 	buckets, err := getBucketsFromResponse(resource.Data)
-	return buckets, err
+	if err != nil || input == nil || input.TeamID == "" {
+		return buckets, err
+	}
+
+	return filterBucketsByTeam(buckets, input.TeamID), nil
 }
 
 // ListTestsInput represents the input to ListTests func
 type ListTestsInput struct {
-	BucketKey string
+	BucketKey BucketKey
 	Count     int
 	Offset    int
 }
 
+// TestsPage is a single page of tests returned by ListTestsPage
+type TestsPage struct {
+	Tests  []*Test
+	Count  int
+	Offset int
+	// Total is the number of tests in the bucket, zero when the api does not report it
+	Total int
+}
+
+// HasMore reports whether there are more tests after this page
+func (page *TestsPage) HasMore() bool {
+	if page.Total > 0 {
+		return page.Offset+len(page.Tests) < page.Total
+	}
+
+	return len(page.Tests) == page.Count
+}
+
 // ListTests lists some tests given ListTestsInput
 func (client *Client) ListTests(input *ListTestsInput) ([]*Test, error) {
+	page, err := client.ListTestsPage(input)
+	if err != nil {
+		return nil, err
+	}
+
+	return page.Tests, nil
+}
+
+// ListTestsPage lists a page of tests given ListTestsInput, including the total number of tests in the bucket
+func (client *Client) ListTestsPage(input *ListTestsInput) (*TestsPage, error) {
 	count := input.Count
 	if count == 0 {
 		count = DefaultPageSize
 	}
 
-	resource, err := client.readResource("[]test", "",
-		fmt.Sprintf("/buckets/%s/tests?count=%d&offset=%d", input.BucketKey, count, input.Offset))
+	endpoint, err := bucketEndpoint(input.BucketKey, "/tests?count=%d&offset=%d", count, input.Offset)
+	if err != nil {
+		return nil, err
+	}
+
+	resource, err := client.readResource("[]test", "", endpoint)
 	if err != nil {
 		return nil, err
 	}
 
-	tests, err := getTestsFromResponse(resource.Data)
-	return tests, err
+	tests, err := getTestsFromResponse(resource.Data, resource.raw)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TestsPage{Tests: tests, Count: count, Offset: input.Offset, Total: resource.Meta.Total}, nil
 }
 
 // ListAllTests lists all tests for a bucket
@@ -152,13 +248,13 @@ func (client *Client) ListAllTests(input *ListTestsInput) ([]*Test, error) {
 	}
 
 	for cfg.Offset = 0; ; cfg.Offset += cfg.Count {
-		tests, err := client.ListTests(cfg)
+		page, err := client.ListTestsPage(cfg)
 		if err != nil {
 			return allTests, err
 		}
 
-		allTests = append(allTests, tests...)
-		if len(tests) < cfg.Count {
+		allTests = append(allTests, page.Tests...)
+		if !page.HasMore() {
 			return allTests, nil
 		}
 	}
@@ -173,6 +269,45 @@ func (bucket *Bucket) String() string {
 	return string(value)
 }
 
+// Validate checks the key has the length and character set of a runscope bucket key
+func (key BucketKey) Validate() error {
+	if len(key) != bucketKeyLength {
+		return fmt.Errorf("Invalid bucket key %q, expected %d characters got %d", string(key), bucketKeyLength, len(key))
+	}
+
+	for _, c := range key {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') {
+			return fmt.Errorf("Invalid bucket key %q, unexpected character %q", string(key), c)
+		}
+	}
+
+	return nil
+}
+
+func (key BucketKey) String() string {
+	return string(key)
+}
+
+// bucketEndpoint validates the key and builds the path of a resource within the bucket
+func bucketEndpoint(key BucketKey, format string, args ...interface{}) (string, error) {
+	if err := key.Validate(); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("/buckets/%s", key) + fmt.Sprintf(format, args...), nil
+}
+
+func filterBucketsByTeam(buckets []*Bucket, teamID string) []*Bucket {
+	var result []*Bucket
+	for _, bucket := range buckets {
+		if bucket.Team != nil && bucket.Team.ID == teamID {
+			result = append(result, bucket)
+		}
+	}
+
+	return result
+}
+
 func getBucketsFromResponse(response interface{}) ([]*Bucket, error) {
 	var buckets []*Bucket
 	err := decode(&buckets, response)
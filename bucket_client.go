@@ -0,0 +1,65 @@
+package runscope
+
+import "time"
+
+// BucketClient performs operations within a single bucket without passing the bucket into every call
+type BucketClient struct {
+	client *Client
+	bucket *Bucket
+}
+
+// Bucket returns a BucketClient scoped to the bucket identified by key
+func (client *Client) Bucket(key BucketKey) *BucketClient {
+	return &BucketClient{client: client, bucket: &Bucket{Key: key}}
+}
+
+// Key returns the key of the bucket this client is scoped to
+func (bucketClient *BucketClient) Key() BucketKey {
+	return bucketClient.bucket.Key
+}
+
+// Read lists details about the bucket
+func (bucketClient *BucketClient) Read() (*Bucket, error) {
+	return bucketClient.client.ReadBucket(bucketClient.bucket.Key)
+}
+
+// Tests lists all tests in the bucket
+func (bucketClient *BucketClient) Tests() ([]*Test, error) {
+	tests, err := bucketClient.client.ListAllTests(&ListTestsInput{BucketKey: bucketClient.bucket.Key})
+	for _, test := range tests {
+		test.Bucket = bucketClient.bucket
+	}
+
+	return tests, err
+}
+
+// CreateTest creates a new test in the bucket
+func (bucketClient *BucketClient) CreateTest(test *Test) (*Test, error) {
+	test.Bucket = bucketClient.bucket
+	return bucketClient.client.CreateTest(test)
+}
+
+// Environments lists all shared environments in the bucket
+func (bucketClient *BucketClient) Environments() ([]*Environment, error) {
+	return bucketClient.client.ListSharedEnvironment(bucketClient.bucket)
+}
+
+// CreateEnvironment creates a new shared environment in the bucket
+func (bucketClient *BucketClient) CreateEnvironment(environment *Environment) (*Environment, error) {
+	return bucketClient.client.CreateSharedEnvironment(environment, bucketClient.bucket)
+}
+
+// Messages lists the most recent messages captured in the bucket
+func (bucketClient *BucketClient) Messages() ([]*Message, error) {
+	return bucketClient.client.ListMessages(bucketClient.bucket)
+}
+
+// Errors lists captured messages that failed since the given time
+func (bucketClient *BucketClient) Errors(since time.Time) ([]*Message, error) {
+	return bucketClient.client.ListBucketErrors(bucketClient.bucket, since)
+}
+
+// ClearMessages deletes all messages captured in the bucket
+func (bucketClient *BucketClient) ClearMessages() error {
+	return bucketClient.client.ClearMessages(bucketClient.bucket)
+}
@@ -0,0 +1,46 @@
+package runscope
+
+import (
+	"testing"
+)
+
+func TestBucketClient(t *testing.T) {
+	testPreCheck(t)
+	client := clientConfigure()
+	bucket, err := client.CreateBucket(&Bucket{Name: "test", Team: &Team{ID: teamID}})
+	defer client.DeleteBucket(bucket.Key)
+	if err != nil {
+		t.Error(err)
+	}
+
+	bucketClient := client.Bucket(bucket.Key)
+	test, err := bucketClient.CreateTest(&Test{Name: "tf_test", Description: "This is a tf test"})
+	defer client.DeleteTest(test)
+	if err != nil {
+		t.Error(err)
+	}
+
+	tests, err := bucketClient.Tests()
+	if err != nil {
+		t.Error(err)
+	}
+
+	if len(tests) != 1 {
+		t.Fatalf("Expected %d tests, actual %d", 1, len(tests))
+	}
+
+	if tests[0].Bucket.Key != bucket.Key {
+		t.Errorf("Expected bucket key %s, actual %s", bucket.Key, tests[0].Bucket.Key)
+	}
+
+	if _, err = bucketClient.Messages(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestBucketClientInvalidKey(t *testing.T) {
+	client := clientConfigure()
+	if _, err := client.Bucket("bad").Environments(); err == nil {
+		t.Error("Expected validation error for invalid bucket key")
+	}
+}
@@ -0,0 +1,70 @@
+package runscope
+
+import (
+	"sync"
+)
+
+// BucketSummary aggregates the state of every test in a bucket, used for status pages and overviews. ScheduleCount
+// counts every schedule, runscope has no disabled schedules, a schedule runs its test until it is deleted
+type BucketSummary struct {
+	Bucket        *Bucket
+	TestCount     int
+	ScheduleCount int
+	LastRunStatus map[string]string
+	FailingTests  []*Test
+}
+
+// BucketSummary lists all tests in a bucket and fetches their schedules concurrently to build a BucketSummary
+func (client *Client) BucketSummary(bucket *Bucket) (*BucketSummary, error) {
+	tests, err := client.ListAllTests(&ListTestsInput{BucketKey: bucket.Key})
+	if err != nil {
+		return nil, err
+	}
+
+	var mu sync.Mutex
+	schedules := make(map[string][]*Schedule, len(tests))
+	pool := client.NewPool(&PoolOptions{Concurrency: fanOutConcurrency})
+	for _, test := range tests {
+		test := test
+		pool.Submit(func() error {
+			testSchedules, err := client.ListSchedules(bucket.Key, test.ID)
+			if err != nil {
+				return err
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			schedules[test.ID] = testSchedules
+			return nil
+		})
+	}
+
+	if pool.Wait() != nil {
+		return nil, pool.Errors()[0]
+	}
+
+	return newBucketSummary(bucket, tests, schedules), nil
+}
+
+func newBucketSummary(bucket *Bucket, tests []*Test, schedules map[string][]*Schedule) *BucketSummary {
+	summary := &BucketSummary{
+		Bucket:        bucket,
+		TestCount:     len(tests),
+		LastRunStatus: make(map[string]string, len(tests)),
+	}
+
+	for _, test := range tests {
+		summary.ScheduleCount += len(schedules[test.ID])
+
+		if test.LastRun == nil {
+			continue
+		}
+
+		summary.LastRunStatus[test.ID] = test.LastRun.Result()
+		if test.LastRun.Result() == TestResultFail {
+			summary.FailingTests = append(summary.FailingTests, test)
+		}
+	}
+
+	return summary
+}
@@ -0,0 +1,60 @@
+package runscope
+
+import (
+	"testing"
+)
+
+func TestBucketSummary(t *testing.T) {
+	testPreCheck(t)
+	client := clientConfigure()
+	bucket, err := client.CreateBucket(&Bucket{Name: "test", Team: &Team{ID: teamID}})
+	defer client.DeleteBucket(bucket.Key)
+	if err != nil {
+		t.Error(err)
+	}
+
+	test, err := client.CreateTest(&Test{Name: "tf_test", Description: "This is a tf test", Bucket: bucket})
+	defer client.DeleteTest(test)
+	if err != nil {
+		t.Error(err)
+	}
+
+	summary, err := client.BucketSummary(bucket)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if summary.TestCount != 1 {
+		t.Errorf("Expected test count %d, actual %d", 1, summary.TestCount)
+	}
+}
+
+func TestNewBucketSummary(t *testing.T) {
+	tests := []*Test{
+		{ID: "a", LastRun: &TestRun{Status: TestRunStatusCompleted, AssertionCount: 1, AssertionSuccess: 1}},
+		{ID: "b", LastRun: &TestRun{Status: TestRunStatusCompleted, AssertionCount: 1}},
+		{ID: "c"},
+	}
+
+	schedules := map[string][]*Schedule{
+		"a": {{ID: "1"}, {ID: "2"}},
+		"b": {{ID: "3"}},
+	}
+
+	summary := newBucketSummary(&Bucket{}, tests, schedules)
+	if summary.TestCount != 3 {
+		t.Errorf("Expected test count %d, actual %d", 3, summary.TestCount)
+	}
+
+	if summary.ScheduleCount != 3 {
+		t.Errorf("Expected schedule count %d, actual %d", 3, summary.ScheduleCount)
+	}
+
+	if summary.LastRunStatus["a"] != TestResultPass {
+		t.Errorf("Expected status %s, actual %s", TestResultPass, summary.LastRunStatus["a"])
+	}
+
+	if len(summary.FailingTests) != 1 || summary.FailingTests[0].ID != "b" {
+		t.Errorf("Expected failing test %s, actual %v", "b", summary.FailingTests)
+	}
+}
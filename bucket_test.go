@@ -3,6 +3,7 @@ package runscope
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -61,7 +62,7 @@ func TestListBuckets(t *testing.T) {
 		t.Error(err)
 	}
 
-	results, err := client.ListBuckets()
+	results, err := client.ListBuckets(nil)
 
 	if err != nil {
 		t.Error(err)
@@ -74,6 +75,51 @@ func TestListBuckets(t *testing.T) {
 	if len(results) < 2 {
 		t.Errorf("Length of buckets expected more than 1, actual:%v", len(results))
 	}
+
+	teamResults, err := client.ListBuckets(&ListBucketsInput{TeamID: teamID})
+	if err != nil {
+		t.Error(err)
+	}
+
+	for _, result := range teamResults {
+		if result.Team.ID != teamID {
+			t.Errorf("Expected team %s, actual %s", teamID, result.Team.ID)
+		}
+	}
+}
+
+func TestFilterBucketsByTeam(t *testing.T) {
+	buckets := []*Bucket{
+		{Key: "a", Team: &Team{ID: "team-1"}},
+		{Key: "b", Team: &Team{ID: "team-2"}},
+		{Key: "c"},
+	}
+
+	result := filterBucketsByTeam(buckets, "team-2")
+	if len(result) != 1 || result[0].Key != "b" {
+		t.Errorf("Expected bucket %s, actual %v", "b", result)
+	}
+}
+
+func TestEnsureBucket(t *testing.T) {
+	testPreCheck(t)
+	client := clientConfigure()
+	team := &Team{ID: teamID}
+
+	bucket, err := client.EnsureBucket(team, "test-ensure")
+	defer client.DeleteBucket(bucket.Key)
+	if err != nil {
+		t.Error(err)
+	}
+
+	existing, err := client.EnsureBucket(team, "test-ensure")
+	if err != nil {
+		t.Error(err)
+	}
+
+	if existing.Key != bucket.Key {
+		t.Errorf("Expected existing bucket %s, actual %s", bucket.Key, existing.Key)
+	}
 }
 
 func TestListAllTests(t *testing.T) {
@@ -176,3 +222,31 @@ func TestBucketReadFromResponse(t *testing.T) {
 		t.Error("Missing test url")
 	}
 }
+
+func TestBucketKeyValidate(t *testing.T) {
+	valid := []BucketKey{"z3n32gktzx94", "6t0sd3euxlwa"}
+	for _, key := range valid {
+		if err := key.Validate(); err != nil {
+			t.Errorf("Expected key %s to be valid, actual error %s", key, err)
+		}
+	}
+
+	invalid := []BucketKey{"", "foo", "Z3N32GKTZX94", "z3n32gktzx9/", "z3n32gktzx94a"}
+	for _, key := range invalid {
+		if err := key.Validate(); err == nil {
+			t.Errorf("Expected key %q to be invalid", key)
+		}
+	}
+}
+
+func TestReadBucketInvalidKey(t *testing.T) {
+	client := clientConfigure()
+	_, err := client.ReadBucket("not-a-key")
+	if err == nil {
+		t.Fatal("Expected validation error for invalid bucket key")
+	}
+
+	if !strings.Contains(err.Error(), "Invalid bucket key") {
+		t.Errorf("Expected error to contain %s, actual %s", "Invalid bucket key", err.Error())
+	}
+}
@@ -0,0 +1,239 @@
+package runscope
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// BucketEventType is the change a BucketEvent reports
+type BucketEventType string
+
+// Changes reported by WatchBucket
+const (
+	BucketEventCreated BucketEventType = "created"
+	BucketEventUpdated BucketEventType = "updated"
+	BucketEventDeleted BucketEventType = "deleted"
+)
+
+// Kinds of resources reported by WatchBucket
+const (
+	BucketEventTest        = "test"
+	BucketEventEnvironment = "environment"
+	BucketEventSchedule    = "schedule"
+)
+
+// BucketEvent is a change of a test, environment or schedule of a watched bucket
+type BucketEvent struct {
+	Type BucketEventType
+	Kind string
+	ID   string
+	// TestID is the test the environment or schedule belongs to, empty for shared environments
+	TestID string
+	// Previous is the *Test, *Environment or *Schedule before the change, nil when created. Current is the resource
+	// after the change, nil when deleted
+	Previous interface{}
+	Current  interface{}
+}
+
+// bucketState is the state of a bucket at one read of WatchBucket, resources by ID
+type bucketState struct {
+	tests        map[string]*Test
+	environments map[string]*Environment
+	schedules    map[string]*Schedule
+	// scheduleTests are the tests schedules belong to, by schedule ID
+	scheduleTests map[string]string
+}
+
+// WatchBucket reads the tests, environments and schedules of a bucket every interval and sends an event for each
+// resource created, updated or deleted since the previous read, e.g. to react to edits made in the web ui. The first
+// read only records the state of the bucket, its error is returned. Reads that fail later are logged and retried at
+// the next interval. The channel is closed once ctx is done
+func (client *Client) WatchBucket(ctx context.Context, bucket *Bucket, interval time.Duration) (<-chan *BucketEvent, error) {
+	state, err := client.readBucketState(bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan *BucketEvent)
+	go func() {
+		defer close(events)
+		for {
+			timer := time.NewTimer(interval)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+
+			next, err := client.readBucketState(bucket)
+			if err != nil {
+				ErrorF(1, "watching bucket %s: %s", bucket.Key, err)
+				continue
+			}
+
+			for _, event := range state.diff(next) {
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			state = next
+		}
+	}()
+
+	return events, nil
+}
+
+func (client *Client) readBucketState(bucket *Bucket) (*bucketState, error) {
+	state := &bucketState{tests: map[string]*Test{}, environments: map[string]*Environment{},
+		schedules: map[string]*Schedule{}, scheduleTests: map[string]string{}}
+
+	environments, err := client.ListSharedEnvironment(bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, environment := range environments {
+		state.environments[environment.ID] = environment
+	}
+
+	tests, err := client.ListAllTests(&ListTestsInput{BucketKey: bucket.Key})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, test := range tests {
+		test.Bucket = bucket
+		detail, err := client.ReadTest(test)
+		if err != nil {
+			return nil, err
+		}
+		state.tests[detail.ID] = detail
+
+		environments, err := client.ListTestEnvironment(bucket, test)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, environment := range environments {
+			environment.TestID = test.ID
+			state.environments[environment.ID] = environment
+		}
+
+		schedules, err := client.ListSchedules(bucket.Key, test.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, schedule := range schedules {
+			state.schedules[schedule.ID] = schedule
+			state.scheduleTests[schedule.ID] = test.ID
+		}
+	}
+
+	return state, nil
+}
+
+// diff returns the events turning the state into next, tests first, then environments and schedules, each ordered
+// by ID
+func (state *bucketState) diff(next *bucketState) []*BucketEvent {
+	var events []*BucketEvent
+	add := func(event *BucketEvent) {
+		if event != nil {
+			events = append(events, event)
+		}
+	}
+
+	ids := map[string]bool{}
+	for id := range state.tests {
+		ids[id] = true
+	}
+	for id := range next.tests {
+		ids[id] = true
+	}
+
+	for _, id := range sortedKeys(ids) {
+		previous, before := state.tests[id]
+		current, after := next.tests[id]
+		add(newBucketEvent(BucketEventTest, id, "", previous, current, before, after,
+			before && after && previous.Equal(current) && previous.DefaultEnvironmentID == current.DefaultEnvironmentID))
+	}
+
+	ids = map[string]bool{}
+	for id := range state.environments {
+		ids[id] = true
+	}
+	for id := range next.environments {
+		ids[id] = true
+	}
+
+	for _, id := range sortedKeys(ids) {
+		previous, before := state.environments[id]
+		current, after := next.environments[id]
+		var testID string
+		if before {
+			testID = previous.TestID
+		} else {
+			testID = current.TestID
+		}
+
+		add(newBucketEvent(BucketEventEnvironment, id, testID, previous, current, before, after,
+			reflect.DeepEqual(previous.Normalize(), current.Normalize())))
+	}
+
+	ids = map[string]bool{}
+	for id := range state.schedules {
+		ids[id] = true
+	}
+	for id := range next.schedules {
+		ids[id] = true
+	}
+
+	for _, id := range sortedKeys(ids) {
+		previous, before := state.schedules[id]
+		current, after := next.schedules[id]
+		testID := next.scheduleTests[id]
+		if before {
+			testID = state.scheduleTests[id]
+		}
+
+		add(newBucketEvent(BucketEventSchedule, id, testID, previous, current, before, after,
+			before && after && previous.EnvironmentID == current.EnvironmentID &&
+				previous.Interval == current.Interval && previous.Note == current.Note))
+	}
+
+	return events
+}
+
+// newBucketEvent returns the event of a resource that existed before and after a read or not, nil if it is unchanged
+func newBucketEvent(kind string, id string, testID string, previous interface{}, current interface{},
+	before bool, after bool, equal bool) *BucketEvent {
+	event := &BucketEvent{Kind: kind, ID: id, TestID: testID}
+	switch {
+	case !before:
+		event.Type, event.Current = BucketEventCreated, current
+	case !after:
+		event.Type, event.Previous = BucketEventDeleted, previous
+	case !equal:
+		event.Type, event.Previous, event.Current = BucketEventUpdated, previous, current
+	default:
+		return nil
+	}
+
+	return event
+}
+
+func sortedKeys(keys map[string]bool) []string {
+	var sorted []string
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	return sorted
+}
@@ -0,0 +1,99 @@
+package runscope
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBucketStateDiff(t *testing.T) {
+	state := &bucketState{
+		tests: map[string]*Test{
+			"1": {ID: "1", Name: "Health"},
+			"2": {ID: "2", Name: "Login"},
+		},
+		environments: map[string]*Environment{
+			"10": {ID: "10", Name: "prod", InitialVariables: map[string]string{"baseUrl": "https://example.com"}},
+		},
+		schedules:     map[string]*Schedule{"20": {ID: "20", EnvironmentID: "10", Interval: "5m"}},
+		scheduleTests: map[string]string{"20": "1"},
+	}
+
+	next := &bucketState{
+		tests: map[string]*Test{
+			"1": {ID: "1", Name: "Health", LastRun: &TestRun{Status: "completed"}},
+			"3": {ID: "3", Name: "Signup"},
+		},
+		environments: map[string]*Environment{
+			"10": {ID: "10", Name: "prod", InitialVariables: map[string]string{"baseUrl": "https://example.org"}},
+		},
+		schedules:     map[string]*Schedule{"20": {ID: "20", EnvironmentID: "10", Interval: "5m"}},
+		scheduleTests: map[string]string{"20": "1"},
+	}
+
+	events := state.diff(next)
+	expected := []string{"deleted test 2", "created test 3", "updated environment 10"}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected events %v, actual %d events", expected, len(events))
+	}
+
+	for i, event := range events {
+		if actual := fmt.Sprintf("%s %s %s", event.Type, event.Kind, event.ID); actual != expected[i] {
+			t.Errorf("Expected event %s, actual %s", expected[i], actual)
+		}
+	}
+
+	if events[0].Current != nil || events[0].Previous.(*Test).Name != "Login" {
+		t.Errorf("Expected deleted test Login without current state, actual %v", events[0])
+	}
+}
+
+func TestWatchBucket(t *testing.T) {
+	var mu sync.Mutex
+	schedules := `[]`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case "/buckets/z3n32gktzx94/tests":
+			fmt.Fprint(w, `{"data": [{"id": "1", "name": "Health"}]}`)
+		case "/buckets/z3n32gktzx94/tests/1":
+			fmt.Fprint(w, `{"data": {"id": "1", "name": "Health"}}`)
+		case "/buckets/z3n32gktzx94/tests/1/schedules":
+			fmt.Fprintf(w, `{"data": %s}`, schedules)
+		default:
+			fmt.Fprint(w, `{"data": []}`)
+		}
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := NewClient(server.URL, "token")
+	events, err := client.WatchBucket(ctx, &Bucket{Key: "z3n32gktzx94"}, 10*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	schedules = `[{"id": "20", "environment_id": "10", "interval": "1h"}]`
+	mu.Unlock()
+
+	select {
+	case event := <-events:
+		if event.Type != BucketEventCreated || event.Kind != BucketEventSchedule || event.TestID != "1" {
+			t.Errorf("Expected created schedule of test 1, actual %s %s of %s", event.Type, event.Kind, event.TestID)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected an event for the created schedule")
+	}
+
+	cancel()
+	for range events {
+	}
+}
@@ -2,6 +2,7 @@ package runscope
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -12,6 +13,7 @@ import (
 	"io/ioutil"
 	"strings"
 	"sync"
+	"time"
 )
 
 // APIURL is the default runscope api uri
@@ -19,38 +21,95 @@ const APIURL = "https://api.runscope.com"
 
 // ClientAPI interface for mocking data in unit tests
 type ClientAPI interface {
+	AddToken(token string) error
+	AgentInventory(maxAge time.Duration) (*AgentInventory, error)
+	Bucket(key BucketKey) *BucketClient
+	BucketSummary(bucket *Bucket) (*BucketSummary, error)
+	ClearMessages(bucket *Bucket) error
+	ConsecutiveFailures(test *Test, threshold int, perRegion bool) ([]*FailureAlert, error)
+	CopyTest(test *Test, dstBucket *Bucket) (*Test, error)
 	CreateBucket(bucket *Bucket) (*Bucket, error)
-	CreateSchedule(schedule *Schedule, bucketKey string, testID string) (*Schedule, error)
+	CreateSchedule(schedule *Schedule, bucketKey BucketKey, testID string) (*Schedule, error)
 	CreateSharedEnvironment(environment *Environment, bucket *Bucket) (*Environment, error)
+	CreateStep(step Step, bucketKey BucketKey, testID string) (Step, error)
 	CreateTest(test *Test) (*Test, error)
 	CreateTestEnvironment(environment *Environment, test *Test) (*Environment, error)
-	CreateTestStep(testStep *TestStep, bucketKey string, testID string) (*TestStep, error)
-	DeleteBucket(key string) error
+	CreateTestDetail(detail *TestDetail) (*Test, error)
+	CreateTestStep(testStep *TestStep, bucketKey BucketKey, testID string) (*TestStep, error)
+	DeleteBucket(key BucketKey) error
 	DeleteBuckets(predicate func(bucket *Bucket) bool) error
 	DeleteEnvironment(environment *Environment, bucket *Bucket) error
-	DeleteSchedule(schedule *Schedule, bucketKey string, testID string) error
+	DeleteSchedule(schedule *Schedule, bucketKey BucketKey, testID string) error
 	DeleteTest(test *Test) error
-	DeleteTestStep(testStep *TestStep, bucketKey string, testID string) error
-	ListBuckets() ([]*Bucket, error)
+	DeleteTests(bucket *Bucket, filter *DeleteTestsFilter, opts *DeleteTestsOptions) (*DeleteTestsSummary, error)
+	DeleteTestStep(testStep *TestStep, bucketKey BucketKey, testID string) error
+	DuplicateTest(test *Test, newName string) (*Test, error)
+	EnsureBucket(team *Team, name string) (*Bucket, error)
+	FindIntegration(teamID string, integrationType string, description string) (*EnvironmentIntegration, error)
+	FindTestByName(bucket *Bucket, name string, options *FindTestOptions) ([]*Test, error)
+	GenerateFixtures(dir string, options *FixtureOptions) ([]string, error)
+	ImportHAR(reader io.Reader, bucket *Bucket) (*Test, error)
+	ImportOpenAPI(reader io.Reader, bucket *Bucket) (*Test, error)
+	ImportPostman(reader io.Reader, bucket *Bucket) (*Test, error)
+	ImportTest(reader io.Reader, bucket *Bucket) (*Test, error)
+	IntegrationUsage(teamID string, integrationID string) ([]*EnvironmentReference, error)
+	InvitePerson(teamID string, invite *Invite) (*Person, error)
+	IterateResults(test *Test, filter *ResultFilter) *ResultIterator
+	ListAgents(teamID string) ([]*Agent, error)
+	ListBucketErrors(bucket *Bucket, since time.Time) ([]*Message, error)
+	ListBuckets(input *ListBucketsInput) ([]*Bucket, error)
+	ListTeamEnvironments(teamID string) ([]*EnvironmentReference, error)
+	ListTeams() ([]*TeamSummary, error)
 	ListTests(input *ListTestsInput) ([]*Test, error)
+	ListTestSteps(bucketKey BucketKey, testID string) ([]*TestStep, error)
+	LatestResult(test *Test) (*TestRun, error)
 	ListAllTests(input *ListTestsInput) ([]*Test, error)
-	ListSchedules(bucketKey string, testID string) ([]*Schedule, error)
+	ListTestsPage(input *ListTestsInput) (*TestsPage, error)
+	ListResults(test *Test, input *ListResultsInput) ([]*TestResult, error)
+	ListSchedules(bucketKey BucketKey, testID string) ([]*Schedule, error)
 	ListIntegrations(teamID string) ([]*Integration, error)
+	ListMessages(bucket *Bucket) ([]*Message, error)
 	ListPeople(teamID string) ([]*People, error)
 	ListSharedEnvironment(bucket *Bucket) ([]*Environment, error)
 	ListTestEnvironment(bucket *Bucket, test *Test) ([]*Environment, error)
-	ReadBucket(key string) (*Bucket, error)
-	ReadSchedule(schedule *Schedule, bucketKey string, testID string) (*Schedule, error)
+	MoveTest(test *Test, dstBucket *Bucket) (*Test, error)
+	NewPool(options *PoolOptions) *Pool
+	ReadAccount() (*Account, error)
+	ReadBucket(key BucketKey) (*Bucket, error)
+	ReadPerson(teamID string, uuid string) (*Person, error)
+	ReadResult(test *Test, testRunID string) (*TestResult, error)
+	ReadRunRequest(test *Test, testRunID string, requestID string) (*RunRequest, error)
+	ReadSchedule(schedule *Schedule, bucketKey BucketKey, testID string) (*Schedule, error)
 	ReadSharedEnvironment(environment *Environment, bucket *Bucket) (*Environment, error)
 	ReadTest(test *Test) (*Test, error)
+	ReadTestFull(test *Test) (*TestDetail, error)
+	RecentFailures(bucket *Bucket, since time.Time) ([]*RecentFailure, error)
+	RemovePerson(teamID string, uuid string) error
+	RerunResult(test *Test, testRunID string) (*TriggerResult, error)
+	RouteBucket(key BucketKey, token string)
+	RouteTeam(teamID string, token string)
 	ReadTestMetrics(test *Test, input *ReadMetricsInput) (*TestMetric, error)
 	ReadTestEnvironment(environment *Environment, test *Test) (*Environment, error)
-	ReadTestStep(testStep *TestStep, bucketKey string, testID string) (*TestStep, error)
-	UpdateSchedule(schedule *Schedule, bucketKey string, testID string) (*Schedule, error)
+	ReadTestStep(testStep *TestStep, bucketKey BucketKey, testID string) (*TestStep, error)
+	SkipTestStep(testStep *TestStep, bucketKey BucketKey, testID string, reason string) (*TestStep, error)
+	Team(uuid string) *TeamClient
+	TestDependencyGraph(bucket *Bucket) (*TestDependencyGraph, error)
+	TestMetrics(test *Test, opts *ReadMetricsInput) (*TestMetricsSummary, error)
+	TriggerAndWait(ctx context.Context, test *Test, environment *Environment, vars map[string]string) ([]*TestResult, error)
+	TriggerBucket(bucket *Bucket, filter func(test *Test) bool, vars map[string]string) (*TriggerResult, error)
+	TriggerTest(test *Test, environment *Environment, vars map[string]string) (*TriggerResult, error)
+	UnskipTestStep(testStep *TestStep, bucketKey BucketKey, testID string) (*TestStep, error)
+	UpdateSchedule(schedule *Schedule, bucketKey BucketKey, testID string) (*Schedule, error)
 	UpdateSharedEnvironment(environment *Environment, bucket *Bucket) (*Environment, error)
 	UpdateTest(test *Test) (*Test, error)
 	UpdateTestEnvironment(environment *Environment, test *Test) (*Environment, error)
-	UpdateTestStep(testStep *TestStep, bucketKey string, testID string) (*TestStep, error)
+	UpdateTestStep(testStep *TestStep, bucketKey BucketKey, testID string) (*TestStep, error)
+	Uptime(test *Test, from time.Time, to time.Time) (*UptimeReport, error)
+	Usage(teamID string) (*Usage, error)
+	VerifyAgents(teamID string, required []string) (*AgentReport, error)
+	WaitForResult(ctx context.Context, test *Test, testRunID string, opts *PollOptions) (*TestResult, error)
+	WatchBucket(ctx context.Context, bucket *Bucket, interval time.Duration) (<-chan *BucketEvent, error)
+	WatchRun(ctx context.Context, test *Test, testRunID string, fn func(request *RequestResult) error) (*TestResult, error)
 }
 
 // Client provides access to create, read, update and delete runscope resources
@@ -58,6 +117,10 @@ type Client struct {
 	APIURL      string
 	AccessToken string
 	HTTP        *http.Client
+	// ResultCache caches the finished results read by ReadResult and ListResults when set, see NewResultCache
+	ResultCache *ResultCache
+	tokens      tokenRoutes
+	limit       rateLimitState
 	sync.Mutex
 }
 
@@ -71,6 +134,28 @@ type response struct {
 	Meta  metaResponse  `json:"meta"`
 	Data  interface{}   `json:"data"`
 	Error errorResponse `json:"error"`
+	// raw is the json of Data, steps are decoded from it to keep the order of their headers and form parameters
+	raw json.RawMessage
+}
+
+// responseJSON has the fields of response without its json methods
+type responseJSON response
+
+// UnmarshalJSON decodes the response keeping the raw json of its data
+func (response *response) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, (*responseJSON)(response)); err != nil {
+		return err
+	}
+
+	fields := struct {
+		Data json.RawMessage `json:"data"`
+	}{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	response.raw = fields.Data
+	return nil
 }
 
 type errorResponse struct {
@@ -80,6 +165,7 @@ type errorResponse struct {
 
 type metaResponse struct {
 	Status string `json:"status"`
+	Total  int    `json:"total,omitempty"`
 }
 
 // NewClient creates a new client instance
@@ -131,11 +217,13 @@ func (client *Client) createResource(
 	if resp.StatusCode >= 300 {
 		errorResp := new(errorResponse)
 		if err = json.Unmarshal(bodyBytes, &errorResp); err != nil {
-			return nil, fmt.Errorf("Error creating %s: %s", resourceType, resourceName)
+			err = fmt.Errorf("Error creating %s: %s", resourceType, resourceName)
+		} else {
+			err = fmt.Errorf("Error creating %s: %s, status: %d reason: %q", resourceType,
+				resourceName, errorResp.Status, errorResp.ErrorMessage)
 		}
 
-		return nil, fmt.Errorf("Error creating %s: %s, status: %d reason: %q", resourceType,
-			resourceName, errorResp.Status, errorResp.ErrorMessage)
+		return nil, client.rateLimitedError(resp, err)
 	}
 
 	response := new(response)
@@ -170,11 +258,14 @@ func (client *Client) readResource(resourceType string, resourceName string, end
 	if resp.StatusCode >= 300 {
 		errorResp := new(errorResponse)
 		if err = json.Unmarshal(bodyBytes, &errorResp); err != nil {
-			return response, fmt.Errorf("Status: %s Error reading %s: %s",
+			err = fmt.Errorf("Status: %s Error reading %s: %s",
 				resp.Status, resourceType, resourceName)
+		} else {
+			err = fmt.Errorf("Status: %s Error reading %s: %s, reason: %q",
+				resp.Status, resourceType, resourceName, errorResp.ErrorMessage)
 		}
-		return response, fmt.Errorf("Status: %s Error reading %s: %s, reason: %q",
-			resp.Status, resourceType, resourceName, errorResp.ErrorMessage)
+
+		return response, client.rateLimitedError(resp, err)
 	}
 
 	if err = json.Unmarshal(bodyBytes, &response); err != nil {
@@ -210,12 +301,14 @@ func (client *Client) updateResource(resource interface{}, resourceType string,
 	if resp.StatusCode >= 300 {
 		errorResp := new(errorResponse)
 		if err = json.Unmarshal(bodyBytes, &errorResp); err != nil {
-			return &response, fmt.Errorf("Status: %s Error reading %s: %s",
+			err = fmt.Errorf("Status: %s Error reading %s: %s",
 				resp.Status, resourceType, resourceName)
+		} else {
+			err = fmt.Errorf("Status: %s Error reading %s: %s, reason: %q",
+				resp.Status, resourceType, resourceName, errorResp.ErrorMessage)
 		}
 
-		return &response, fmt.Errorf("Status: %s Error reading %s: %s, reason: %q",
-			resp.Status, resourceType, resourceName, errorResp.ErrorMessage)
+		return &response, client.rateLimitedError(resp, err)
 	}
 
 	json.Unmarshal(bodyBytes, &response)
@@ -231,11 +324,11 @@ func (client *Client) deleteResource(resourceType string, resourceName string, e
 
 	DebugF(2, "	request: DELETE %s", endpoint)
 	resp, err := client.HTTP.Do(req)
-	DebugF(2, "	response: %d", resp.StatusCode)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
+	DebugF(2, "	response: %d", resp.StatusCode)
 
 	if resp.StatusCode >= 300 {
 		bodyBytes, _ := ioutil.ReadAll(resp.Body)
@@ -244,12 +337,14 @@ func (client *Client) deleteResource(resourceType string, resourceName string, e
 
 		errorResp := new(errorResponse)
 		if err = json.Unmarshal(bodyBytes, &errorResp); err != nil {
-			return fmt.Errorf("Status: %s Error deleting %s: %s",
+			err = fmt.Errorf("Status: %s Error deleting %s: %s",
 				resp.Status, resourceType, resourceName)
+		} else {
+			err = fmt.Errorf("Status: %s Error deleting %s: %s, reason: %q",
+				resp.Status, resourceType, resourceName, errorResp.ErrorMessage)
 		}
 
-		return fmt.Errorf("Status: %s Error deleting %s: %s, reason: %q",
-			resp.Status, resourceType, resourceName, errorResp.ErrorMessage)
+		return client.rateLimitedError(resp, err)
 	}
 
 	return nil
@@ -269,7 +364,7 @@ func (client *Client) newFormURLEncodedRequest(method string, endpoint string, d
 		return nil, fmt.Errorf("Error during creation of request: %s", err)
 	}
 
-	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", client.AccessToken))
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", client.accessToken(endpoint)))
 	req.Header.Add("Accept", "application/json")
 	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
 
@@ -295,7 +390,7 @@ func (client *Client) newRequest(method string, endpoint string, body []byte) (*
 		return nil, fmt.Errorf("Error during creation of request: %s", err)
 	}
 
-	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", client.AccessToken))
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", client.accessToken(endpoint)))
 	req.Header.Add("Accept", "application/json")
 
 	if method != "GET" {
@@ -0,0 +1,673 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ewilde/go-runscope"
+	"github.com/ewilde/go-runscope/backup"
+	"github.com/ewilde/go-runscope/reconcile"
+)
+
+// parseFlags parses the flags of an action, which must be followed by exactly positional arguments
+func parseFlags(name string, args []string, positional int, configure func(flags *flag.FlagSet)) ([]string, error) {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	configure(flags)
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if flags.NArg() != positional {
+		fmt.Fprintf(flags.Output(), "%s: expected %d arguments, actual %d\n", name, positional, flags.NArg())
+		flags.Usage()
+		return nil, flag.ErrHelp
+	}
+
+	return flags.Args(), nil
+}
+
+func bucketFlag(flags *flag.FlagSet, key *string) {
+	flags.StringVar(key, "bucket", "", "bucket key")
+}
+
+func bucketRows(buckets ...*runscope.Bucket) [][]string {
+	var rows [][]string
+	for _, bucket := range buckets {
+		team := ""
+		if bucket.Team != nil {
+			team = bucket.Team.Name
+		}
+
+		rows = append(rows, []string{bucket.Key.String(), bucket.Name, team})
+	}
+
+	return rows
+}
+
+var bucketHeader = []string{"KEY", "NAME", "TEAM"}
+
+func listBuckets(client *runscope.Client, out *output, args []string) error {
+	var teamID string
+	if _, err := parseFlags("buckets list", args, 0, func(flags *flag.FlagSet) {
+		flags.StringVar(&teamID, "team", "", "only list buckets of the team with this UUID")
+	}); err != nil {
+		return err
+	}
+
+	var input *runscope.ListBucketsInput
+	if teamID != "" {
+		input = &runscope.ListBucketsInput{TeamID: teamID}
+	}
+
+	buckets, err := client.ListBuckets(input)
+	if err != nil {
+		return err
+	}
+
+	return out.print(buckets, bucketHeader, bucketRows(buckets...))
+}
+
+func readBucket(client *runscope.Client, out *output, args []string) error {
+	positional, err := parseFlags("buckets read", args, 1, func(*flag.FlagSet) {})
+	if err != nil {
+		return err
+	}
+
+	bucket, err := client.ReadBucket(runscope.BucketKey(positional[0]))
+	if err != nil {
+		return err
+	}
+
+	return out.print(bucket, bucketHeader, bucketRows(bucket))
+}
+
+func createBucket(client *runscope.Client, out *output, args []string) error {
+	var teamID, name string
+	if _, err := parseFlags("buckets create", args, 0, func(flags *flag.FlagSet) {
+		flags.StringVar(&teamID, "team", "", "UUID of the team owning the bucket")
+		flags.StringVar(&name, "name", "", "name of the bucket")
+	}); err != nil {
+		return err
+	}
+
+	bucket, err := client.CreateBucket(&runscope.Bucket{Name: name, Team: &runscope.Team{ID: teamID}})
+	if err != nil {
+		return err
+	}
+
+	return out.print(bucket, bucketHeader, bucketRows(bucket))
+}
+
+func deleteBucket(client *runscope.Client, out *output, args []string) error {
+	positional, err := parseFlags("buckets delete", args, 1, func(*flag.FlagSet) {})
+	if err != nil {
+		return err
+	}
+
+	return client.DeleteBucket(runscope.BucketKey(positional[0]))
+}
+
+var testHeader = []string{"ID", "NAME", "LAST RUN"}
+
+func testRows(tests ...*runscope.Test) [][]string {
+	var rows [][]string
+	for _, test := range tests {
+		lastRun := ""
+		if test.LastRun != nil {
+			lastRun = test.LastRun.Result()
+		}
+
+		rows = append(rows, []string{test.ID, test.Name, lastRun})
+	}
+
+	return rows
+}
+
+func listTests(client *runscope.Client, out *output, args []string) error {
+	var key string
+	if _, err := parseFlags("tests list", args, 0, func(flags *flag.FlagSet) { bucketFlag(flags, &key) }); err != nil {
+		return err
+	}
+
+	tests, err := client.ListAllTests(&runscope.ListTestsInput{BucketKey: runscope.BucketKey(key)})
+	if err != nil {
+		return err
+	}
+
+	return out.print(tests, testHeader, testRows(tests...))
+}
+
+func readTest(client *runscope.Client, out *output, args []string) error {
+	var key string
+	positional, err := parseFlags("tests read", args, 1, func(flags *flag.FlagSet) { bucketFlag(flags, &key) })
+	if err != nil {
+		return err
+	}
+
+	test, err := client.ReadTest(&runscope.Test{ID: positional[0], Bucket: &runscope.Bucket{Key: runscope.BucketKey(key)}})
+	if err != nil {
+		return err
+	}
+
+	return out.print(test, testHeader, testRows(test))
+}
+
+func createTest(client *runscope.Client, out *output, args []string) error {
+	var key, name, description string
+	if _, err := parseFlags("tests create", args, 0, func(flags *flag.FlagSet) {
+		bucketFlag(flags, &key)
+		flags.StringVar(&name, "name", "", "name of the test")
+		flags.StringVar(&description, "description", "", "description of the test")
+	}); err != nil {
+		return err
+	}
+
+	test, err := client.CreateTest(&runscope.Test{
+		Name: name, Description: description, Bucket: &runscope.Bucket{Key: runscope.BucketKey(key)}})
+	if err != nil {
+		return err
+	}
+
+	return out.print(test, testHeader, testRows(test))
+}
+
+func updateTest(client *runscope.Client, out *output, args []string) error {
+	var key, name, description string
+	var updateFlags *flag.FlagSet
+	positional, err := parseFlags("tests update", args, 1, func(flags *flag.FlagSet) {
+		updateFlags = flags
+		bucketFlag(flags, &key)
+		flags.StringVar(&name, "name", "", "new name of the test")
+		flags.StringVar(&description, "description", "", "new description of the test")
+	})
+	if err != nil {
+		return err
+	}
+
+	set := map[string]bool{}
+	updateFlags.Visit(func(f *flag.Flag) { set[f.Name] = true })
+
+	bucket := &runscope.Bucket{Key: runscope.BucketKey(key)}
+	test, err := client.ReadTest(&runscope.Test{ID: positional[0], Bucket: bucket})
+	if err != nil {
+		return err
+	}
+
+	test.Bucket = bucket
+	if set["name"] {
+		test.Name = name
+	}
+
+	if set["description"] {
+		test.Description = description
+	}
+
+	if test, err = client.UpdateTest(test); err != nil {
+		return err
+	}
+
+	return out.print(test, testHeader, testRows(test))
+}
+
+func deleteTest(client *runscope.Client, out *output, args []string) error {
+	var key string
+	positional, err := parseFlags("tests delete", args, 1, func(flags *flag.FlagSet) { bucketFlag(flags, &key) })
+	if err != nil {
+		return err
+	}
+
+	return client.DeleteTest(&runscope.Test{ID: positional[0], Bucket: &runscope.Bucket{Key: runscope.BucketKey(key)}})
+}
+
+var environmentHeader = []string{"ID", "NAME", "REGIONS"}
+
+func environmentRows(environments ...*runscope.Environment) [][]string {
+	var rows [][]string
+	for _, environment := range environments {
+		rows = append(rows, []string{environment.ID, environment.Name, strings.Join(environment.Regions, ",")})
+	}
+
+	return rows
+}
+
+// environmentFlags parses the flags shared by the environment actions, test is nil for shared environments
+func environmentFlags(name string, args []string, positional int, file *string) (*runscope.Bucket, *runscope.Test, []string, error) {
+	var key, testID string
+	positionalArgs, err := parseFlags(name, args, positional, func(flags *flag.FlagSet) {
+		bucketFlag(flags, &key)
+		flags.StringVar(&testID, "test", "", "test ID of a test environment, shared environments when empty")
+		if file != nil {
+			flags.StringVar(file, "f", "-", "json file of the environment, - for stdin")
+		}
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	bucket := &runscope.Bucket{Key: runscope.BucketKey(key)}
+	if testID == "" {
+		return bucket, nil, positionalArgs, nil
+	}
+
+	return bucket, &runscope.Test{ID: testID, Bucket: bucket}, positionalArgs, nil
+}
+
+func readEnvironmentFile(file string) (*runscope.Environment, error) {
+	var reader io.Reader = os.Stdin
+	if file != "-" {
+		opened, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		defer opened.Close()
+		reader = opened
+	}
+
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	environment := runscope.NewEnvironment()
+	if err := json.Unmarshal(data, environment); err != nil {
+		return nil, fmt.Errorf("Error reading environment %s: %s", file, err)
+	}
+
+	return environment, nil
+}
+
+func listEnvironments(client *runscope.Client, out *output, args []string) error {
+	bucket, test, _, err := environmentFlags("environments list", args, 0, nil)
+	if err != nil {
+		return err
+	}
+
+	var environments []*runscope.Environment
+	if test == nil {
+		environments, err = client.ListSharedEnvironment(bucket)
+	} else {
+		environments, err = client.ListTestEnvironment(bucket, test)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return out.print(environments, environmentHeader, environmentRows(environments...))
+}
+
+func readEnvironment(client *runscope.Client, out *output, args []string) error {
+	bucket, test, positional, err := environmentFlags("environments read", args, 1, nil)
+	if err != nil {
+		return err
+	}
+
+	environment := &runscope.Environment{ID: positional[0]}
+	if test == nil {
+		environment, err = client.ReadSharedEnvironment(environment, bucket)
+	} else {
+		environment, err = client.ReadTestEnvironment(environment, test)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return out.print(environment, environmentHeader, environmentRows(environment))
+}
+
+func createEnvironment(client *runscope.Client, out *output, args []string) error {
+	var file string
+	bucket, test, _, err := environmentFlags("environments create", args, 0, &file)
+	if err != nil {
+		return err
+	}
+
+	environment, err := readEnvironmentFile(file)
+	if err != nil {
+		return err
+	}
+
+	if test == nil {
+		environment, err = client.CreateSharedEnvironment(environment, bucket)
+	} else {
+		environment, err = client.CreateTestEnvironment(environment, test)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return out.print(environment, environmentHeader, environmentRows(environment))
+}
+
+func updateEnvironment(client *runscope.Client, out *output, args []string) error {
+	var file string
+	bucket, test, positional, err := environmentFlags("environments update", args, 1, &file)
+	if err != nil {
+		return err
+	}
+
+	environment, err := readEnvironmentFile(file)
+	if err != nil {
+		return err
+	}
+
+	environment.ID = positional[0]
+	if test == nil {
+		environment, err = client.UpdateSharedEnvironment(environment, bucket)
+	} else {
+		environment, err = client.UpdateTestEnvironment(environment, test)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return out.print(environment, environmentHeader, environmentRows(environment))
+}
+
+func deleteEnvironment(client *runscope.Client, out *output, args []string) error {
+	bucket, test, positional, err := environmentFlags("environments delete", args, 1, nil)
+	if err != nil {
+		return err
+	}
+
+	if test != nil {
+		return fmt.Errorf("deleting test environments is not supported, only shared environments")
+	}
+
+	return client.DeleteEnvironment(&runscope.Environment{ID: positional[0]}, bucket)
+}
+
+func trigger(client *runscope.Client, out *output, args []string) error {
+	var key, environmentID string
+	var wait bool
+	var timeout time.Duration
+	vars := varsFlag{}
+	positional, err := parseFlags("trigger", args, 1, func(flags *flag.FlagSet) {
+		bucketFlag(flags, &key)
+		flags.StringVar(&environmentID, "environment", "", "environment to run, the default environment when empty")
+		flags.Var(vars, "var", "initial variable NAME=VALUE of the run, may be repeated")
+		flags.BoolVar(&wait, "wait", false, "wait for the runs to finish, exit with status 1 when one fails")
+		flags.DurationVar(&timeout, "timeout", 10*time.Minute, "maximum time to wait for the runs")
+	})
+	if err != nil {
+		return err
+	}
+
+	test := &runscope.Test{ID: positional[0], Bucket: &runscope.Bucket{Key: runscope.BucketKey(key)}}
+	var environment *runscope.Environment
+	if environmentID != "" {
+		environment = &runscope.Environment{ID: environmentID}
+	}
+
+	if !wait {
+		triggered, err := client.TriggerTest(test, environment, vars)
+		if err != nil {
+			return err
+		}
+
+		var rows [][]string
+		for _, run := range triggered.Runs {
+			rows = append(rows, []string{run.TestRunID, run.TestName, run.Region, run.Status, run.WebURL()})
+		}
+
+		return out.print(triggered, []string{"RUN", "TEST", "REGION", "STATUS", "URL"}, rows)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	results, err := client.TriggerAndWait(ctx, test, environment, vars)
+	if err != nil {
+		return err
+	}
+
+	var rows [][]string
+	passed := true
+	for _, result := range results {
+		passed = passed && result.Result == runscope.TestResultPass
+		rows = append(rows, []string{result.TestRunID, result.Region, result.Result, result.WebURL()})
+	}
+
+	if err = out.print(results, []string{"RUN", "REGION", "RESULT", "URL"}, rows); err != nil {
+		return err
+	}
+
+	if !passed {
+		return errRunFailed
+	}
+
+	return nil
+}
+
+func exportTest(client *runscope.Client, out *output, args []string) error {
+	var key, format string
+	positional, err := parseFlags("export", args, 1, func(flags *flag.FlagSet) {
+		bucketFlag(flags, &key)
+		flags.StringVar(&format, "format", "export", "format of the output, export or go")
+	})
+	if err != nil {
+		return err
+	}
+
+	test, err := client.ReadTest(&runscope.Test{ID: positional[0], Bucket: &runscope.Bucket{Key: runscope.BucketKey(key)}})
+	if err != nil {
+		return err
+	}
+
+	var export []byte
+	switch format {
+	case "export":
+		export, err = test.Export()
+	case "go":
+		export, err = test.GoTest(nil)
+	default:
+		return fmt.Errorf("unknown export format %q", format)
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(out.writer, string(export))
+	return err
+}
+
+func importTest(client *runscope.Client, out *output, args []string) error {
+	var key, format string
+	positional, err := parseFlags("import", args, 1, func(flags *flag.FlagSet) {
+		bucketFlag(flags, &key)
+		flags.StringVar(&format, "format", "export", "format of the file, export, har, openapi or postman")
+	})
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(positional[0])
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	bucket := &runscope.Bucket{Key: runscope.BucketKey(key)}
+	var test *runscope.Test
+	switch format {
+	case "export":
+		test, err = client.ImportTest(file, bucket)
+	case "har":
+		test, err = client.ImportHAR(file, bucket)
+	case "openapi":
+		test, err = client.ImportOpenAPI(file, bucket)
+	case "postman":
+		test, err = client.ImportPostman(file, bucket)
+	default:
+		return fmt.Errorf("unknown import format %q", format)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return out.print(test, testHeader, testRows(test))
+}
+
+func syncSpecs(client *runscope.Client, out *output, args []string) error {
+	var dryRun bool
+	positional, err := parseFlags("sync", args, 1, func(flags *flag.FlagSet) {
+		flags.BoolVar(&dryRun, "dry-run", false, "print the plan without applying it")
+	})
+	if err != nil {
+		return err
+	}
+
+	specs, err := reconcile.LoadDir(positional[0])
+	if err != nil {
+		return err
+	}
+
+	reconciler := reconcile.NewReconciler(client)
+	reconciler.DryRun = dryRun
+	reconciler.Secrets = reconcile.DefaultSecretProviders()
+	plan, err := reconciler.Reconcile(specs)
+	if plan != nil {
+		var rows [][]string
+		for _, change := range plan.Changes {
+			rows = append(rows, []string{string(change.Action), change.Kind, change.Path})
+		}
+
+		if printErr := out.print(plan.Changes, []string{"ACTION", "KIND", "PATH"}, rows); printErr != nil {
+			return printErr
+		}
+	}
+
+	return err
+}
+
+func detectDrift(client *runscope.Client, out *output, args []string) error {
+	positional, err := parseFlags("drift", args, 1, func(*flag.FlagSet) {})
+	if err != nil {
+		return err
+	}
+
+	specs, err := reconcile.LoadDir(positional[0])
+	if err != nil {
+		return err
+	}
+
+	reconciler := reconcile.NewReconciler(client)
+	reconciler.Secrets = reconcile.DefaultSecretProviders()
+	report, err := reconciler.DetectDrift(specs)
+	if err != nil {
+		return err
+	}
+
+	var rows [][]string
+	for _, drift := range report.Drift {
+		rows = append(rows, []string{drift.Status, drift.Kind, drift.Path, strings.Join(drift.Fields, ",")})
+	}
+
+	if err = out.print(report, []string{"STATUS", "KIND", "PATH", "FIELDS"}, rows); err != nil {
+		return err
+	}
+
+	if report.HasDrift() {
+		return errDrift
+	}
+
+	return nil
+}
+
+// listFlag collects repeated flags
+type listFlag []string
+
+func (list *listFlag) String() string {
+	return strings.Join(*list, ",")
+}
+
+func (list *listFlag) Set(value string) error {
+	*list = append(*list, value)
+	return nil
+}
+
+func backupAccount(client *runscope.Client, out *output, args []string) error {
+	options := &backup.Options{}
+	var teams listFlag
+	positional, err := parseFlags("backup", args, 1, func(flags *flag.FlagSet) {
+		flags.Var(&teams, "team", "UUID of a team to back up, may be repeated, every team when omitted")
+		flags.BoolVar(&options.Redact, "redact", false, "replace secrets of tests and environments")
+	})
+	if err != nil {
+		return err
+	}
+
+	options.Teams = teams
+	snapshot, err := backup.Create(client, options)
+	if err != nil {
+		return err
+	}
+
+	if positional[0] == "-" {
+		return snapshot.Write(out.writer)
+	}
+
+	return snapshot.WriteFile(positional[0])
+}
+
+func restoreAccount(client *runscope.Client, out *output, args []string) error {
+	options := &backup.RestoreOptions{Teams: map[string]string{}}
+	positional, err := parseFlags("restore", args, 1, func(flags *flag.FlagSet) {
+		flags.Var(varsFlag(options.Teams), "team", "SOURCE=TARGET UUIDs restoring a team of the backup into another team")
+		flags.BoolVar(&options.AllowRedacted, "allow-redacted", false, "restore a backup whose secrets were redacted")
+	})
+	if err != nil {
+		return err
+	}
+
+	var snapshot *backup.Snapshot
+	if positional[0] == "-" {
+		snapshot, err = backup.Read(os.Stdin)
+	} else {
+		snapshot, err = backup.ReadFile(positional[0])
+	}
+	if err != nil {
+		return err
+	}
+
+	mapping, err := backup.Restore(client, snapshot, options)
+	if mapping != nil {
+		for _, unmapped := range mapping.Unmapped {
+			fmt.Fprintln(out.writer, unmapped)
+		}
+	}
+
+	return err
+}
+
+func generateFixtures(client *runscope.Client, out *output, args []string) error {
+	var buckets listFlag
+	positional, err := parseFlags("fixtures", args, 1, func(flags *flag.FlagSet) {
+		flags.Var(&buckets, "bucket", "key of a bucket to record, may be repeated, every bucket when omitted")
+	})
+	if err != nil {
+		return err
+	}
+
+	options := &runscope.FixtureOptions{}
+	for _, key := range buckets {
+		options.Buckets = append(options.Buckets, runscope.BucketKey(key))
+	}
+
+	names, err := client.GenerateFixtures(positional[0], options)
+	for _, name := range names {
+		fmt.Fprintln(out.writer, name)
+	}
+
+	return err
+}
@@ -0,0 +1,204 @@
+// Command runscope manages buckets, tests and environments and triggers tests from the command line.
+//
+// Usage:
+//
+//	runscope [-o json|table] [-v] <command> <action> [flags] [arguments]
+//
+// The output is written to stdout, errors and with -v the requests made to the api are logged to stderr. The access
+// token is read from RUNSCOPE_ACCESS_TOKEN, the api url from RUNSCOPE_API_URL which defaults to
+// https://api.runscope.com
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ewilde/go-runscope"
+)
+
+// errRunFailed is returned when a test run awaited by trigger -wait did not pass
+var errRunFailed = errors.New("test run failed")
+
+// errDrift is returned when drift finds resources that don't match their spec
+var errDrift = errors.New("remote state drifted from specs")
+
+type command struct {
+	usage   string
+	actions map[string]action
+}
+
+type action func(client *runscope.Client, out *output, args []string) error
+
+var commands = map[string]*command{
+	"buckets": {
+		usage: "list [-team ID] | read KEY | create -team ID -name NAME | delete KEY",
+		actions: map[string]action{
+			"list": listBuckets, "read": readBucket, "create": createBucket, "delete": deleteBucket,
+		},
+	},
+	"tests": {
+		usage: "list -bucket KEY | read -bucket KEY ID | create -bucket KEY -name NAME [-description TEXT] | " +
+			"update -bucket KEY [-name NAME] [-description TEXT] ID | delete -bucket KEY ID",
+		actions: map[string]action{
+			"list": listTests, "read": readTest, "create": createTest, "update": updateTest, "delete": deleteTest,
+		},
+	},
+	"environments": {
+		usage: "list -bucket KEY [-test ID] | read -bucket KEY [-test ID] ID | " +
+			"create -bucket KEY [-test ID] -f FILE | update -bucket KEY [-test ID] -f FILE ID | delete -bucket KEY ID",
+		actions: map[string]action{
+			"list": listEnvironments, "read": readEnvironment, "create": createEnvironment,
+			"update": updateEnvironment, "delete": deleteEnvironment,
+		},
+	},
+	"trigger": {
+		usage:   "-bucket KEY [-environment ID] [-var NAME=VALUE]... [-wait] [-timeout DURATION] TEST_ID",
+		actions: map[string]action{"": trigger},
+	},
+	"export": {
+		usage:   "-bucket KEY [-format export|go] TEST_ID",
+		actions: map[string]action{"": exportTest},
+	},
+	"backup": {
+		usage:   "[-team ID]... [-redact] FILE",
+		actions: map[string]action{"": backupAccount},
+	},
+	"restore": {
+		usage:   "[-team SOURCE=TARGET]... [-allow-redacted] FILE",
+		actions: map[string]action{"": restoreAccount},
+	},
+	"fixtures": {
+		usage:   "[-bucket KEY]... DIR",
+		actions: map[string]action{"": generateFixtures},
+	},
+	"drift": {
+		usage:   "DIR",
+		actions: map[string]action{"": detectDrift},
+	},
+	"sync": {
+		usage:   "[-dry-run] DIR",
+		actions: map[string]action{"": syncSpecs},
+	},
+	"import": {
+		usage:   "-bucket KEY [-format export|har|openapi|postman] FILE",
+		actions: map[string]action{"": importTest},
+	},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("runscope", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	format := flags.String("o", formatTable, "output format, json or table")
+	verbose := flags.Bool("v", false, "log the requests made to the api to stderr")
+	flags.Usage = func() { usage(stderr) }
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if *format != formatJSON && *format != formatTable {
+		fmt.Fprintf(stderr, "unknown output format %q\n", *format)
+		return 2
+	}
+
+	name, actionName, actionArgs, ok := parseCommand(flags.Args())
+	if !ok {
+		usage(stderr)
+		return 2
+	}
+
+	action, ok := commands[name].actions[actionName]
+	if !ok {
+		fmt.Fprintf(stderr, "usage: runscope %s %s\n", name, commands[name].usage)
+		return 2
+	}
+
+	token := os.Getenv("RUNSCOPE_ACCESS_TOKEN")
+	if token == "" {
+		fmt.Fprintln(stderr, "RUNSCOPE_ACCESS_TOKEN must be set")
+		return 2
+	}
+
+	apiURL := os.Getenv("RUNSCOPE_API_URL")
+	if apiURL == "" {
+		apiURL = runscope.APIURL
+	}
+
+	registerLogHandlers(stderr, *verbose)
+	err := action(runscope.NewClient(apiURL, token), &output{writer: stdout, format: *format}, actionArgs)
+	switch {
+	case err == flag.ErrHelp:
+		return 2
+	case err == errRunFailed, err == errDrift:
+		return 1
+	case err != nil:
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	return 0
+}
+
+// parseCommand splits the arguments into the command, its action for commands that have actions, and the
+// arguments of the action
+func parseCommand(args []string) (string, string, []string, bool) {
+	if len(args) == 0 {
+		return "", "", nil, false
+	}
+
+	command, ok := commands[args[0]]
+	if !ok {
+		return "", "", nil, false
+	}
+
+	if _, single := command.actions[""]; single {
+		return args[0], "", args[1:], true
+	}
+
+	if len(args) < 2 {
+		return args[0], "", nil, true
+	}
+
+	return args[0], args[1], args[2:], true
+}
+
+// registerLogHandlers writes the log lines of the client to stderr, stdout only carries the output so it can be piped,
+// e.g. into jq or a backup file. Debug and info lines are only written when verbose is set
+func registerLogHandlers(stderr io.Writer, verbose bool) {
+	logger := func(prefix string) func(int, string, ...interface{}) {
+		return func(level int, format string, args ...interface{}) {
+			fmt.Fprintf(stderr, "[%s]%s %s\n", prefix, strings.Repeat("\t", level-1), fmt.Sprintf(format, args...))
+		}
+	}
+
+	if verbose {
+		runscope.RegisterLogHandlers(logger("DEBUG"), logger("INFO"), logger("ERROR"))
+		return
+	}
+
+	quiet := func(int, string, ...interface{}) {}
+	runscope.RegisterLogHandlers(quiet, quiet, logger("ERROR"))
+}
+
+func usage(writer io.Writer) {
+	fmt.Fprintln(writer, "usage: runscope [-o json|table] [-v] <command> [action] [flags] [arguments]")
+	fmt.Fprintln(writer)
+
+	var names []string
+	for name := range commands {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(writer, "  %s %s\n", name, commands[name].usage)
+	}
+}
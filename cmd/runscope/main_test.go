@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func runscopeServer() *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/buckets":
+			fmt.Fprint(w, `{"data": [{"name": "Production", "key": "z3n32gktzx94", "team": {"name": "Acme", "id": "1"}}]}`)
+		case "/buckets/z3n32gktzx94/tests/8e7afae4":
+			fmt.Fprint(w, `{"data": {"id": "8e7afae4", "name": "Smoke test", "steps": []}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error": {"status": 404, "error": "not found"}}`)
+		}
+	}))
+
+	os.Setenv("RUNSCOPE_ACCESS_TOKEN", "token")
+	os.Setenv("RUNSCOPE_API_URL", server.URL)
+	return server
+}
+
+func TestRunTable(t *testing.T) {
+	server := runscopeServer()
+	defer server.Close()
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"buckets", "list"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, actual %d: %s", code, stderr.String())
+	}
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 2 || strings.Fields(lines[1])[0] != "z3n32gktzx94" || !strings.HasSuffix(lines[1], "Acme") {
+		t.Errorf("Expected a table with bucket z3n32gktzx94, actual\n%s", stdout.String())
+	}
+}
+
+func TestRunJSON(t *testing.T) {
+	server := runscopeServer()
+	defer server.Close()
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-o", "json", "tests", "read", "-bucket", "z3n32gktzx94", "8e7afae4"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, actual %d: %s", code, stderr.String())
+	}
+
+	test := map[string]interface{}{}
+	if err := json.Unmarshal(stdout.Bytes(), &test); err != nil || test["name"] != "Smoke test" {
+		t.Errorf("Expected test Smoke test as json, actual %s %v", stdout.String(), err)
+	}
+
+	stdout.Reset()
+	if code := run([]string{"tests", "read", "-bucket", "z3n32gktzx94", "missing"}, &stdout, &stderr); code != 1 {
+		t.Errorf("Expected exit code 1 for an api error, actual %d", code)
+	}
+}
+
+func TestRunLogsToStderr(t *testing.T) {
+	server := runscopeServer()
+	defer server.Close()
+
+	for _, verbose := range []bool{false, true} {
+		args := []string{"-o", "json", "buckets", "list"}
+		if verbose {
+			args = append([]string{"-v"}, args...)
+		}
+
+		var stdout, stderr bytes.Buffer
+		if code := run(args, &stdout, &stderr); code != 0 {
+			t.Fatalf("Expected exit code 0, actual %d: %s", code, stderr.String())
+		}
+
+		if !json.Valid(stdout.Bytes()) {
+			t.Errorf("Expected only json on stdout, actual %s", stdout.String())
+		}
+
+		if logged := strings.Contains(stderr.String(), "[DEBUG]"); logged != verbose {
+			t.Errorf("Expected requests to be logged to stderr only with -v, actual %q", stderr.String())
+		}
+	}
+}
+
+func TestRunUsage(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	for _, args := range [][]string{{}, {"pipelines"}, {"buckets", "rename"}, {"-o", "yaml", "buckets", "list"}} {
+		if code := run(args, &stdout, &stderr); code != 2 {
+			t.Errorf("Expected exit code 2 for %v, actual %d", args, code)
+		}
+	}
+}
+
+func TestVarsFlag(t *testing.T) {
+	vars := varsFlag{}
+	if err := vars.Set("baseUrl=https://example.com/?a=b"); err != nil || vars["baseUrl"] != "https://example.com/?a=b" {
+		t.Errorf("Expected variable baseUrl, actual %v %v", vars, err)
+	}
+
+	if err := vars.Set("baseUrl"); err == nil {
+		t.Error("Expected an error for a variable without value")
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// Output formats selected with -o
+const (
+	formatJSON  = "json"
+	formatTable = "table"
+)
+
+type output struct {
+	writer io.Writer
+	format string
+}
+
+// print writes value as indented json, or as a table of header and rows
+func (out *output) print(value interface{}, header []string, rows [][]string) error {
+	if out.format == formatJSON {
+		encoder := json.NewEncoder(out.writer)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(value)
+	}
+
+	table := tabwriter.NewWriter(out.writer, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(table, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(table, strings.Join(row, "\t"))
+	}
+
+	return table.Flush()
+}
+
+// varsFlag collects repeated NAME=VALUE flags
+type varsFlag map[string]string
+
+func (vars varsFlag) String() string {
+	var pairs []string
+	for name, value := range vars {
+		pairs = append(pairs, name+"="+value)
+	}
+
+	return strings.Join(pairs, ",")
+}
+
+func (vars varsFlag) Set(value string) error {
+	parts := strings.SplitN(value, "=", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return fmt.Errorf("expected NAME=VALUE, actual %q", value)
+	}
+
+	vars[parts[0]] = parts[1]
+	return nil
+}
@@ -0,0 +1,157 @@
+package runscope
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Dataset is a list of rows used to generate data-driven steps and tests, each row maps a variable name to its value
+type Dataset []map[string]string
+
+// ReadCSVDataset reads a dataset from csv, the first record names the variable of each column
+func ReadCSVDataset(reader io.Reader) (Dataset, error) {
+	records, err := csv.NewReader(reader).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("Error reading csv dataset: %s", err)
+	}
+
+	if len(records) == 0 {
+		return nil, fmt.Errorf("Error reading csv dataset: missing header record")
+	}
+
+	header := records[0]
+	dataset := make(Dataset, 0, len(records)-1)
+	for _, record := range records[1:] {
+		row := make(map[string]string, len(header))
+		for i, name := range header {
+			row[strings.TrimSpace(name)] = record[i]
+		}
+
+		dataset = append(dataset, row)
+	}
+
+	return dataset, nil
+}
+
+// ReadJSONDataset reads a dataset from a json array of objects, non-string values are formatted with %v
+func ReadJSONDataset(reader io.Reader) (Dataset, error) {
+	var objects []map[string]interface{}
+	if err := json.NewDecoder(reader).Decode(&objects); err != nil {
+		return nil, fmt.Errorf("Error reading json dataset: %s", err)
+	}
+
+	dataset := make(Dataset, 0, len(objects))
+	for _, object := range objects {
+		row := make(map[string]string, len(object))
+		for name, value := range object {
+			if value == nil {
+				row[name] = ""
+				continue
+			}
+
+			row[name] = fmt.Sprintf("%v", value)
+		}
+
+		dataset = append(dataset, row)
+	}
+
+	return dataset, nil
+}
+
+// GenerateSteps returns one copy of the template step per row of the dataset, with every {{name}} placeholder of the
+// row's variables replaced by its value. Placeholders of variables not in the row, e.g. ones extracted by earlier
+// steps, are left in place
+func GenerateSteps(template *TestStep, dataset Dataset) []*TestStep {
+	steps := make([]*TestStep, 0, len(dataset))
+	for _, row := range dataset {
+		steps = append(steps, bindStep(template, rowReplacer(row)))
+	}
+
+	return steps
+}
+
+// GenerateTests returns one copy of the template test per row of the dataset. The steps of each copy are bound to
+// the row as by GenerateSteps and the row becomes the initial variables of its default environment. Placeholders in
+// the template's name are bound as well, when that leaves names equal the row number is appended
+func GenerateTests(template *Test, dataset Dataset) []*Test {
+	tests := make([]*Test, 0, len(dataset))
+	for i, row := range dataset {
+		replacer := rowReplacer(row)
+		test := NewTest()
+		test.Bucket = template.Bucket
+		test.Name = replacer.Replace(template.Name)
+		if test.Name == template.Name {
+			test.Name = fmt.Sprintf("%s #%d", template.Name, i+1)
+		}
+		test.Description = replacer.Replace(template.Description)
+
+		for _, step := range template.Steps {
+			test.Steps = append(test.Steps, bindStep(step, replacer))
+		}
+
+		variables := make(map[string]string, len(row))
+		for name, value := range row {
+			variables[name] = value
+		}
+		test.Environments = []*Environment{{Name: test.Name, InitialVariables: variables}}
+
+		tests = append(tests, test)
+	}
+
+	return tests
+}
+
+func rowReplacer(row map[string]string) *strings.Replacer {
+	pairs := make([]string, 0, len(row)*2)
+	for name, value := range row {
+		pairs = append(pairs, "{{"+name+"}}", value)
+	}
+
+	return strings.NewReplacer(pairs...)
+}
+
+func bindStep(template *TestStep, replacer *strings.Replacer) *TestStep {
+	step := template.Clone()
+	step.ID = ""
+	step.URL = replacer.Replace(step.URL)
+	step.Body = replacer.Replace(step.Body)
+	step.Note = replacer.Replace(step.Note)
+	step.LeftValue = replacer.Replace(step.LeftValue)
+	step.RightValue = replacer.Replace(step.RightValue)
+	bindStrings(step.Scripts, replacer)
+	bindStrings(step.BeforeScripts, replacer)
+
+	for _, header := range step.Headers {
+		bindStrings(header.Values, replacer)
+	}
+
+	for _, field := range step.Form {
+		bindStrings(field.Values, replacer)
+	}
+
+	for name, value := range step.Auth {
+		step.Auth[name] = replacer.Replace(value)
+	}
+
+	for _, assertion := range step.Assertions {
+		assertion.Property = replacer.Replace(assertion.Property)
+		if value, ok := assertion.Value.(string); ok {
+			assertion.Value = replacer.Replace(value)
+		}
+	}
+
+	for i, nested := range template.Steps {
+		step.Steps[i] = bindStep(nested, replacer)
+	}
+
+	return step
+}
+
+func bindStrings(values []string, replacer *strings.Replacer) {
+	for i, value := range values {
+		values[i] = replacer.Replace(value)
+	}
+}
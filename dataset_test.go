@@ -0,0 +1,105 @@
+package runscope
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadCSVDataset(t *testing.T) {
+	dataset, err := ReadCSVDataset(strings.NewReader("tenant, status\nacme,200\nglobex,404\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(dataset) != 2 {
+		t.Fatalf("Expected %d rows, actual %d", 2, len(dataset))
+	}
+
+	if dataset[1]["tenant"] != "globex" || dataset[1]["status"] != "404" {
+		t.Errorf("Expected row globex 404, actual %v", dataset[1])
+	}
+
+	if _, err = ReadCSVDataset(strings.NewReader("")); err == nil {
+		t.Error("Expected error for empty csv")
+	}
+}
+
+func TestReadJSONDataset(t *testing.T) {
+	dataset, err := ReadJSONDataset(strings.NewReader(`[{"tenant": "acme", "status": 200, "region": null}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if dataset[0]["tenant"] != "acme" || dataset[0]["status"] != "200" || dataset[0]["region"] != "" {
+		t.Errorf("Expected row acme 200, actual %v", dataset[0])
+	}
+
+	if _, err = ReadJSONDataset(strings.NewReader(`{"tenant": "acme"}`)); err == nil {
+		t.Error("Expected error for json object")
+	}
+}
+
+func TestGenerateSteps(t *testing.T) {
+	template := &TestStep{
+		StepType:   StepTypeRequest,
+		Method:     "GET",
+		URL:        "https://{{tenant}}.example.com/users/{{user}}",
+		Headers:    Parameters{{Name: "X-Tenant", Values: []string{"{{tenant}}"}}},
+		Assertions: []*Assertion{AssertJSON("tenant").Equals("{{tenant}}")},
+	}
+
+	steps := GenerateSteps(template, Dataset{{"tenant": "acme"}, {"tenant": "globex"}})
+	if len(steps) != 2 {
+		t.Fatalf("Expected %d steps, actual %d", 2, len(steps))
+	}
+
+	if steps[1].URL != "https://globex.example.com/users/{{user}}" {
+		t.Errorf("Expected url %s, actual %s", "https://globex.example.com/users/{{user}}", steps[1].URL)
+	}
+
+	if steps[0].Headers.Get("X-Tenant")[0] != "acme" || steps[0].Assertions[0].Value != "acme" {
+		t.Errorf("Expected header and assertion bound to acme, actual %v %v",
+			steps[0].Headers, steps[0].Assertions[0].Value)
+	}
+
+	if template.Headers.Get("X-Tenant")[0] != "{{tenant}}" || template.Assertions[0].Value != "{{tenant}}" {
+		t.Error("Expected template to be unchanged")
+	}
+}
+
+func TestGenerateTests(t *testing.T) {
+	template := &Test{
+		Name:  "Contract {{tenant}}",
+		Steps: []*TestStep{{StepType: StepTypeRequest, Method: "GET", URL: "https://{{tenant}}.example.com"}},
+	}
+
+	tests := GenerateTests(template, Dataset{{"tenant": "acme"}, {"tenant": "globex"}})
+	if tests[1].Name != "Contract globex" || tests[1].Steps[0].URL != "https://globex.example.com" {
+		t.Errorf("Expected test bound to globex, actual %s %s", tests[1].Name, tests[1].Steps[0].URL)
+	}
+
+	if tests[0].Environments[0].InitialVariables["tenant"] != "acme" {
+		t.Errorf("Expected initial variable tenant acme, actual %v", tests[0].Environments[0].InitialVariables)
+	}
+
+	tests = GenerateTests(&Test{Name: "Contract"}, Dataset{{"tenant": "acme"}, {"tenant": "globex"}})
+	if tests[0].Name != "Contract #1" || tests[1].Name != "Contract #2" {
+		t.Errorf("Expected numbered names, actual %s %s", tests[0].Name, tests[1].Name)
+	}
+}
+
+func TestGenerateStepsSubtest(t *testing.T) {
+	template := &TestStep{ID: "step", StepType: StepTypeSubtest, TestUUID: "checkout", BucketKey: "z3n32gktzx94"}
+	steps := GenerateSteps(template, Dataset{{"tenant": "acme"}, {"tenant": "globex"}})
+
+	if len(steps) != 2 {
+		t.Fatalf("Expected %d steps, actual %d", 2, len(steps))
+	}
+
+	for _, step := range steps {
+		if step.ID != "" || step.TestUUID != "checkout" || step.BucketKey != "z3n32gktzx94" {
+			t.Errorf("Expected subtest step invoking checkout without an id, actual %s %s %s",
+				step.ID, step.TestUUID, step.BucketKey)
+		}
+	}
+}
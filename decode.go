@@ -1,8 +1,10 @@
 package runscope
 
 import (
+	"fmt"
 	"github.com/mitchellh/mapstructure"
 	"reflect"
+	"sort"
 	"time"
 )
 
@@ -27,13 +29,61 @@ func floatToTimeDurationHookFunc() mapstructure.DecodeHookFunc {
 	}
 }
 
+// mapToParametersHookFunc decodes the json objects of a response into Parameters ordered by name, as decoded maps have
+// no order. Steps are decoded from the raw json of responses instead, see getTestStepFromResponse
+func mapToParametersHookFunc() mapstructure.DecodeHookFunc {
+	return func(
+		f reflect.Type,
+		t reflect.Type,
+		data interface{}) (interface{}, error) {
+		if f == nil || f.Kind() != reflect.Map || t != reflect.TypeOf(Parameters{}) {
+			return data, nil
+		}
+
+		return mapToParameters(data)
+	}
+}
+
+func mapToParameters(data interface{}) (interface{}, error) {
+	object, ok := data.(map[string]interface{})
+	if !ok {
+		return data, nil
+	}
+
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parameters := make(Parameters, 0, len(names))
+	for _, name := range names {
+		parameter := &Parameter{Name: name}
+		switch value := object[name].(type) {
+		case []interface{}:
+			for _, item := range value {
+				parameter.Values = append(parameter.Values, fmt.Sprintf("%v", item))
+			}
+		case string:
+			parameter.Values = []string{value}
+		case nil:
+		default:
+			return nil, fmt.Errorf("Error reading parameter %s: unexpected value %v", name, value)
+		}
+
+		parameters = append(parameters, parameter)
+	}
+
+	return parameters, nil
+}
+
 func decode(result interface{}, response interface{}) error {
 
 	config := &mapstructure.DecoderConfig{
 		Metadata:   nil,
 		Result:     result,
 		TagName:    "json",
-		DecodeHook: floatToTimeDurationHookFunc(),
+		DecodeHook: mapstructure.ComposeDecodeHookFunc(floatToTimeDurationHookFunc(), mapToParametersHookFunc()),
 	}
 	decoder, err := mapstructure.NewDecoder(config)
 	if err != nil {
@@ -27,6 +27,18 @@ type Environment struct {
 	Headers             map[string][]string       `json:"headers,omitempty"`
 }
 
+// Notification modes of EmailSettings.NotifyOn
+const (
+	// NotifyOnAll notifies of every run
+	NotifyOnAll = "all"
+	// NotifyOnFailures notifies of failed runs only
+	NotifyOnFailures = "failures"
+	// NotifyOnThreshold notifies once NotifyThreshold runs failed in a row
+	NotifyOnThreshold = "threshold"
+	// NotifyOnSwitch notifies when the result changes from pass to fail or back
+	NotifyOnSwitch = "switch"
+)
+
 // EmailSettings determining how test failures trigger notifications
 type EmailSettings struct {
 	NotifyAll       bool       `json:"notify_all"`
@@ -55,53 +67,171 @@ func NewEnvironment() *Environment {
 
 // CreateSharedEnvironment creates a new shared environment. See https://www.runscope.com/docs/api/environments#create-shared
 func (client *Client) CreateSharedEnvironment(environment *Environment, bucket *Bucket) (*Environment, error) {
-	return client.createEnvironment(environment, fmt.Sprintf("/buckets/%s/environments", bucket.Key))
+	endpoint, error := bucketEndpoint(bucket.Key, "/environments")
+	if error != nil {
+		return nil, error
+	}
+
+	return client.createEnvironment(environment, endpoint)
 }
 
 // CreateTestEnvironment creates a new test environment. See https://www.runscope.com/docs/api/environments#create
 func (client *Client) CreateTestEnvironment(environment *Environment, test *Test) (*Environment, error) {
-	return client.createEnvironment(environment, fmt.Sprintf("/buckets/%s/tests/%s/environments",
-		test.Bucket.Key, test.ID))
+	endpoint, error := bucketEndpoint(test.Bucket.Key, "/tests/%s/environments", test.ID)
+	if error != nil {
+		return nil, error
+	}
+
+	return client.createEnvironment(environment, endpoint)
 }
 
 // ListSharedEnvironment lists all shared environments for a given bucket. See https://www.runscope.com/docs/api/environments#list-shared
 func (client *Client) ListSharedEnvironment(bucket *Bucket) ([]*Environment, error) {
-	return client.listEnvironments(bucket, fmt.Sprintf("/buckets/%s/environments", bucket.Key))
+	endpoint, error := bucketEndpoint(bucket.Key, "/environments")
+	if error != nil {
+		return nil, error
+	}
+
+	return client.listEnvironments(bucket, endpoint)
 }
 
 // ListTestEnvironment lists all tests environments in a given test. See https://api.blazemeter.com/api-monitoring/#test-envrionment-list
 func (client *Client) ListTestEnvironment(bucket *Bucket, test *Test) ([]*Environment, error) {
-	return client.listEnvironments(bucket, fmt.Sprintf("/buckets/%s/tests/%s/environments", bucket.Key, test.ID))
+	endpoint, error := bucketEndpoint(bucket.Key, "/tests/%s/environments", test.ID)
+	if error != nil {
+		return nil, error
+	}
+
+	return client.listEnvironments(bucket, endpoint)
+}
+
+// EnvironmentReference is an environment along with the bucket and test it belongs to, Test is nil for a shared
+// environment
+type EnvironmentReference struct {
+	Bucket      *Bucket
+	Test        *Test
+	Environment *Environment
+}
+
+// ListTeamEnvironments lists the shared and test environments of every bucket of a team, e.g. to find everything
+// referencing an agent or an integration
+func (client *Client) ListTeamEnvironments(teamID string) ([]*EnvironmentReference, error) {
+	buckets, error := client.ListBuckets(&ListBucketsInput{TeamID: teamID})
+	if error != nil {
+		return nil, error
+	}
+
+	var references []*EnvironmentReference
+	for _, bucket := range buckets {
+		shared, error := client.ListSharedEnvironment(bucket)
+		if error != nil {
+			return nil, error
+		}
+
+		for _, environment := range shared {
+			references = append(references, &EnvironmentReference{Bucket: bucket, Environment: environment})
+		}
+
+		tests, error := client.ListAllTests(&ListTestsInput{BucketKey: bucket.Key})
+		if error != nil {
+			return nil, error
+		}
+
+		for _, test := range tests {
+			test.Bucket = bucket
+			environments, error := client.ListTestEnvironment(bucket, test)
+			if error != nil {
+				return nil, error
+			}
+
+			for _, environment := range environments {
+				references = append(references, &EnvironmentReference{Bucket: bucket, Test: test, Environment: environment})
+			}
+		}
+	}
+
+	return references, nil
+}
+
+func (reference *EnvironmentReference) String() string {
+	if reference.Test != nil {
+		return fmt.Sprintf("%s/%s/%s", reference.Bucket.Name, reference.Test.Name, reference.Environment.Name)
+	}
+
+	return fmt.Sprintf("%s/%s", reference.Bucket.Name, reference.Environment.Name)
+}
+
+// AttachIntegration adds a reference to an integration unless the environment already has it
+func (environment *Environment) AttachIntegration(integration *EnvironmentIntegration) {
+	for _, existing := range environment.Integrations {
+		if existing.ID == integration.ID {
+			return
+		}
+	}
+
+	environment.Integrations = append(environment.Integrations, integration)
+}
+
+// DetachIntegration removes the reference to an integration, it reports whether the environment had it
+func (environment *Environment) DetachIntegration(integrationID string) bool {
+	for i, existing := range environment.Integrations {
+		if existing.ID == integrationID {
+			environment.Integrations = append(environment.Integrations[:i:i], environment.Integrations[i+1:]...)
+			return true
+		}
+	}
+
+	return false
 }
 
 // ReadSharedEnvironment lists details about an existing shared environment. See https://www.runscope.com/docs/api/environments#detail
 func (client *Client) ReadSharedEnvironment(environment *Environment, bucket *Bucket) (*Environment, error) {
-	return client.readEnvironment(environment, fmt.Sprintf("/buckets/%s/environments/%s",
-		bucket.Key, environment.ID))
+	endpoint, error := bucketEndpoint(bucket.Key, "/environments/%s", environment.ID)
+	if error != nil {
+		return nil, error
+	}
+
+	return client.readEnvironment(environment, endpoint)
 }
 
 // ReadTestEnvironment lists details about an existing test environment. See https://www.runscope.com/docs/api/environments#detail
 func (client *Client) ReadTestEnvironment(environment *Environment, test *Test) (*Environment, error) {
-	return client.readEnvironment(environment, fmt.Sprintf("/buckets/%s/tests/%s/environments/%s",
-		test.Bucket.Key, test.ID, environment.ID))
+	endpoint, error := bucketEndpoint(test.Bucket.Key, "/tests/%s/environments/%s", test.ID, environment.ID)
+	if error != nil {
+		return nil, error
+	}
+
+	return client.readEnvironment(environment, endpoint)
 }
 
 // UpdateSharedEnvironment updates details about an existing shared environment. See https://www.runscope.com/docs/api/environments#modify
 func (client *Client) UpdateSharedEnvironment(environment *Environment, bucket *Bucket) (*Environment, error) {
-	return client.updateEnvironment(environment,
-		fmt.Sprintf("/buckets/%s/environments/%s", bucket.Key, environment.ID))
+	endpoint, error := bucketEndpoint(bucket.Key, "/environments/%s", environment.ID)
+	if error != nil {
+		return nil, error
+	}
+
+	return client.updateEnvironment(environment, endpoint)
 }
 
 // UpdateTestEnvironment updates details about an existing test environment. See https://www.runscope.com/docs/api/environments#modify
 func (client *Client) UpdateTestEnvironment(environment *Environment, test *Test) (*Environment, error) {
-	return client.updateEnvironment(environment,
-		fmt.Sprintf("/buckets/%s/tests/%s/environments/%s", test.Bucket.Key, test.ID, environment.ID))
+	endpoint, error := bucketEndpoint(test.Bucket.Key, "/tests/%s/environments/%s", test.ID, environment.ID)
+	if error != nil {
+		return nil, error
+	}
+
+	return client.updateEnvironment(environment, endpoint)
 }
 
 // DeleteEnvironment deletes an existing shared environment. https://www.runscope.com/docs/api/environments#delete
 func (client *Client) DeleteEnvironment(environment *Environment, bucket *Bucket) error {
-	return client.deleteResource("environment", environment.ID,
-		fmt.Sprintf("/buckets/%s/environments/%s", bucket.Key, environment.ID))
+	endpoint, error := bucketEndpoint(bucket.Key, "/environments/%s", environment.ID)
+	if error != nil {
+		return error
+	}
+
+	return client.deleteResource("environment", environment.ID, endpoint)
 }
 
 func (environment *Environment) String() string {
@@ -128,7 +258,7 @@ func (client *Client) createEnvironment(environment *Environment, endpoint strin
 }
 
 func (client *Client) listEnvironments(bucket *Bucket, endpoint string) ([]*Environment, error) {
-	resource, error := client.readResource("environments", bucket.Key, endpoint)
+	resource, error := client.readResource("environments", bucket.Key.String(), endpoint)
 	if error != nil {
 		return nil, error
 	}
@@ -0,0 +1,280 @@
+package runscope
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// FixtureOptions of GenerateFixtures
+type FixtureOptions struct {
+	// Buckets limits the fixtures to the buckets with these keys, every bucket of the account when empty
+	Buckets []BucketKey
+}
+
+// fixtureSecretFields are fields whose values are replaced by RedactedValue besides those with credential names
+var fixtureSecretFields = map[string]bool{
+	"trigger_url":        true,
+	"client_certificate": true,
+	"email":              true,
+}
+
+// fixtureSortFields identify the items of a list response, by the first of them an item has
+var fixtureSortFields = []string{"id", "key", "uuid", "agent_id", "name"}
+
+// GenerateFixtures reads the account, the integrations and agents of its teams, and the buckets with their tests,
+// environments and schedules, writing every api response into dir as a json fixture. Fixtures are sanitized:
+// credentials, trigger urls and emails are replaced by RedactedValue, keys are sorted and list responses ordered
+// by ID, so regenerating them only shows changes of the api. Requests use Client.AccessToken, tokens routed with
+// RouteTeam or RouteBucket are ignored. Returns the names of the written files, see FixtureHandler to serve them
+func (client *Client) GenerateFixtures(dir string, options *FixtureOptions) ([]string, error) {
+	if options == nil {
+		options = &FixtureOptions{}
+	}
+
+	apiURL, err := url.Parse(client.APIURL)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := client.HTTP.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	recorder := &fixtureRecorder{transport: transport, prefix: apiURL.Path, responses: map[string][]byte{}}
+	recording := &Client{APIURL: client.APIURL, AccessToken: client.AccessToken,
+		HTTP: &http.Client{Transport: recorder, Timeout: client.HTTP.Timeout}}
+
+	if err = recording.readFixtures(options); err != nil {
+		return nil, err
+	}
+
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for name, body := range recorder.responses {
+		sanitized, err := sanitizeFixture(body)
+		if err != nil {
+			return nil, fmt.Errorf("Error sanitizing fixture: %s, %s", name, err)
+		}
+
+		if err = ioutil.WriteFile(filepath.Join(dir, name), sanitized, 0644); err != nil {
+			return nil, err
+		}
+
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names, nil
+}
+
+func (client *Client) readFixtures(options *FixtureOptions) error {
+	account, err := client.ReadAccount()
+	if err != nil {
+		return err
+	}
+
+	for _, team := range account.Teams {
+		if _, err = client.ListIntegrations(team.ID); err != nil {
+			return err
+		}
+
+		if _, err = client.ListAgents(team.ID); err != nil {
+			return err
+		}
+	}
+
+	var buckets []*Bucket
+	if len(options.Buckets) == 0 {
+		if buckets, err = client.ListBuckets(nil); err != nil {
+			return err
+		}
+	}
+
+	for _, key := range options.Buckets {
+		bucket, err := client.ReadBucket(key)
+		if err != nil {
+			return err
+		}
+
+		buckets = append(buckets, bucket)
+	}
+
+	for _, bucket := range buckets {
+		if _, err = client.ListSharedEnvironment(bucket); err != nil {
+			return err
+		}
+
+		tests, err := client.ListAllTests(&ListTestsInput{BucketKey: bucket.Key})
+		if err != nil {
+			return err
+		}
+
+		for _, test := range tests {
+			test.Bucket = bucket
+			if _, err = client.ReadTest(test); err != nil {
+				return err
+			}
+
+			if _, err = client.ListTestEnvironment(bucket, test); err != nil {
+				return err
+			}
+
+			if _, err = client.ListSchedules(bucket.Key, test.ID); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// FixtureHandler serves the fixtures GenerateFixtures wrote into dir, answering GET requests for the endpoints they
+// were recorded from and 404 otherwise, e.g. as the handler of an httptest.Server a Client is pointed at
+func FixtureHandler(dir string) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		var body []byte
+		err := fmt.Errorf("no fixture for %s %s", request.Method, request.URL)
+		if request.Method == http.MethodGet {
+			body, err = ioutil.ReadFile(filepath.Join(dir, fixtureName(request.URL.Path, request.URL.Query())))
+		}
+
+		writer.Header().Set("Content-Type", "application/json")
+		if err != nil {
+			writer.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(writer).Encode(&response{
+				Meta:  metaResponse{Status: "error"},
+				Error: errorResponse{Status: http.StatusNotFound, ErrorMessage: err.Error()}})
+			return
+		}
+
+		writer.Write(body)
+	})
+}
+
+// fixtureRecorder keeps the bodies of the successful GET responses by fixture name
+type fixtureRecorder struct {
+	transport http.RoundTripper
+	// prefix is the path of the api url, stripped from the recorded endpoints
+	prefix    string
+	mu        sync.Mutex
+	responses map[string][]byte
+}
+
+func (recorder *fixtureRecorder) RoundTrip(request *http.Request) (*http.Response, error) {
+	resp, err := recorder.transport.RoundTrip(request)
+	if err != nil || request.Method != http.MethodGet || resp.StatusCode >= 300 {
+		return resp, err
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	name := fixtureName(strings.TrimPrefix(request.URL.Path, recorder.prefix), request.URL.Query())
+	recorder.mu.Lock()
+	recorder.responses[name] = body
+	recorder.mu.Unlock()
+
+	return resp, nil
+}
+
+// fixtureName returns the file name of the fixture of an endpoint, e.g. buckets_z3n32gktzx94_tests_count-50.json
+func fixtureName(path string, query url.Values) string {
+	name := strings.Replace(strings.Trim(path, "/"), "/", "_", -1)
+	var parameters []string
+	for key, values := range query {
+		for _, value := range values {
+			parameters = append(parameters, key+"-"+value)
+		}
+	}
+
+	sort.Strings(parameters)
+	if len(parameters) > 0 {
+		name += "_" + strings.Join(parameters, "_")
+	}
+
+	return name + ".json"
+}
+
+func sanitizeFixture(body []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	value = sanitizeFixtureValue("", value)
+	if object, ok := value.(map[string]interface{}); ok {
+		if items, ok := object["data"].([]interface{}); ok {
+			sort.SliceStable(items, func(i, j int) bool {
+				return fixtureSortKey(items[i]) < fixtureSortKey(items[j])
+			})
+		}
+	}
+
+	sanitized, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(sanitized, '\n'), nil
+}
+
+// sanitizeFixtureValue replaces the secrets of a decoded json value, key is the name of the field holding it
+func sanitizeFixtureValue(key string, value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for name, field := range typed {
+			typed[name] = sanitizeFixtureValue(name, field)
+		}
+	case []interface{}:
+		for i, item := range typed {
+			typed[i] = sanitizeFixtureValue(key, item)
+		}
+	case string:
+		lower := strings.ToLower(key)
+		if (fixtureSecretFields[lower] || credentialHeaders[lower] || isCredentialName(key)) &&
+			typed != "" && !isTemplated(typed) {
+			return RedactedValue
+		}
+
+		if lower == "url" || strings.HasSuffix(lower, "_url") {
+			return redactURL(typed)
+		}
+	}
+
+	return value
+}
+
+func fixtureSortKey(item interface{}) string {
+	object, ok := item.(map[string]interface{})
+	if !ok {
+		return fmt.Sprint(item)
+	}
+
+	for _, field := range fixtureSortFields {
+		if value, ok := object[field]; ok {
+			return fmt.Sprint(value)
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,77 @@
+package runscope
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerateFixtures(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/account":
+			fmt.Fprint(w, `{"data": {"email": "jane@example.com", "teams": [{"name": "Acme", "id": "acme"}]}}`)
+		case "/buckets/z3n32gktzx94":
+			fmt.Fprint(w, `{"data": {"name": "Production", "key": "z3n32gktzx94",
+				"trigger_url": "https://api.runscope.com/radar/bucket/f2f4dbbb/trigger"}}`)
+		case "/buckets/z3n32gktzx94/tests":
+			fmt.Fprint(w, `{"data": [{"id": "b", "name": "Login"}, {"id": "a", "name": "Health"}]}`)
+		case "/buckets/z3n32gktzx94/tests/a", "/buckets/z3n32gktzx94/tests/b":
+			fmt.Fprint(w, `{"data": {"id": "a", "name": "Health", "steps": [{"step_type": "request",
+				"url": "https://api.example.com/health?api_key=abc123",
+				"headers": {"Authorization": ["Bearer abc123"], "Accept": ["application/json"]}}]}}`)
+		default:
+			fmt.Fprint(w, `{"data": []}`)
+		}
+	}))
+	defer api.Close()
+
+	dir, err := ioutil.TempDir("", "fixtures")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names, err := NewClient(api.URL, "token").GenerateFixtures(dir, &FixtureOptions{Buckets: []BucketKey{"z3n32gktzx94"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(names) != 12 || names[0] != "account.json" {
+		t.Errorf("Expected 12 fixtures starting with account.json, actual %v", names)
+	}
+
+	tests, _ := ioutil.ReadFile(filepath.Join(dir, "buckets_z3n32gktzx94_tests_count-50_offset-0.json"))
+	if strings.Index(string(tests), `"a"`) > strings.Index(string(tests), `"b"`) {
+		t.Errorf("Expected tests to be ordered by ID, actual %s", tests)
+	}
+
+	for _, name := range names {
+		fixture, _ := ioutil.ReadFile(filepath.Join(dir, name))
+		for _, secret := range []string{"abc123", "jane@example.com", "f2f4dbbb"} {
+			if strings.Contains(string(fixture), secret) {
+				t.Errorf("Expected %s to be redacted from %s, actual %s", secret, name, fixture)
+			}
+		}
+	}
+
+	fixtures := httptest.NewServer(FixtureHandler(dir))
+	defer fixtures.Close()
+
+	client := NewClient(fixtures.URL, "token")
+	test, err := client.ReadTest(&Test{ID: "a", Bucket: &Bucket{Key: "z3n32gktzx94"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if value := test.Steps[0].Headers.Get("Authorization")[0]; value != RedactedValue {
+		t.Errorf("Expected Authorization %s, actual %s", RedactedValue, value)
+	}
+
+	if _, err = client.ReadBucket("6t0sd3euxlwa"); err == nil {
+		t.Error("Expected an error reading a bucket without fixture")
+	}
+}
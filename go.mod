@@ -5,4 +5,5 @@ go 1.15
 require (
 	github.com/hashicorp/go-cleanhttp v0.0.0-20170211013415-3573b8b52aa7
 	github.com/mitchellh/mapstructure v0.0.0-20161211222315-bfdb1a85537d
+	gopkg.in/yaml.v2 v2.4.0
 )
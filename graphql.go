@@ -0,0 +1,27 @@
+package runscope
+
+import (
+	"encoding/json"
+)
+
+// GraphQLRequest is the body of a GraphQL request, variable values may use {{name}} placeholders as strings
+type GraphQLRequest struct {
+	Query         string                 `json:"query"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+	OperationName string                 `json:"operationName,omitempty"`
+}
+
+// GraphQL starts building a request step that POSTs the GraphQL request to url as json. The step asserts the response
+// status is 200 and that the response has no errors, since GraphQL servers report failed queries with a 200 status
+// and an errors array
+func GraphQL(url string, request *GraphQLRequest) *RequestBuilder {
+	builder := POST(url).
+		Header("Content-Type", "application/json").
+		Header("Accept", "application/json").
+		AssertStatus(200).
+		Assert(AssertJSON("errors").IsEmpty())
+
+	// a struct of strings and json values always marshals
+	body, _ := json.Marshal(request)
+	return builder.Body(string(body))
+}
@@ -0,0 +1,45 @@
+package runscope
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestGraphQL(t *testing.T) {
+	query := `query User($id: ID!) {
+  user(id: $id) {
+    name
+  }
+}`
+	step := GraphQL("https://api.example.com/graphql", &GraphQLRequest{
+		Query:         query,
+		Variables:     map[string]interface{}{"id": "{{userId}}"},
+		OperationName: "User",
+	}).Assert(AssertJSON("data.user.name").IsNotEmpty()).TestStep()
+
+	if step.Method != "POST" || step.Headers.Get("Content-Type")[0] != "application/json" {
+		t.Errorf("Expected json POST, actual %s %v", step.Method, step.Headers)
+	}
+
+	body := new(GraphQLRequest)
+	if err := json.Unmarshal([]byte(step.Body), body); err != nil {
+		t.Fatal(err)
+	}
+
+	if body.Query != query || body.Variables["id"] != "{{userId}}" || body.OperationName != "User" {
+		t.Errorf("Expected query, variables and operation name in body, actual %s", step.Body)
+	}
+
+	if len(step.Assertions) != 3 {
+		t.Fatalf("Expected %d assertions, actual %d", 3, len(step.Assertions))
+	}
+
+	errors := step.Assertions[1]
+	if errors.Source != AssertionSourceResponseJSON || errors.Property != "errors" || errors.Comparison != ComparisonEmpty {
+		t.Errorf("Expected empty errors assertion, actual %#v", errors)
+	}
+
+	if err := step.validate(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,89 @@
+package runscope
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+type harLog struct {
+	Log struct {
+		Pages []struct {
+			Title string `json:"title"`
+		} `json:"pages"`
+		Entries []struct {
+			Request struct {
+				Method  string `json:"method"`
+				URL     string `json:"url"`
+				Headers []struct {
+					Name  string `json:"name"`
+					Value string `json:"value"`
+				} `json:"headers"`
+				PostData *struct {
+					MimeType string `json:"mimeType"`
+					Text     string `json:"text"`
+				} `json:"postData"`
+			} `json:"request"`
+			Response struct {
+				Status int `json:"status"`
+			} `json:"response"`
+		} `json:"entries"`
+	} `json:"log"`
+}
+
+// ConvertHAR converts a HAR capture, as saved from browser developer tools or a recording proxy, into a test with one
+// request step per entry in recorded order. Each step keeps the recorded headers and body and asserts the recorded
+// response status.
+func ConvertHAR(reader io.Reader) (*Test, error) {
+	har := new(harLog)
+	if err := json.NewDecoder(reader).Decode(har); err != nil {
+		return nil, fmt.Errorf("Error reading HAR: %s", err)
+	}
+
+	test := NewTest()
+	test.Name = "Imported from HAR"
+	if len(har.Log.Pages) > 0 && har.Log.Pages[0].Title != "" {
+		test.Name = har.Log.Pages[0].Title
+	}
+
+	for _, entry := range har.Log.Entries {
+		step := NewTestStep()
+		step.StepType = StepTypeRequest
+		step.Method = strings.ToUpper(entry.Request.Method)
+		step.URL = entry.Request.URL
+
+		for _, header := range entry.Request.Headers {
+			// pseudo headers are http/2 framing and content length is recalculated when the step runs
+			if strings.HasPrefix(header.Name, ":") || http.CanonicalHeaderKey(header.Name) == "Content-Length" {
+				continue
+			}
+
+			step.Headers.Add(header.Name, header.Value)
+		}
+
+		if entry.Request.PostData != nil && step.Method != "GET" {
+			step.Body = entry.Request.PostData.Text
+		}
+
+		if entry.Response.Status > 0 {
+			step.Assertions = []*Assertion{AssertStatus().EqualsNumber(entry.Response.Status)}
+		}
+
+		test.Steps = append(test.Steps, step)
+	}
+
+	return test, nil
+}
+
+// ImportHAR converts a HAR capture with ConvertHAR and creates the resulting test in the bucket
+func (client *Client) ImportHAR(reader io.Reader, bucket *Bucket) (*Test, error) {
+	test, err := ConvertHAR(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	test.Bucket = bucket
+	return client.importTest(test)
+}
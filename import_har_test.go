@@ -0,0 +1,53 @@
+package runscope
+
+import (
+	"os"
+	"testing"
+)
+
+func TestConvertHAR(t *testing.T) {
+	file, err := os.Open("testdata/capture.har")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	test, err := ConvertHAR(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if test.Name != "Checkout flow" {
+		t.Errorf("Expected name %s, actual %s", "Checkout flow", test.Name)
+	}
+
+	if len(test.Steps) != 2 {
+		t.Fatalf("Expected %d steps, actual %d", 2, len(test.Steps))
+	}
+
+	cart := test.Steps[0]
+	if cart.Headers.Has(":authority") {
+		t.Error("Expected pseudo header to be skipped")
+	}
+
+	if cart.Headers.Get("Accept")[0] != "application/json" {
+		t.Errorf("Expected accept header, actual %v", cart.Headers)
+	}
+
+	checkout := test.Steps[1]
+	if checkout.Method != "POST" {
+		t.Errorf("Expected method %s, actual %s", "POST", checkout.Method)
+	}
+
+	if checkout.Headers.Has("content-length") {
+		t.Error("Expected content length header to be skipped")
+	}
+
+	if checkout.Body != `{"cart": 12}` {
+		t.Errorf("Expected body %s, actual %s", `{"cart": 12}`, checkout.Body)
+	}
+
+	if checkout.Assertions[0].Value != 500 {
+		t.Errorf("Expected status assertion %d, actual %v", 500, checkout.Assertions[0].Value)
+	}
+}
@@ -0,0 +1,327 @@
+package runscope
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+var openAPIMethods = map[string]bool{
+	"get": true, "put": true, "post": true, "delete": true, "options": true, "head": true, "patch": true,
+}
+
+type openAPIDocument struct {
+	Swagger string `json:"swagger"`
+	OpenAPI string `json:"openapi"`
+	Info    struct {
+		Title       string `json:"title"`
+		Description string `json:"description"`
+	} `json:"info"`
+	Host     string   `json:"host"`
+	BasePath string   `json:"basePath"`
+	Schemes  []string `json:"schemes"`
+	Servers  []struct {
+		URL string `json:"url"`
+	} `json:"servers"`
+	Paths map[string]map[string]json.RawMessage `json:"paths"`
+}
+
+type openAPIOperation struct {
+	OperationID string             `json:"operationId"`
+	Summary     string             `json:"summary"`
+	Parameters  []openAPIParameter `json:"parameters"`
+	RequestBody *struct {
+		Content map[string]openAPIMediaType `json:"content"`
+	} `json:"requestBody"`
+	Responses map[string]openAPIResponse `json:"responses"`
+}
+
+type openAPIParameter struct {
+	Name     string      `json:"name"`
+	In       string      `json:"in"`
+	Required bool        `json:"required"`
+	Example  interface{} `json:"example"`
+	Default  interface{} `json:"default"`
+	Schema   *struct {
+		Example interface{} `json:"example"`
+	} `json:"schema"`
+}
+
+type openAPIResponse struct {
+	Examples map[string]interface{}      `json:"examples"`
+	Content  map[string]openAPIMediaType `json:"content"`
+}
+
+type openAPIMediaType struct {
+	Example interface{} `json:"example"`
+}
+
+// ConvertOpenAPI converts an OpenAPI 2.0 or 3.0 json or yaml document into a test with one request step per
+// operation. Path, query and header parameters become variables in the test's default environment, the first
+// successful response code and the top level keys of its json example become assertions.
+func ConvertOpenAPI(reader io.Reader) (*Test, error) {
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading OpenAPI document: %s", err)
+	}
+
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		// the document is decoded from json, which yaml is converted to
+		var value interface{}
+		if err = yaml.Unmarshal(data, &value); err != nil {
+			return nil, fmt.Errorf("Error reading OpenAPI document: %s", err)
+		}
+
+		if data, err = json.Marshal(yamlToJSON(value)); err != nil {
+			return nil, fmt.Errorf("Error reading OpenAPI document: %s", err)
+		}
+	}
+
+	document := new(openAPIDocument)
+	if err = json.Unmarshal(data, document); err != nil {
+		return nil, fmt.Errorf("Error reading OpenAPI document: %s", err)
+	}
+
+	if document.Swagger == "" && document.OpenAPI == "" {
+		return nil, fmt.Errorf("Error reading OpenAPI document: missing 'swagger' or 'openapi' version")
+	}
+
+	variables := map[string]string{"baseUrl": document.baseURL()}
+	test := NewTest()
+	test.Name = document.Info.Title
+	test.Description = document.Info.Description
+
+	paths := make([]string, 0, len(document.Paths))
+	for path := range document.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		item := document.Paths[path]
+		var shared []openAPIParameter
+		if raw, ok := item["parameters"]; ok {
+			if err := json.Unmarshal(raw, &shared); err != nil {
+				return nil, fmt.Errorf("Error reading OpenAPI parameters of %s: %s", path, err)
+			}
+		}
+
+		methods := make([]string, 0, len(item))
+		for method := range item {
+			if openAPIMethods[method] {
+				methods = append(methods, method)
+			}
+		}
+		sort.Strings(methods)
+
+		for _, method := range methods {
+			operation := new(openAPIOperation)
+			if err := json.Unmarshal(item[method], operation); err != nil {
+				return nil, fmt.Errorf("Error reading OpenAPI operation %s %s: %s", method, path, err)
+			}
+
+			operation.Parameters = append(append([]openAPIParameter{}, shared...), operation.Parameters...)
+			test.Steps = append(test.Steps, convertOpenAPIOperation(strings.ToUpper(method), path, operation, variables))
+		}
+	}
+
+	test.Environments = []*Environment{{Name: "Imported from OpenAPI", InitialVariables: variables}}
+	return test, nil
+}
+
+// ImportOpenAPI converts an OpenAPI document with ConvertOpenAPI and creates the resulting test in the bucket
+func (client *Client) ImportOpenAPI(reader io.Reader, bucket *Bucket) (*Test, error) {
+	test, err := ConvertOpenAPI(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	test.Bucket = bucket
+	return client.importTest(test)
+}
+
+// importTest creates a converted test along with its steps and environments
+func (client *Client) importTest(test *Test) (*Test, error) {
+	newTest, err := client.CreateTest(&Test{Name: test.Name, Description: test.Description, Bucket: test.Bucket})
+	if err != nil {
+		return nil, err
+	}
+
+	// converted environments have no ID yet, they get one to map the default environment, the first environment
+	// unless the test names another one, as exported tests do
+	defaultEnvironmentID := test.DefaultEnvironmentID
+	environments := make([]*Environment, len(test.Environments))
+	for i, environment := range test.Environments {
+		imported := *environment
+		if imported.ID == "" {
+			imported.ID = fmt.Sprintf("imported-%d", i)
+		}
+
+		if i == 0 && defaultEnvironmentID == "" {
+			defaultEnvironmentID = imported.ID
+		}
+		environments[i] = &imported
+	}
+
+	source := &Test{Steps: test.Steps, DefaultEnvironmentID: defaultEnvironmentID}
+	if err = client.copyTestContents(source, newTest, environments, nil); err != nil {
+		client.DeleteTest(newTest)
+		return nil, err
+	}
+
+	return client.ReadTest(newTest)
+}
+
+// yamlToJSON converts the maps decoded from yaml, which may have keys of any type, e.g. response codes, to json
+// objects
+func yamlToJSON(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[interface{}]interface{}:
+		object := make(map[string]interface{}, len(value))
+		for key, nested := range value {
+			object[fmt.Sprint(key)] = yamlToJSON(nested)
+		}
+		return object
+	case []interface{}:
+		array := make([]interface{}, len(value))
+		for i, nested := range value {
+			array[i] = yamlToJSON(nested)
+		}
+		return array
+	default:
+		return value
+	}
+}
+
+func (document *openAPIDocument) baseURL() string {
+	if len(document.Servers) > 0 {
+		return strings.TrimSuffix(document.Servers[0].URL, "/")
+	}
+
+	scheme := "https"
+	if len(document.Schemes) > 0 {
+		scheme = document.Schemes[0]
+	}
+
+	return strings.TrimSuffix(fmt.Sprintf("%s://%s%s", scheme, document.Host, document.BasePath), "/")
+}
+
+func convertOpenAPIOperation(method string, path string, operation *openAPIOperation, variables map[string]string) *TestStep {
+	step := NewTestStep()
+	step.StepType = StepTypeRequest
+	step.Method = method
+	step.Note = strings.TrimSpace(fmt.Sprintf("%s %s %s", method, path, operation.Summary))
+
+	url := "{{baseUrl}}" + path
+	var query []string
+	for _, parameter := range operation.Parameters {
+		if parameter.Name == "" {
+			continue
+		}
+
+		if _, ok := variables[parameter.Name]; !ok {
+			variables[parameter.Name] = parameter.exampleValue()
+		}
+
+		placeholder := fmt.Sprintf("{{%s}}", parameter.Name)
+		switch parameter.In {
+		case "path":
+			url = strings.Replace(url, fmt.Sprintf("{%s}", parameter.Name), placeholder, -1)
+		case "query":
+			if parameter.Required {
+				query = append(query, fmt.Sprintf("%s=%s", parameter.Name, placeholder))
+			}
+		case "header":
+			step.Headers.Set(parameter.Name, placeholder)
+		case "body":
+			if method != "GET" && parameter.Schema != nil && parameter.Schema.Example != nil {
+				step.setJSONBody(parameter.Schema.Example)
+			}
+		}
+	}
+
+	if len(query) > 0 {
+		url = url + "?" + strings.Join(query, "&")
+	}
+	step.URL = url
+
+	if method != "GET" && operation.RequestBody != nil {
+		if media, ok := operation.RequestBody.Content["application/json"]; ok && media.Example != nil {
+			step.setJSONBody(media.Example)
+		}
+	}
+
+	step.Assertions = openAPIAssertions(operation.Responses)
+	return step
+}
+
+func (step *TestStep) setJSONBody(example interface{}) {
+	body, err := json.Marshal(example)
+	if err != nil {
+		return
+	}
+
+	step.Headers.Set("Content-Type", "application/json")
+	step.Body = string(body)
+}
+
+func (parameter *openAPIParameter) exampleValue() string {
+	value := parameter.Example
+	if value == nil && parameter.Schema != nil {
+		value = parameter.Schema.Example
+	}
+	if value == nil {
+		value = parameter.Default
+	}
+	if value == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%v", value)
+}
+
+func openAPIAssertions(responses map[string]openAPIResponse) []*Assertion {
+	codes := make([]string, 0, len(responses))
+	for code := range responses {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	for _, code := range codes {
+		status, err := strconv.Atoi(code)
+		if err != nil || status < 200 || status >= 300 {
+			continue
+		}
+
+		assertions := []*Assertion{AssertStatus().EqualsNumber(status)}
+		example := responses[code].Examples["application/json"]
+		if media, ok := responses[code].Content["application/json"]; ok && media.Example != nil {
+			example = media.Example
+		}
+
+		if object, ok := example.(map[string]interface{}); ok {
+			keys := make([]string, 0, len(object))
+			for key, value := range object {
+				if value != nil {
+					keys = append(keys, key)
+				}
+			}
+			sort.Strings(keys)
+
+			for _, key := range keys {
+				assertions = append(assertions, AssertJSON(key).IsNotEmpty())
+			}
+		}
+
+		return assertions
+	}
+
+	return nil
+}
@@ -0,0 +1,214 @@
+package runscope
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestConvertOpenAPI3(t *testing.T) {
+	file, err := os.Open("testdata/petstore-openapi3.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	test, err := ConvertOpenAPI(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if test.Name != "Petstore" {
+		t.Errorf("Expected name %s, actual %s", "Petstore", test.Name)
+	}
+
+	if len(test.Steps) != 3 {
+		t.Fatalf("Expected %d steps, actual %d", 3, len(test.Steps))
+	}
+
+	list := test.Steps[0]
+	if list.Method != "GET" || list.URL != "{{baseUrl}}/pets?limit={{limit}}" {
+		t.Errorf("Expected GET {{baseUrl}}/pets?limit={{limit}}, actual %s %s", list.Method, list.URL)
+	}
+
+	if len(list.Assertions) != 2 {
+		t.Errorf("Expected %d assertions, actual %d", 2, len(list.Assertions))
+	}
+
+	create := test.Steps[1]
+	if create.Body != `{"name":"Rex"}` {
+		t.Errorf("Expected body %s, actual %s", `{"name":"Rex"}`, create.Body)
+	}
+
+	if create.Assertions[0].Value != 201 {
+		t.Errorf("Expected status assertion %d, actual %v", 201, create.Assertions[0].Value)
+	}
+
+	show := test.Steps[2]
+	if show.URL != "{{baseUrl}}/pets/{{petId}}" {
+		t.Errorf("Expected url %s, actual %s", "{{baseUrl}}/pets/{{petId}}", show.URL)
+	}
+
+	if show.Headers.Get("X-Request-ID")[0] != "{{X-Request-ID}}" {
+		t.Errorf("Expected header variable, actual %v", show.Headers)
+	}
+
+	variables := test.Environments[0].InitialVariables
+	if variables["baseUrl"] != "https://petstore.example.com/v1" {
+		t.Errorf("Expected baseUrl %s, actual %s", "https://petstore.example.com/v1", variables["baseUrl"])
+	}
+
+	if variables["petId"] != "42" || variables["limit"] != "10" {
+		t.Errorf("Expected example values for variables, actual %v", variables)
+	}
+
+	for _, step := range test.Steps {
+		if err = step.validate(); err != nil {
+			t.Errorf("Expected converted step to be valid, actual %s", err)
+		}
+	}
+}
+
+func TestConvertOpenAPIYAML(t *testing.T) {
+	var converted []string
+	for _, name := range []string{"testdata/petstore-openapi3.json", "testdata/petstore-openapi3.yaml"} {
+		file, err := os.Open(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer file.Close()
+
+		test, err := ConvertOpenAPI(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		data, err := json.Marshal(test)
+		if err != nil {
+			t.Fatal(err)
+		}
+		converted = append(converted, string(data))
+	}
+
+	if converted[0] != converted[1] {
+		t.Errorf("Expected the yaml document to convert like the json one\n%s\n%s", converted[0], converted[1])
+	}
+}
+
+func TestConvertSwagger2(t *testing.T) {
+	file, err := os.Open("testdata/petstore-swagger2.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	test, err := ConvertOpenAPI(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	variables := test.Environments[0].InitialVariables
+	if variables["baseUrl"] != "http://petstore.example.com/v1" {
+		t.Errorf("Expected baseUrl %s, actual %s", "http://petstore.example.com/v1", variables["baseUrl"])
+	}
+
+	if variables["petId"] != "1" {
+		t.Errorf("Expected petId %s, actual %s", "1", variables["petId"])
+	}
+
+	if len(test.Steps[0].Assertions) != 2 {
+		t.Errorf("Expected %d assertions, actual %d", 2, len(test.Steps[0].Assertions))
+	}
+}
+
+func TestConvertOpenAPIMissingVersion(t *testing.T) {
+	if _, err := ConvertOpenAPI(strings.NewReader(`{"paths": {}}`)); err == nil {
+		t.Error("Expected error for document without version")
+	}
+}
+
+func TestImportOpenAPI(t *testing.T) {
+	testPreCheck(t)
+	client := clientConfigure()
+	bucket, err := client.CreateBucket(&Bucket{Name: "test", Team: &Team{ID: teamID}})
+	defer client.DeleteBucket(bucket.Key)
+	if err != nil {
+		t.Error(err)
+	}
+
+	file, err := os.Open("testdata/petstore-openapi3.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	test, err := client.ImportOpenAPI(file, bucket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.DeleteTest(test)
+
+	if len(test.Steps) != 3 {
+		t.Errorf("Expected %d steps, actual %d", 3, len(test.Steps))
+	}
+}
+
+// importServer fakes the api creating imported tests, recording the last update of the test
+type importServer struct {
+	*httptest.Server
+	mu           sync.Mutex
+	environments int
+	steps        int
+	updated      *Test
+}
+
+func newImportServer() *importServer {
+	server := &importServer{}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server.mu.Lock()
+		defer server.mu.Unlock()
+		body, _ := ioutil.ReadAll(r.Body)
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/buckets/z3n32gktzx94/tests":
+			fmt.Fprint(w, `{"data": {"id": "1", "name": "Petstore"}}`)
+		case r.Method == "POST" && r.URL.Path == "/buckets/z3n32gktzx94/tests/1/environments":
+			server.environments++
+			fmt.Fprintf(w, `{"data": {"id": "env-%d"}}`, server.environments)
+		case r.Method == "PUT":
+			server.updated = &Test{}
+			json.Unmarshal(body, server.updated)
+			fmt.Fprintf(w, `{"data": %s}`, body)
+		case r.Method == "POST" && strings.HasSuffix(r.URL.Path, "/steps"):
+			server.steps++
+			fmt.Fprint(w, `{"data": [{"id": "step"}]}`)
+		case r.Method == "POST":
+			fmt.Fprint(w, `{"data": {"id": "schedule"}}`)
+		default:
+			steps := strings.TrimSuffix(strings.Repeat(`{"id": "step", "step_type": "request"},`, server.steps), ",")
+			fmt.Fprintf(w, `{"data": {"id": "1", "name": "Petstore", "steps": [%s]}}`, steps)
+		}
+	}))
+
+	return server
+}
+
+func TestImportTestDefaultEnvironment(t *testing.T) {
+	server := newImportServer()
+	defer server.Close()
+
+	test := &Test{Name: "Petstore", Bucket: &Bucket{Key: "z3n32gktzx94"},
+		Environments: []*Environment{{Name: "production"}, {Name: "staging"}}}
+	if _, err := NewClient(server.URL, "token").importTest(test); err != nil {
+		t.Fatal(err)
+	}
+
+	if server.updated == nil || server.updated.DefaultEnvironmentID != "env-1" {
+		t.Errorf("Expected default environment %s, actual %v", "env-1", server.updated)
+	}
+}
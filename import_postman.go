@@ -0,0 +1,252 @@
+package runscope
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// postmanScriptReplacer translates the parts of the Postman scripting api that have a runscope equivalent
+var postmanScriptReplacer = strings.NewReplacer(
+	"pm.environment.get(", "variables.get(",
+	"pm.environment.set(", "variables.set(",
+	"pm.collectionVariables.get(", "variables.get(",
+	"pm.collectionVariables.set(", "variables.set(",
+	"pm.variables.get(", "variables.get(",
+	"pm.variables.set(", "variables.set(",
+	"postman.getEnvironmentVariable(", "variables.get(",
+	"postman.setEnvironmentVariable(", "variables.set(",
+)
+
+type postmanCollection struct {
+	Info struct {
+		Name        string          `json:"name"`
+		Description json.RawMessage `json:"description"`
+		Schema      string          `json:"schema"`
+	} `json:"info"`
+	Item     []*postmanItem     `json:"item"`
+	Variable []*postmanKeyValue `json:"variable"`
+}
+
+type postmanItem struct {
+	Name    string          `json:"name"`
+	Item    []*postmanItem  `json:"item"`
+	Request *postmanRequest `json:"request"`
+	Event   []struct {
+		Listen string `json:"listen"`
+		Script struct {
+			Exec []string `json:"exec"`
+		} `json:"script"`
+	} `json:"event"`
+}
+
+type postmanRequest struct {
+	Method string             `json:"method"`
+	Header []*postmanKeyValue `json:"header"`
+	URL    json.RawMessage    `json:"url"`
+	Body   *struct {
+		Mode       string             `json:"mode"`
+		Raw        string             `json:"raw"`
+		URLEncoded []*postmanKeyValue `json:"urlencoded"`
+	} `json:"body"`
+}
+
+type postmanKeyValue struct {
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	Disabled bool   `json:"disabled"`
+}
+
+// ConvertPostman converts a Postman Collection v2.1 into a test with one request step per request, folders are
+// flattened in order. Collection variables become the initial variables of the test's default environment, and
+// pre-request and test scripts are kept when they only use the parts of the Postman api that runscope supports.
+func ConvertPostman(reader io.Reader) (*Test, error) {
+	collection := new(postmanCollection)
+	if err := json.NewDecoder(reader).Decode(collection); err != nil {
+		return nil, fmt.Errorf("Error reading Postman collection: %s", err)
+	}
+
+	if !strings.Contains(collection.Info.Schema, "v2.1") {
+		return nil, fmt.Errorf("Error reading Postman collection: unsupported schema %q, expected v2.1",
+			collection.Info.Schema)
+	}
+
+	test := NewTest()
+	test.Name = collection.Info.Name
+	description, err := postmanDescription(collection.Info.Description)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading Postman collection description: %s", err)
+	}
+	test.Description = description
+
+	steps, err := convertPostmanItems(collection.Item, "")
+	if err != nil {
+		return nil, err
+	}
+	test.Steps = steps
+
+	variables := map[string]string{}
+	for _, variable := range collection.Variable {
+		if !variable.Disabled {
+			variables[variable.Key] = variable.Value
+		}
+	}
+	test.Environments = []*Environment{{Name: "Imported from Postman", InitialVariables: variables}}
+
+	return test, nil
+}
+
+// ImportPostman converts a Postman collection with ConvertPostman and creates the resulting test in the bucket
+func (client *Client) ImportPostman(reader io.Reader, bucket *Bucket) (*Test, error) {
+	test, err := ConvertPostman(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	test.Bucket = bucket
+	return client.importTest(test)
+}
+
+func convertPostmanItems(items []*postmanItem, folder string) ([]*TestStep, error) {
+	var steps []*TestStep
+	for _, item := range items {
+		name := item.Name
+		if folder != "" {
+			name = folder + " / " + item.Name
+		}
+
+		if item.Request == nil {
+			nested, err := convertPostmanItems(item.Item, name)
+			if err != nil {
+				return nil, err
+			}
+
+			steps = append(steps, nested...)
+			continue
+		}
+
+		step, err := convertPostmanItem(item, name)
+		if err != nil {
+			return nil, err
+		}
+
+		steps = append(steps, step)
+	}
+
+	return steps, nil
+}
+
+func convertPostmanItem(item *postmanItem, name string) (*TestStep, error) {
+	request := item.Request
+	step := NewTestStep()
+	step.StepType = StepTypeRequest
+	step.Note = name
+	step.Method = strings.ToUpper(request.Method)
+	if step.Method == "" {
+		step.Method = "GET"
+	}
+
+	rawURL, err := postmanURL(request.URL)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading Postman request %s: %s", name, err)
+	}
+	step.URL = rawURL
+
+	for _, header := range request.Header {
+		if header.Disabled {
+			continue
+		}
+
+		step.Headers.Add(header.Key, header.Value)
+	}
+
+	if request.Body != nil && step.Method != "GET" {
+		switch request.Body.Mode {
+		case "raw":
+			step.Body = request.Body.Raw
+		case "urlencoded":
+			for _, field := range request.Body.URLEncoded {
+				if !field.Disabled {
+					step.Form.Add(field.Key, field.Value)
+				}
+			}
+			step.Headers.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+	}
+
+	for _, event := range item.Event {
+		script, ok := translatePostmanScript(strings.Join(event.Script.Exec, "\n"))
+		if !ok {
+			continue
+		}
+
+		switch event.Listen {
+		case "prerequest":
+			step.BeforeScripts = append(step.BeforeScripts, script)
+		case "test":
+			step.Scripts = append(step.Scripts, script)
+		}
+	}
+
+	return step, nil
+}
+
+// postmanURL reads a request url which Postman stores either as a string or as an object with a raw field
+func postmanURL(raw json.RawMessage) (string, error) {
+	if len(raw) == 0 {
+		return "", nil
+	}
+
+	var value string
+	if err := json.Unmarshal(raw, &value); err == nil {
+		return value, nil
+	}
+
+	object := struct {
+		Raw string `json:"raw"`
+	}{}
+	if err := json.Unmarshal(raw, &object); err != nil {
+		return "", err
+	}
+
+	return object.Raw, nil
+}
+
+// postmanDescription reads a description which Postman stores either as a string or as an object with the content
+// and its mime type
+func postmanDescription(raw json.RawMessage) (string, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return "", nil
+	}
+
+	var value string
+	if err := json.Unmarshal(raw, &value); err == nil {
+		return value, nil
+	}
+
+	object := struct {
+		Content string `json:"content"`
+		Type    string `json:"type"`
+	}{}
+	if err := json.Unmarshal(raw, &object); err != nil {
+		return "", err
+	}
+
+	return object.Content, nil
+}
+
+// translatePostmanScript rewrites Postman variable access to runscope's, scripts using any other part of the
+// Postman api are not translatable
+func translatePostmanScript(script string) (string, bool) {
+	if strings.TrimSpace(script) == "" {
+		return "", false
+	}
+
+	translated := postmanScriptReplacer.Replace(script)
+	if strings.Contains(translated, "pm.") || strings.Contains(translated, "postman.") {
+		return "", false
+	}
+
+	return translated, true
+}
@@ -0,0 +1,81 @@
+package runscope
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestConvertPostman(t *testing.T) {
+	file, err := os.Open("testdata/postman-collection.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	test, err := ConvertPostman(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if test.Name != "Users API" {
+		t.Errorf("Expected name %s, actual %s", "Users API", test.Name)
+	}
+
+	if len(test.Steps) != 3 {
+		t.Fatalf("Expected %d steps, actual %d", 3, len(test.Steps))
+	}
+
+	login := test.Steps[0]
+	if login.Method != "POST" || login.URL != "{{baseUrl}}/login" {
+		t.Errorf("Expected POST {{baseUrl}}/login, actual %s %s", login.Method, login.URL)
+	}
+
+	if login.Headers.Has("X-Debug") {
+		t.Error("Expected disabled header to be skipped")
+	}
+
+	if len(login.BeforeScripts) != 1 || login.BeforeScripts[0] != "variables.set(\"nonce\", Date.now());" {
+		t.Errorf("Expected translated pre-request script, actual %v", login.BeforeScripts)
+	}
+
+	if len(login.Scripts) != 0 {
+		t.Errorf("Expected untranslatable test script to be skipped, actual %v", login.Scripts)
+	}
+
+	list := test.Steps[1]
+	if list.Note != "Users / List users" {
+		t.Errorf("Expected note %s, actual %s", "Users / List users", list.Note)
+	}
+
+	update := test.Steps[2]
+	if len(update.Form) != 1 || update.Form.Get("name")[0] != "bob" {
+		t.Errorf("Expected form field name %s, actual %v", "bob", update.Form)
+	}
+
+	if test.Environments[0].InitialVariables["baseUrl"] != "https://api.example.com" {
+		t.Errorf("Expected baseUrl variable, actual %v", test.Environments[0].InitialVariables)
+	}
+}
+
+func TestConvertPostmanUnsupportedSchema(t *testing.T) {
+	collection := `{"info": {"schema": "https://schema.getpostman.com/json/collection/v1.0.0/collection.json"}}`
+	if _, err := ConvertPostman(strings.NewReader(collection)); err == nil {
+		t.Error("Expected error for unsupported schema")
+	}
+}
+
+func TestConvertPostmanDescription(t *testing.T) {
+	for _, description := range []string{`"Users API tests"`, `{"content": "Users API tests", "type": "text/markdown"}`} {
+		collection := `{"info": {"schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json", ` +
+			`"description": ` + description + `}}`
+		test, err := ConvertPostman(strings.NewReader(collection))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if test.Description != "Users API tests" {
+			t.Errorf("Expected description %s, actual %s", "Users API tests", test.Description)
+		}
+	}
+}
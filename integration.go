@@ -0,0 +1,298 @@
+package runscope
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Integration types, the values of Integration.IntegrationType. Slack, PagerDuty, DataDog, New Relic Insights, email
+// and webhook integrations have typed details, see Integration.Details
+const (
+	IntegrationTypeSlack            = "slack"
+	IntegrationTypePagerDuty        = "pagerduty"
+	IntegrationTypeDataDog          = "datadog"
+	IntegrationTypeEmail            = "email"
+	IntegrationTypeWebhook          = "webhook"
+	IntegrationTypeHipChat          = "hipchat"
+	IntegrationTypeMicrosoftTeams   = "microsoft_teams"
+	IntegrationTypeOpsGenie         = "opsgenie"
+	IntegrationTypeVictorOps        = "victorops"
+	IntegrationTypeNewRelic         = "newrelic"
+	IntegrationTypeNewRelicInsights = "newrelic_insights"
+	IntegrationTypeStatusPage       = "statuspage"
+)
+
+// IntegrationDetails is the configuration of an integration specific to its type
+type IntegrationDetails interface {
+	IntegrationType() string
+}
+
+// SlackIntegration posts run notifications to a Slack channel
+type SlackIntegration struct {
+	TeamName string `json:"team_name"`
+	Channel  string `json:"channel"`
+	// ChannelID is the Slack ID of Channel
+	ChannelID string `json:"channel_id"`
+	// Username and IconURL are how notifications are posted, the Runscope bot when empty
+	Username string `json:"username"`
+	IconURL  string `json:"icon_url"`
+}
+
+// PagerDuty severities of the incidents opened by a PagerDutyIntegration
+const (
+	PagerDutySeverityCritical = "critical"
+	PagerDutySeverityError    = "error"
+	PagerDutySeverityWarning  = "warning"
+	PagerDutySeverityInfo     = "info"
+)
+
+// PagerDutyIntegration opens PagerDuty incidents for failed runs
+type PagerDutyIntegration struct {
+	ServiceName string `json:"service_name"`
+	// ServiceKey is the integration key of the PagerDuty service, the api may return it masked
+	ServiceKey string `json:"service_key"`
+	// Severities maps run results to the severity of the incident, e.g. "fail" to PagerDutySeverityCritical
+	Severities map[string]string `json:"severity_mapping"`
+}
+
+// DataDogIntegration sends run metrics and events to DataDog
+type DataDogIntegration struct {
+	// Tags are added to every metric, e.g. "env:prod"
+	Tags []string `json:"tags"`
+	// MetricPrefix is prepended to the metric names, runscope when empty
+	MetricPrefix string `json:"metric_prefix"`
+}
+
+// NewRelicInsightsIntegration sends an event with the response times of each run to New Relic Insights
+type NewRelicInsightsIntegration struct {
+	AccountID string `json:"account_id"`
+	// EventType is the Insights event type the runs are recorded as
+	EventType string `json:"event_type"`
+	// Attributes are added to every event
+	Attributes map[string]string `json:"attributes"`
+}
+
+// EmailIntegration sends run notifications to email addresses
+type EmailIntegration struct {
+	Emails []string `json:"emails"`
+}
+
+// WebhookIntegration posts run notifications to a url, see ParseResultWebhook. A PayloadTemplate replaces the default
+// ResultNotification body, e.g. to post to a receiver with a fixed schema, see WebhookIntegration.Validate
+type WebhookIntegration struct {
+	URL string `json:"url"`
+	// Method is the http method of the notification, POST when empty
+	Method string `json:"method"`
+	// ContentType of the payload, application/json when empty
+	ContentType string            `json:"content_type"`
+	Headers     map[string]string `json:"headers"`
+	// PayloadTemplate is the body of the notification with {{field}} placeholders, see RenderPayload
+	PayloadTemplate string `json:"payload_template"`
+}
+
+// IntegrationType returns IntegrationTypeSlack
+func (*SlackIntegration) IntegrationType() string { return IntegrationTypeSlack }
+
+// IntegrationType returns IntegrationTypePagerDuty
+func (*PagerDutyIntegration) IntegrationType() string { return IntegrationTypePagerDuty }
+
+// IntegrationType returns IntegrationTypeDataDog
+func (*DataDogIntegration) IntegrationType() string { return IntegrationTypeDataDog }
+
+// IntegrationType returns IntegrationTypeNewRelicInsights
+func (*NewRelicInsightsIntegration) IntegrationType() string { return IntegrationTypeNewRelicInsights }
+
+// IntegrationType returns IntegrationTypeEmail
+func (*EmailIntegration) IntegrationType() string { return IntegrationTypeEmail }
+
+// IntegrationType returns IntegrationTypeWebhook
+func (*WebhookIntegration) IntegrationType() string { return IntegrationTypeWebhook }
+
+// Details decodes the configuration of the integration into the struct of its type, e.g. *SlackIntegration. It
+// returns nil for types without typed details
+func (integration *Integration) Details() (IntegrationDetails, error) {
+	var details IntegrationDetails
+	switch integration.IntegrationType {
+	case IntegrationTypeSlack:
+		details = &SlackIntegration{}
+	case IntegrationTypePagerDuty:
+		details = &PagerDutyIntegration{}
+	case IntegrationTypeDataDog:
+		details = &DataDogIntegration{}
+	case IntegrationTypeNewRelicInsights:
+		details = &NewRelicInsightsIntegration{}
+	case IntegrationTypeEmail:
+		details = &EmailIntegration{}
+	case IntegrationTypeWebhook:
+		details = &WebhookIntegration{}
+	default:
+		return nil, nil
+	}
+
+	if err := decode(details, integration.Config); err != nil {
+		return nil, fmt.Errorf("Error reading %s integration %s: %s", integration.IntegrationType, integration.ID, err)
+	}
+
+	return details, nil
+}
+
+// EnvironmentIntegration returns a reference to the integration to add to Environment.Integrations
+func (integration *Integration) EnvironmentIntegration() *EnvironmentIntegration {
+	return &EnvironmentIntegration{
+		ID:              integration.ID,
+		IntegrationType: integration.IntegrationType,
+		Description:     integration.Description,
+	}
+}
+
+// FindIntegration finds the single integration of a team with the given type whose description matches the shell
+// pattern description, see path.Match, and returns the reference to add to an environment. It is an error if no
+// integration or more than one matches
+func (client *Client) FindIntegration(teamID string, integrationType string, description string) (*EnvironmentIntegration, error) {
+	integrations, err := client.ListIntegrations(teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	integration, err := findIntegration(integrations, integrationType, description)
+	if err != nil {
+		return nil, err
+	}
+
+	return integration.EnvironmentIntegration(), nil
+}
+
+func findIntegration(integrations []*Integration, integrationType string, description string) (*Integration, error) {
+	var matched []*Integration
+	for _, integration := range integrations {
+		if integration.IntegrationType != integrationType {
+			continue
+		}
+
+		ok, err := path.Match(description, integration.Description)
+		if err != nil {
+			return nil, fmt.Errorf("Error finding integration: %s", err)
+		}
+
+		if ok {
+			matched = append(matched, integration)
+		}
+	}
+
+	switch len(matched) {
+	case 0:
+		return nil, fmt.Errorf("Error finding integration: no %s integration matches %q", integrationType, description)
+	case 1:
+		return matched[0], nil
+	}
+
+	descriptions := make([]string, 0, len(matched))
+	for _, integration := range matched {
+		descriptions = append(descriptions, integration.Description)
+	}
+
+	return nil, fmt.Errorf("Error finding integration: %d %s integrations match %q: %s",
+		len(matched), integrationType, description, strings.Join(descriptions, ", "))
+}
+
+// Severity returns the severity of the incident opened for a run result, failed runs are critical unless mapped
+// otherwise and other results have no incident
+func (pagerDuty *PagerDutyIntegration) Severity(result string) string {
+	if severity, ok := pagerDuty.Severities[result]; ok {
+		return severity
+	}
+
+	if result == TestResultFail {
+		return PagerDutySeverityCritical
+	}
+
+	return ""
+}
+
+// AttachSlack attaches a Slack integration to an environment and sets when the environment notifies, NotifyOnAll to
+// post every result or NotifyOnFailures to post failures only. The notification settings of an environment apply to
+// all of its integrations, threshold is only used with NotifyOnThreshold
+func AttachSlack(environment *Environment, integration *Integration, notifyOn string, threshold int) error {
+	return attachIntegration(environment, integration, IntegrationTypeSlack, notifyOn, threshold)
+}
+
+// AttachPagerDuty attaches a PagerDuty integration to an environment, opening incidents according to notifyOn, e.g.
+// NotifyOnThreshold to only page after threshold consecutive failures. See AttachSlack
+func AttachPagerDuty(environment *Environment, integration *Integration, notifyOn string, threshold int) error {
+	return attachIntegration(environment, integration, IntegrationTypePagerDuty, notifyOn, threshold)
+}
+
+// AttachMetrics attaches a metrics integration, DataDog or New Relic Insights, to an environment. Metrics are sent
+// for every run regardless of the notification settings of the environment
+func AttachMetrics(environment *Environment, integration *Integration) error {
+	switch integration.IntegrationType {
+	case IntegrationTypeDataDog, IntegrationTypeNewRelicInsights:
+	default:
+		return fmt.Errorf("Error attaching integration %s: expected a metrics integration, actual %s",
+			integration.ID, integration.IntegrationType)
+	}
+
+	environment.AttachIntegration(integration.EnvironmentIntegration())
+	return nil
+}
+
+func attachIntegration(
+	environment *Environment, integration *Integration, integrationType string, notifyOn string, threshold int) error {
+	if integration.IntegrationType != integrationType {
+		return fmt.Errorf("Error attaching integration %s: expected a %s integration, actual %s",
+			integration.ID, integrationType, integration.IntegrationType)
+	}
+
+	if err := setNotifyOn(environment, notifyOn, threshold); err != nil {
+		return err
+	}
+
+	environment.AttachIntegration(integration.EnvironmentIntegration())
+	return nil
+}
+
+func setNotifyOn(environment *Environment, notifyOn string, threshold int) error {
+	switch notifyOn {
+	case NotifyOnAll, NotifyOnFailures, NotifyOnSwitch:
+	case NotifyOnThreshold:
+		if threshold < 1 {
+			return fmt.Errorf("Error setting notifications: %s requires a threshold of at least 1", NotifyOnThreshold)
+		}
+	default:
+		return fmt.Errorf("Error setting notifications: unknown notify on %q", notifyOn)
+	}
+
+	if environment.EmailSettings == nil {
+		environment.EmailSettings = &EmailSettings{}
+	}
+
+	environment.EmailSettings.NotifyOn = notifyOn
+	environment.EmailSettings.NotifyThreshold = threshold
+	return nil
+}
+
+// IntegrationUsage lists every environment of a team the integration is attached to, to check before deleting or
+// rotating it
+func (client *Client) IntegrationUsage(teamID string, integrationID string) ([]*EnvironmentReference, error) {
+	environments, err := client.ListTeamEnvironments(teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	return environmentsWithIntegration(environments, integrationID), nil
+}
+
+func environmentsWithIntegration(environments []*EnvironmentReference, integrationID string) []*EnvironmentReference {
+	var using []*EnvironmentReference
+	for _, environment := range environments {
+		for _, integration := range environment.Environment.Integrations {
+			if integration.ID == integrationID {
+				using = append(using, environment)
+				break
+			}
+		}
+	}
+
+	return using
+}
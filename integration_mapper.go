@@ -0,0 +1,85 @@
+package runscope
+
+import (
+	"fmt"
+	"strings"
+)
+
+// IntegrationMapper translates references to the integrations of one team into the equivalent integrations of
+// another, matched by type and description, so environments exported from one account work once imported into
+// another
+type IntegrationMapper struct {
+	mapping map[string]*EnvironmentIntegration
+	// Unmapped are the source integrations without exactly one equivalent in the destination team
+	Unmapped []*Integration
+}
+
+// NewIntegrationMapper lists the integrations of a source and a destination team, which may belong to different
+// accounts and so use different clients, and pairs them
+func NewIntegrationMapper(
+	source *Client, sourceTeamID string, destination *Client, destinationTeamID string) (*IntegrationMapper, error) {
+	sourceIntegrations, err := source.ListIntegrations(sourceTeamID)
+	if err != nil {
+		return nil, err
+	}
+
+	destinationIntegrations, err := destination.ListIntegrations(destinationTeamID)
+	if err != nil {
+		return nil, err
+	}
+
+	return newIntegrationMapper(sourceIntegrations, destinationIntegrations), nil
+}
+
+func newIntegrationMapper(source []*Integration, destination []*Integration) *IntegrationMapper {
+	candidates := map[string][]*Integration{}
+	for _, integration := range destination {
+		key := integrationKey(integration.IntegrationType, integration.Description)
+		candidates[key] = append(candidates[key], integration)
+	}
+
+	mapper := &IntegrationMapper{mapping: map[string]*EnvironmentIntegration{}}
+	for _, integration := range source {
+		matches := candidates[integrationKey(integration.IntegrationType, integration.Description)]
+		if len(matches) != 1 {
+			mapper.Unmapped = append(mapper.Unmapped, integration)
+			continue
+		}
+
+		mapper.mapping[integration.ID] = matches[0].EnvironmentIntegration()
+	}
+
+	return mapper
+}
+
+// Map returns the destination integration equivalent to a source integration reference
+func (mapper *IntegrationMapper) Map(integration *EnvironmentIntegration) (*EnvironmentIntegration, bool) {
+	mapped, ok := mapper.mapping[integration.ID]
+	return mapped, ok
+}
+
+// MapEnvironment replaces the integrations of an environment with their destination equivalents. Every integration
+// that can be mapped is replaced, an error lists those that can't
+func (mapper *IntegrationMapper) MapEnvironment(environment *Environment) error {
+	var unmapped []string
+	for i, integration := range environment.Integrations {
+		mapped, ok := mapper.Map(integration)
+		if !ok {
+			unmapped = append(unmapped, fmt.Sprintf("%s %q", integration.IntegrationType, integration.Description))
+			continue
+		}
+
+		environment.Integrations[i] = mapped
+	}
+
+	if len(unmapped) > 0 {
+		return fmt.Errorf("Error mapping integrations of environment %s: no equivalent for %s",
+			environment.Name, strings.Join(unmapped, ", "))
+	}
+
+	return nil
+}
+
+func integrationKey(integrationType string, description string) string {
+	return integrationType + "\x00" + strings.TrimSpace(description)
+}
@@ -0,0 +1,38 @@
+package runscope
+
+import (
+	"testing"
+)
+
+func TestIntegrationMapper(t *testing.T) {
+	source := []*Integration{
+		{ID: "s1", IntegrationType: IntegrationTypeSlack, Description: "Slack: #oncall"},
+		{ID: "s2", IntegrationType: IntegrationTypePagerDuty, Description: "PagerDuty: API"},
+		{ID: "s3", IntegrationType: IntegrationTypeWebhook, Description: "Deploy hook"},
+	}
+	destination := []*Integration{
+		{ID: "d1", IntegrationType: IntegrationTypeSlack, Description: "Slack: #oncall"},
+		{ID: "d2", IntegrationType: IntegrationTypePagerDuty, Description: "PagerDuty: API"},
+		{ID: "d3", IntegrationType: IntegrationTypePagerDuty, Description: "PagerDuty: API"},
+		{ID: "d4", IntegrationType: IntegrationTypeSlack, Description: "Deploy hook"},
+	}
+
+	mapper := newIntegrationMapper(source, destination)
+	if len(mapper.Unmapped) != 2 || mapper.Unmapped[0].ID != "s2" || mapper.Unmapped[1].ID != "s3" {
+		t.Errorf("Expected ambiguous s2 and missing s3 to be unmapped, actual %v", mapper.Unmapped)
+	}
+
+	environment := &Environment{Name: "prod", Integrations: []*EnvironmentIntegration{
+		source[0].EnvironmentIntegration(),
+		source[1].EnvironmentIntegration(),
+	}}
+
+	if err := mapper.MapEnvironment(environment); err == nil {
+		t.Error("Expected an error for the unmapped pagerduty integration")
+	}
+
+	if environment.Integrations[0].ID != "d1" || environment.Integrations[1].ID != "s2" {
+		t.Errorf("Expected slack to be mapped to d1 and pagerduty kept, actual %s %s",
+			environment.Integrations[0].ID, environment.Integrations[1].ID)
+	}
+}
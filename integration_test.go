@@ -0,0 +1,208 @@
+package runscope
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestIntegrationDetails(t *testing.T) {
+	responseBody := `
+{
+  "meta": {
+    "status": "success"
+  },
+  "data": [
+    {
+      "id": "0f8a6e3c-7b1d-4b3a-9a55-1c2d3e4f5a6b",
+      "uuid": "0f8a6e3c-7b1d-4b3a-9a55-1c2d3e4f5a6b",
+      "type": "slack",
+      "description": "Slack: #oncall",
+      "team_name": "acme",
+      "channel": "#oncall"
+    },
+    {
+      "id": "1a2b3c4d-5e6f-4a1b-8c2d-3e4f5a6b7c8d",
+      "uuid": "1a2b3c4d-5e6f-4a1b-8c2d-3e4f5a6b7c8d",
+      "type": "pagerduty",
+      "description": "PagerDuty: API",
+      "service_name": "API"
+    },
+    {
+      "id": "9d8c7b6a-5f4e-4d3c-2b1a-0f9e8d7c6b5a",
+      "uuid": "9d8c7b6a-5f4e-4d3c-2b1a-0f9e8d7c6b5a",
+      "type": "hipchat",
+      "description": "HipChat"
+    }
+  ],
+  "error": null
+}
+`
+	responseMap := new(response)
+	if err := json.Unmarshal([]byte(responseBody), &responseMap); err != nil {
+		t.Error(err)
+	}
+
+	integrations, err := getIntegrationFromResponse(responseMap.Data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	details, err := integrations[0].Details()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	slack, ok := details.(*SlackIntegration)
+	if !ok || slack.Channel != "#oncall" || slack.TeamName != "acme" {
+		t.Errorf("Expected slack integration for #oncall, actual %#v", details)
+	}
+
+	details, err = integrations[1].Details()
+	if pagerDuty, ok := details.(*PagerDutyIntegration); err != nil || !ok || pagerDuty.ServiceName != "API" {
+		t.Errorf("Expected pagerduty integration for API, actual %#v %v", details, err)
+	}
+
+	if details, err = integrations[2].Details(); details != nil || err != nil {
+		t.Errorf("Expected no details for hipchat, actual %#v %v", details, err)
+	}
+
+	reference := integrations[0].EnvironmentIntegration()
+	if reference.ID != integrations[0].ID || reference.IntegrationType != IntegrationTypeSlack {
+		t.Errorf("Expected reference to slack integration, actual %#v", reference)
+	}
+}
+
+func TestFindIntegration(t *testing.T) {
+	integrations := []*Integration{
+		{ID: "1", IntegrationType: IntegrationTypeSlack, Description: "Slack: #oncall"},
+		{ID: "2", IntegrationType: IntegrationTypeSlack, Description: "Slack: #deploys"},
+		{ID: "3", IntegrationType: IntegrationTypePagerDuty, Description: "PagerDuty: API"},
+	}
+
+	integration, err := findIntegration(integrations, IntegrationTypeSlack, "*#oncall")
+	if err != nil || integration.ID != "1" {
+		t.Errorf("Expected integration 1, actual %v %v", integration, err)
+	}
+
+	if _, err = findIntegration(integrations, IntegrationTypeSlack, "Slack:*"); err == nil {
+		t.Error("Expected an error for an ambiguous description")
+	}
+
+	if _, err = findIntegration(integrations, IntegrationTypePagerDuty, "*oncall"); err == nil {
+		t.Error("Expected an error when no integration matches")
+	}
+}
+
+func TestAttachSlack(t *testing.T) {
+	slack := &Integration{ID: "1", IntegrationType: IntegrationTypeSlack, Description: "Slack: #oncall"}
+	environment := &Environment{Name: "prod"}
+
+	if err := AttachSlack(environment, slack, NotifyOnFailures, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := AttachSlack(environment, slack, NotifyOnAll, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(environment.Integrations) != 1 || environment.Integrations[0].ID != "1" {
+		t.Errorf("Expected slack integration attached once, actual %v", environment.Integrations)
+	}
+
+	if environment.EmailSettings.NotifyOn != NotifyOnAll {
+		t.Errorf("Expected notify on %s, actual %s", NotifyOnAll, environment.EmailSettings.NotifyOn)
+	}
+
+	if err := AttachSlack(environment, slack, NotifyOnThreshold, 0); err == nil {
+		t.Error("Expected an error for a threshold of 0")
+	}
+
+	pagerDuty := &Integration{ID: "2", IntegrationType: IntegrationTypePagerDuty}
+	if err := AttachSlack(environment, pagerDuty, NotifyOnAll, 0); err == nil {
+		t.Error("Expected an error for a pagerduty integration")
+	}
+
+	if !environment.DetachIntegration("1") || len(environment.Integrations) != 0 {
+		t.Errorf("Expected slack integration to be detached, actual %v", environment.Integrations)
+	}
+}
+
+func TestAttachPagerDuty(t *testing.T) {
+	pagerDuty := &Integration{ID: "2", IntegrationType: IntegrationTypePagerDuty, Config: map[string]interface{}{
+		"service_name":     "API",
+		"severity_mapping": map[string]interface{}{"fail": "error"},
+	}}
+	environment := &Environment{Name: "prod"}
+
+	if err := AttachPagerDuty(environment, pagerDuty, NotifyOnThreshold, 3); err != nil {
+		t.Fatal(err)
+	}
+
+	if environment.Integrations[0].ID != "2" || environment.EmailSettings.NotifyThreshold != 3 {
+		t.Errorf("Expected pagerduty attached with threshold 3, actual %v %d",
+			environment.Integrations, environment.EmailSettings.NotifyThreshold)
+	}
+
+	details, err := pagerDuty.Details()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := details.(*PagerDutyIntegration)
+	if config.Severity(TestResultFail) != PagerDutySeverityError || config.Severity(TestResultPass) != "" {
+		t.Errorf("Expected mapped error severity for failures, actual %s %s",
+			config.Severity(TestResultFail), config.Severity(TestResultPass))
+	}
+
+	if (&PagerDutyIntegration{}).Severity(TestResultFail) != PagerDutySeverityCritical {
+		t.Errorf("Expected failures to be %s by default", PagerDutySeverityCritical)
+	}
+}
+
+func TestAttachMetrics(t *testing.T) {
+	dataDog := &Integration{ID: "3", IntegrationType: IntegrationTypeDataDog, Config: map[string]interface{}{
+		"tags":          []interface{}{"env:prod", "team:api"},
+		"metric_prefix": "synthetics",
+	}}
+	environment := &Environment{Name: "prod"}
+
+	if err := AttachMetrics(environment, dataDog); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(environment.Integrations) != 1 || environment.EmailSettings != nil {
+		t.Errorf("Expected datadog attached without notification settings, actual %v %v",
+			environment.Integrations, environment.EmailSettings)
+	}
+
+	details, err := dataDog.Details()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if config := details.(*DataDogIntegration); len(config.Tags) != 2 || config.MetricPrefix != "synthetics" {
+		t.Errorf("Expected 2 tags and prefix synthetics, actual %v %s", config.Tags, config.MetricPrefix)
+	}
+
+	slack := &Integration{ID: "1", IntegrationType: IntegrationTypeSlack}
+	if err := AttachMetrics(environment, slack); err == nil {
+		t.Error("Expected an error for a slack integration")
+	}
+}
+
+func TestEnvironmentsWithIntegration(t *testing.T) {
+	bucket := &Bucket{Name: "prod"}
+	slack := &EnvironmentIntegration{ID: "1", IntegrationType: IntegrationTypeSlack}
+	pagerDuty := &EnvironmentIntegration{ID: "2", IntegrationType: IntegrationTypePagerDuty}
+	environments := []*EnvironmentReference{
+		{Bucket: bucket, Environment: &Environment{Name: "shared", Integrations: []*EnvironmentIntegration{slack}}},
+		{Bucket: bucket, Test: &Test{Name: "Smoke test"},
+			Environment: &Environment{Name: "test", Integrations: []*EnvironmentIntegration{pagerDuty, slack}}},
+		{Bucket: bucket, Environment: &Environment{Name: "quiet"}},
+	}
+
+	using := environmentsWithIntegration(environments, "1")
+	if len(using) != 2 || using[0].String() != "prod/shared" || using[1].String() != "prod/Smoke test/test" {
+		t.Errorf("Expected prod/shared and prod/Smoke test/test, actual %v", using)
+	}
+}
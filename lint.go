@@ -0,0 +1,221 @@
+package runscope
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Lint rules reported in LintIssue.Rule
+const (
+	LintNoAssertions         = "no-assertions"
+	LintUnusedVariable       = "unused-variable"
+	LintHardcodedCredentials = "hardcoded-credentials"
+	LintUnreachableCondition = "unreachable-condition"
+)
+
+var credentialNames = []string{"password", "passwd", "secret", "token", "api_key", "apikey", "access_key"}
+
+var credentialHeaders = map[string]bool{
+	"authorization":       true,
+	"proxy-authorization": true,
+	"x-api-key":           true,
+	"x-auth-token":        true,
+	"cookie":              true,
+}
+
+// LintIssue is a quality problem found in a test definition. Step is the position of the step, nested steps of a
+// condition are separated by a dot, e.g. "3.1"
+type LintIssue struct {
+	Step    string
+	Rule    string
+	Message string
+}
+
+func (issue *LintIssue) String() string {
+	return fmt.Sprintf("step %s: %s: %s", issue.Step, issue.Rule, issue.Message)
+}
+
+type lintStep struct {
+	path string
+	step *TestStep
+}
+
+// Lint checks a test definition for steps without assertions, extracted variables that are never used, credentials
+// hard-coded into urls or headers and conditions that can never be true
+func Lint(test *Test) []*LintIssue {
+	var issues []*LintIssue
+	steps := flattenLintSteps(test.Steps, "")
+
+	for i, current := range steps {
+		step := current.step
+		switch step.StepType {
+		case StepTypeRequest, StepTypeSubtest, StepTypeGhostInspector:
+			if len(step.Assertions) == 0 {
+				issues = append(issues, &LintIssue{current.path, LintNoAssertions, "step has no assertions"})
+			}
+		case StepTypeCondition:
+			if reason, ok := unreachableCondition(step); ok && !step.Skipped() {
+				issues = append(issues, &LintIssue{current.path, LintUnreachableCondition, reason})
+			}
+		}
+
+		for _, message := range hardcodedCredentials(step) {
+			issues = append(issues, &LintIssue{current.path, LintHardcodedCredentials, message})
+		}
+
+		for _, variable := range step.Variables {
+			if !variableUsed(variable.Name, steps[i+1:]) {
+				issues = append(issues, &LintIssue{current.path, LintUnusedVariable,
+					fmt.Sprintf("variable %q is extracted but never used by a later step", variable.Name)})
+			}
+		}
+	}
+
+	return issues
+}
+
+func flattenLintSteps(steps []*TestStep, prefix string) []*lintStep {
+	var flattened []*lintStep
+	for i, step := range steps {
+		path := prefix + strconv.Itoa(i+1)
+		flattened = append(flattened, &lintStep{path, step})
+		flattened = append(flattened, flattenLintSteps(step.Steps, path+".")...)
+	}
+
+	return flattened
+}
+
+// stepText returns every field of a step that may reference a variable
+func stepText(step *TestStep) []string {
+	text := []string{step.URL, step.Body, step.LeftValue, step.RightValue}
+	for _, header := range step.Headers {
+		text = append(text, header.Values...)
+	}
+
+	for _, field := range step.Form {
+		text = append(text, field.Values...)
+	}
+
+	for _, value := range step.Auth {
+		text = append(text, value)
+	}
+
+	for _, assertion := range step.Assertions {
+		text = append(text, assertion.Property, fmt.Sprintf("%v", assertion.Value))
+	}
+
+	text = append(text, step.Scripts...)
+	text = append(text, step.BeforeScripts...)
+	return text
+}
+
+func variableUsed(name string, steps []*lintStep) bool {
+	template := "{{" + name + "}}"
+	for _, later := range steps {
+		for _, text := range stepText(later.step) {
+			if strings.Contains(text, template) ||
+				strings.Contains(text, fmt.Sprintf("variables.get(%q)", name)) ||
+				strings.Contains(text, fmt.Sprintf("variables.get('%s')", name)) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func isTemplated(value string) bool {
+	return strings.Contains(value, "{{") && strings.Contains(value, "}}")
+}
+
+func isCredentialName(name string) bool {
+	name = strings.ToLower(name)
+	for _, credential := range credentialNames {
+		if strings.Contains(name, credential) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hardcodedCredentials(step *TestStep) []string {
+	var messages []string
+	if parsed, err := url.Parse(step.URL); err == nil {
+		if password, ok := parsed.User.Password(); ok && !isTemplated(password) {
+			messages = append(messages, "url contains a hard-coded password")
+		}
+
+		for name, values := range parsed.Query() {
+			for _, value := range values {
+				if isCredentialName(name) && value != "" && !isTemplated(value) {
+					messages = append(messages, fmt.Sprintf("url query parameter %q contains a hard-coded credential", name))
+				}
+			}
+		}
+	}
+
+	for _, header := range step.Headers {
+		for _, value := range header.Values {
+			if (credentialHeaders[strings.ToLower(header.Name)] || isCredentialName(header.Name)) &&
+				value != "" && !isTemplated(value) {
+				messages = append(messages, fmt.Sprintf("header %q contains a hard-coded credential", header.Name))
+			}
+		}
+	}
+
+	for _, field := range step.Form {
+		for _, value := range field.Values {
+			if isCredentialName(field.Name) && value != "" && !isTemplated(value) {
+				messages = append(messages, fmt.Sprintf("form parameter %q contains a hard-coded credential", field.Name))
+			}
+		}
+	}
+
+	for name, value := range step.Auth {
+		if isCredentialName(name) && value != "" && !isTemplated(value) {
+			messages = append(messages, fmt.Sprintf("auth %q contains a hard-coded credential", name))
+		}
+	}
+
+	return messages
+}
+
+// unreachableCondition evaluates conditions comparing two literal values, which always have the same outcome
+func unreachableCondition(step *TestStep) (string, bool) {
+	if isTemplated(step.LeftValue) || isTemplated(step.RightValue) {
+		return "", false
+	}
+
+	left, right := step.LeftValue, step.RightValue
+	var holds bool
+	switch step.Comparison {
+	case ComparisonEqual:
+		holds = left == right
+	case ComparisonNotEqual:
+		holds = left != right
+	case ComparisonEmpty:
+		holds = left == ""
+	case ComparisonNotEmpty:
+		holds = left != ""
+	case ComparisonContains:
+		holds = strings.Contains(left, right)
+	case ComparisonDoesNotContain:
+		holds = !strings.Contains(left, right)
+	case ComparisonEqualNumber:
+		leftNumber, leftErr := strconv.ParseFloat(left, 64)
+		rightNumber, rightErr := strconv.ParseFloat(right, 64)
+		holds = leftErr == nil && rightErr == nil && leftNumber == rightNumber
+	default:
+		return "", false
+	}
+
+	if holds {
+		return "", false
+	}
+
+	return fmt.Sprintf("condition %q %s %q compares literal values and is never true, its steps never run",
+		left, step.Comparison, right), true
+}
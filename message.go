@@ -0,0 +1,95 @@
+package runscope
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// Message represents a request/response pair captured in a bucket. See https://www.runscope.com/docs/api/buckets#bucket-errors
+type Message struct {
+	UUID               string     `json:"uuid,omitempty"`
+	Timestamp          *time.Time `json:"timestamp,omitempty"`
+	Method             string     `json:"method,omitempty"`
+	URL                string     `json:"url,omitempty"`
+	Host               string     `json:"host,omitempty"`
+	Path               string     `json:"path,omitempty"`
+	ResponseStatusCode int        `json:"response_status_code,omitempty"`
+	ResponseSizeBytes  int        `json:"response_size_bytes,omitempty"`
+	ResponseTimeMs     float64    `json:"response_time_ms,omitempty"`
+	ConnectionError    string     `json:"connection_error,omitempty"`
+	Edited             bool       `json:"edited,omitempty"`
+}
+
+// ListMessages lists the most recent messages captured in a bucket. See https://www.runscope.com/docs/api/buckets#bucket-messages
+func (client *Client) ListMessages(bucket *Bucket) ([]*Message, error) {
+	endpoint, error := bucketEndpoint(bucket.Key, "/messages")
+	if error != nil {
+		return nil, error
+	}
+
+	resource, error := client.readResource("[]message", bucket.Key.String(), endpoint)
+	if error != nil {
+		return nil, error
+	}
+
+	messages, error := getMessagesFromResponse(resource.Data)
+	if error != nil {
+		return nil, error
+	}
+
+	return messages, nil
+}
+
+// ListBucketErrors lists captured messages that returned a non-2xx response or failed to connect, optionally
+// restricted to those captured after since. See https://www.runscope.com/docs/api/buckets#bucket-errors
+func (client *Client) ListBucketErrors(bucket *Bucket, since time.Time) ([]*Message, error) {
+	endpoint, error := bucketEndpoint(bucket.Key, "/errors")
+	if error != nil {
+		return nil, error
+	}
+
+	if !since.IsZero() {
+		query := url.Values{}
+		query.Add("since", fmt.Sprintf("%d", since.Unix()))
+		endpoint = endpoint + "?" + query.Encode()
+	}
+
+	resource, error := client.readResource("[]message", bucket.Key.String(), endpoint)
+	if error != nil {
+		return nil, error
+	}
+
+	messages, error := getMessagesFromResponse(resource.Data)
+	if error != nil {
+		return nil, error
+	}
+
+	return messages, nil
+}
+
+// ClearMessages deletes all messages captured in a bucket. See https://www.runscope.com/docs/api/buckets#bucket-messages-delete
+func (client *Client) ClearMessages(bucket *Bucket) error {
+	endpoint, error := bucketEndpoint(bucket.Key, "/messages")
+	if error != nil {
+		return error
+	}
+
+	return client.deleteResource("messages", bucket.Key.String(), endpoint)
+}
+
+func (message *Message) String() string {
+	value, err := json.Marshal(message)
+	if err != nil {
+		return ""
+	}
+
+	return string(value)
+}
+
+func getMessagesFromResponse(response interface{}) ([]*Message, error) {
+	var messages []*Message
+	err := decode(&messages, response)
+	return messages, err
+}
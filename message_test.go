@@ -0,0 +1,95 @@
+package runscope
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestListBucketErrors(t *testing.T) {
+	testPreCheck(t)
+	client := clientConfigure()
+	bucket, err := client.CreateBucket(&Bucket{Name: "test", Team: &Team{ID: teamID}})
+	defer client.DeleteBucket(bucket.Key)
+	if err != nil {
+		t.Error(err)
+	}
+
+	messages, err := client.ListBucketErrors(bucket, time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Error(err)
+	}
+
+	if len(messages) != 0 {
+		t.Errorf("Expected no errors in a new bucket, actual %d", len(messages))
+	}
+}
+
+func TestClearMessages(t *testing.T) {
+	testPreCheck(t)
+	client := clientConfigure()
+	bucket, err := client.CreateBucket(&Bucket{Name: "test", Team: &Team{ID: teamID}})
+	defer client.DeleteBucket(bucket.Key)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if err = client.ClearMessages(bucket); err != nil {
+		t.Error(err)
+	}
+
+	messages, err := client.ListBucketErrors(bucket, time.Time{})
+	if err != nil {
+		t.Error(err)
+	}
+
+	if len(messages) != 0 {
+		t.Errorf("Expected no messages after clearing bucket, actual %d", len(messages))
+	}
+}
+
+func TestReadMessagesFromResponse(t *testing.T) {
+	responseBody := `
+{
+  "meta": {
+    "status": "success"
+  },
+  "data": [
+    {
+      "uuid": "d5a3b3bc-1e3b-4f9b-b2f0-6e8e5c1e7d4a",
+      "timestamp": 1494450838.0,
+      "method": "GET",
+      "url": "https://example.com/missing",
+      "host": "example.com",
+      "path": "/missing",
+      "response_status_code": 404,
+      "response_size_bytes": 233,
+      "response_time_ms": 12.5,
+      "edited": false
+    }
+  ],
+  "error": null
+}
+`
+	responseMap := new(response)
+	if err := json.Unmarshal([]byte(responseBody), &responseMap); err != nil {
+		t.Error(err)
+	}
+
+	messages, err := getMessagesFromResponse(responseMap.Data)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if len(messages) != 1 {
+		t.Fatalf("Expected %d messages, actual %d", 1, len(messages))
+	}
+
+	if messages[0].ResponseStatusCode != 404 {
+		t.Errorf("Expected status code %d, actual %d", 404, messages[0].ResponseStatusCode)
+	}
+
+	if messages[0].Timestamp.Unix() != 1494450838 {
+		t.Errorf("Expected timestamp %d, actual %d", 1494450838, messages[0].Timestamp.Unix())
+	}
+}
@@ -0,0 +1,180 @@
+package runscope
+
+import (
+	"sort"
+)
+
+// Normalize returns a copy of the test in a canonical form, so a test read from the api and the same test built
+// locally compare equal field by field, e.g. in a Terraform plan. Fields assigned by the server, the trigger url,
+// timestamps, author, last run and step IDs, are cleared while the ID of the test is kept to identify it. Empty
+// collections become nil, headers and form parameters are ordered by name as the api returns them, numeric assertion
+// values become float64 as decoded from json, and environments are normalized with Environment.Normalize
+func (test *Test) Normalize() *Test {
+	if test == nil {
+		return nil
+	}
+
+	normalized := test.Clone()
+	normalized.TriggerURL = ""
+	normalized.CreatedAt = nil
+	normalized.CreatedBy = nil
+	normalized.ExportedAt = nil
+	normalized.LastRun = nil
+	normalized.Steps = normalizeSteps(normalized.Steps)
+
+	if len(normalized.Environments) == 0 {
+		normalized.Environments = nil
+	}
+
+	for i, environment := range normalized.Environments {
+		normalized.Environments[i] = environment.Normalize()
+	}
+
+	return normalized
+}
+
+// Normalize returns a copy of the environment in a canonical form, see Test.Normalize. The export timestamp and test
+// ID assigned by the server are cleared, empty collections and email settings without effect become nil, and
+// regions, webhooks, integrations, agents and recipients are sorted, as their order has no meaning
+func (environment *Environment) Normalize() *Environment {
+	if environment == nil {
+		return nil
+	}
+
+	normalized := environment.clone()
+	normalized.ExportedAt = nil
+	normalized.TestID = ""
+
+	if len(normalized.InitialVariables) == 0 {
+		normalized.InitialVariables = nil
+	}
+
+	if len(normalized.Headers) == 0 {
+		normalized.Headers = nil
+	}
+
+	normalized.Regions = normalizeStrings(normalized.Regions)
+	normalized.WebHooks = normalizeStrings(normalized.WebHooks)
+
+	if len(normalized.Integrations) == 0 {
+		normalized.Integrations = nil
+	}
+
+	sort.SliceStable(normalized.Integrations, func(i, j int) bool {
+		return normalized.Integrations[i].ID < normalized.Integrations[j].ID
+	})
+
+	if len(normalized.RemoteAgents) == 0 {
+		normalized.RemoteAgents = nil
+	}
+
+	sort.SliceStable(normalized.RemoteAgents, func(i, j int) bool {
+		return normalized.RemoteAgents[i].UUID < normalized.RemoteAgents[j].UUID
+	})
+
+	if settings := normalized.EmailSettings; settings != nil {
+		if len(settings.Recipients) == 0 {
+			settings.Recipients = nil
+		}
+
+		sort.SliceStable(settings.Recipients, func(i, j int) bool {
+			left, right := settings.Recipients[i], settings.Recipients[j]
+			if left.ID != right.ID {
+				return left.ID < right.ID
+			}
+
+			return left.Email < right.Email
+		})
+
+		if !settings.NotifyAll && settings.NotifyOn == "" && settings.NotifyThreshold == 0 && settings.Recipients == nil {
+			normalized.EmailSettings = nil
+		}
+	}
+
+	return normalized
+}
+
+func normalizeSteps(steps []*TestStep) []*TestStep {
+	if len(steps) == 0 {
+		return nil
+	}
+
+	for _, step := range steps {
+		step.ID = ""
+		step.Headers = normalizeParameters(step.Headers)
+		step.Form = normalizeParameters(step.Form)
+		step.Scripts = normalizeEmpty(step.Scripts)
+		step.BeforeScripts = normalizeEmpty(step.BeforeScripts)
+		step.Steps = normalizeSteps(step.Steps)
+
+		if len(step.Auth) == 0 {
+			step.Auth = nil
+		}
+
+		if len(step.Args) == 0 {
+			step.Args = nil
+		}
+
+		if len(step.Extra) == 0 {
+			step.Extra = nil
+		}
+
+		if len(step.Assertions) == 0 {
+			step.Assertions = nil
+		}
+
+		for _, assertion := range step.Assertions {
+			assertion.Value = normalizeNumber(assertion.Value)
+		}
+
+		if len(step.Variables) == 0 {
+			step.Variables = nil
+		}
+	}
+
+	return steps
+}
+
+func normalizeParameters(parameters Parameters) Parameters {
+	if len(parameters) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(parameters, func(i, j int) bool { return parameters[i].Name < parameters[j].Name })
+	return parameters
+}
+
+// normalizeEmpty returns nil for an empty slice, keeping the order of the values
+func normalizeEmpty(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+
+	return values
+}
+
+// normalizeStrings returns the values sorted, nil when empty
+func normalizeStrings(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+
+	sort.Strings(values)
+	return values
+}
+
+// normalizeNumber converts numbers to float64, the type json numbers are decoded into
+func normalizeNumber(value interface{}) interface{} {
+	switch number := value.(type) {
+	case int:
+		return float64(number)
+	case int32:
+		return float64(number)
+	case int64:
+		return float64(number)
+	case float32:
+		return float64(number)
+	default:
+		return value
+	}
+}
@@ -0,0 +1,79 @@
+package runscope
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNormalizeTest(t *testing.T) {
+	createdAt := time.Date(2021, 5, 6, 10, 0, 0, 0, time.UTC)
+	remote := &Test{
+		ID:         "8e7afae4",
+		Name:       "Smoke test",
+		TriggerURL: "https://api.runscope.com/radar/8e7afae4/trigger",
+		CreatedAt:  &createdAt,
+		CreatedBy:  &Contact{ID: "1"},
+		LastRun:    &TestRun{Status: "completed"},
+		Steps: []*TestStep{{
+			ID:         "step-1",
+			StepType:   StepTypeRequest,
+			Method:     "GET",
+			URL:        "https://example.com",
+			Headers:    Parameters{{Name: "X-B", Values: []string{"2"}}, {Name: "Accept", Values: []string{"*/*"}}},
+			Assertions: []*Assertion{{Source: AssertionSourceResponseStatus, Comparison: ComparisonEqualNumber, Value: float64(200)}},
+			Scripts:    []string{},
+		}},
+		Environments: []*Environment{},
+	}
+
+	local := &Test{
+		ID:   "8e7afae4",
+		Name: "Smoke test",
+		Steps: []*TestStep{{
+			StepType:   StepTypeRequest,
+			Method:     "GET",
+			URL:        "https://example.com",
+			Headers:    Parameters{{Name: "Accept", Values: []string{"*/*"}}, {Name: "X-B", Values: []string{"2"}}},
+			Assertions: []*Assertion{{Source: AssertionSourceResponseStatus, Comparison: ComparisonEqualNumber, Value: 200}},
+		}},
+	}
+
+	if !reflect.DeepEqual(remote.Normalize(), local.Normalize()) {
+		t.Errorf("Expected normalized tests to be equal, actual\n%s\n%s", remote.Normalize(), local.Normalize())
+	}
+
+	if remote.Steps[0].ID != "step-1" || remote.LastRun == nil {
+		t.Error("Expected Normalize not to modify the test")
+	}
+}
+
+func TestNormalizeEnvironment(t *testing.T) {
+	exportedAt := time.Date(2021, 5, 6, 10, 0, 0, 0, time.UTC)
+	remote := &Environment{
+		ID:               "1",
+		Name:             "prod",
+		TestID:           "8e7afae4",
+		ExportedAt:       &exportedAt,
+		Regions:          []string{"us1", "eu1"},
+		InitialVariables: map[string]string{},
+		Integrations:     []*EnvironmentIntegration{{ID: "b"}, {ID: "a"}},
+		EmailSettings:    &EmailSettings{Recipients: []*Contact{}},
+	}
+
+	local := &Environment{
+		ID:           "1",
+		Name:         "prod",
+		Regions:      []string{"eu1", "us1"},
+		Integrations: []*EnvironmentIntegration{{ID: "a"}, {ID: "b"}},
+	}
+
+	if !reflect.DeepEqual(remote.Normalize(), local.Normalize()) {
+		t.Errorf("Expected normalized environments to be equal, actual\n%s\n%s", remote.Normalize(), local.Normalize())
+	}
+
+	notify := &Environment{EmailSettings: &EmailSettings{NotifyOn: NotifyOnFailures, Recipients: []*Contact{}}}
+	if settings := notify.Normalize().EmailSettings; settings == nil || settings.Recipients != nil {
+		t.Errorf("Expected email settings without recipients, actual %v", settings)
+	}
+}
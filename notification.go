@@ -0,0 +1,156 @@
+package runscope
+
+// NotificationChannel is somewhere an environment notifies of its runs: a WebhookChannel, an EmailChannel or an
+// integration. Channels are added to and removed from an environment with AddChannel and RemoveChannel
+type NotificationChannel interface {
+	String() string
+	addTo(environment *Environment)
+	removeFrom(environment *Environment) bool
+}
+
+// WebhookChannel is a url the results of an environment's runs are posted to
+type WebhookChannel string
+
+// EmailChannel is a team member emailed the results of an environment's runs, identified by ID or Email
+type EmailChannel Contact
+
+func (webhook WebhookChannel) String() string {
+	return string(webhook)
+}
+
+func (webhook WebhookChannel) addTo(environment *Environment) {
+	for _, existing := range environment.WebHooks {
+		if existing == string(webhook) {
+			return
+		}
+	}
+
+	environment.WebHooks = append(environment.WebHooks, string(webhook))
+}
+
+func (webhook WebhookChannel) removeFrom(environment *Environment) bool {
+	for i, existing := range environment.WebHooks {
+		if existing == string(webhook) {
+			environment.WebHooks = append(environment.WebHooks[:i:i], environment.WebHooks[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+func (email *EmailChannel) String() string {
+	if email.Email != "" {
+		return "mailto:" + email.Email
+	}
+
+	return "mailto:" + email.ID
+}
+
+func (email *EmailChannel) matches(contact *Contact) bool {
+	if email.ID != "" && contact.ID != "" {
+		return email.ID == contact.ID
+	}
+
+	return email.Email != "" && email.Email == contact.Email
+}
+
+func (email *EmailChannel) addTo(environment *Environment) {
+	if environment.EmailSettings == nil {
+		environment.EmailSettings = &EmailSettings{}
+	}
+
+	for _, existing := range environment.EmailSettings.Recipients {
+		if email.matches(existing) {
+			return
+		}
+	}
+
+	contact := Contact(*email)
+	environment.EmailSettings.Recipients = append(environment.EmailSettings.Recipients, &contact)
+}
+
+func (email *EmailChannel) removeFrom(environment *Environment) bool {
+	if environment.EmailSettings == nil {
+		return false
+	}
+
+	recipients := environment.EmailSettings.Recipients
+	for i, existing := range recipients {
+		if email.matches(existing) {
+			environment.EmailSettings.Recipients = append(recipients[:i:i], recipients[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+func (integration *EnvironmentIntegration) String() string {
+	if integration.Description != "" {
+		return integration.IntegrationType + ":" + integration.Description
+	}
+
+	return integration.IntegrationType + ":" + integration.ID
+}
+
+func (integration *EnvironmentIntegration) addTo(environment *Environment) {
+	environment.AttachIntegration(integration)
+}
+
+func (integration *EnvironmentIntegration) removeFrom(environment *Environment) bool {
+	return environment.DetachIntegration(integration.ID)
+}
+
+func (integration *Integration) String() string {
+	return integration.EnvironmentIntegration().String()
+}
+
+func (integration *Integration) addTo(environment *Environment) {
+	environment.AttachIntegration(integration.EnvironmentIntegration())
+}
+
+func (integration *Integration) removeFrom(environment *Environment) bool {
+	return environment.DetachIntegration(integration.ID)
+}
+
+// AddChannel adds notification channels to the environment, e.g. a Slack integration and a PagerDuty integration,
+// and sets when they're notified, NotifyOnFailures to notify of failed runs only. The notification settings of an
+// environment apply to all of its channels, threshold is only used with NotifyOnThreshold
+func (environment *Environment) AddChannel(notifyOn string, threshold int, channels ...NotificationChannel) error {
+	if err := setNotifyOn(environment, notifyOn, threshold); err != nil {
+		return err
+	}
+
+	for _, channel := range channels {
+		channel.addTo(environment)
+	}
+
+	return nil
+}
+
+// RemoveChannel removes a notification channel from the environment, it reports whether the environment had it
+func (environment *Environment) RemoveChannel(channel NotificationChannel) bool {
+	return channel.removeFrom(environment)
+}
+
+// Channels lists the notification channels of the environment, webhooks first, then email recipients and
+// integrations
+func (environment *Environment) Channels() []NotificationChannel {
+	var channels []NotificationChannel
+	for _, webhook := range environment.WebHooks {
+		channels = append(channels, WebhookChannel(webhook))
+	}
+
+	if environment.EmailSettings != nil {
+		for _, recipient := range environment.EmailSettings.Recipients {
+			channels = append(channels, (*EmailChannel)(recipient))
+		}
+	}
+
+	for _, integration := range environment.Integrations {
+		channels = append(channels, integration)
+	}
+
+	return channels
+}
@@ -0,0 +1,65 @@
+package runscope
+
+import (
+	"testing"
+)
+
+func TestAddChannel(t *testing.T) {
+	slack := &Integration{ID: "1", IntegrationType: IntegrationTypeSlack, Description: "Slack: #oncall"}
+	pagerDuty := &EnvironmentIntegration{ID: "2", IntegrationType: IntegrationTypePagerDuty}
+	environment := &Environment{Name: "prod"}
+
+	err := environment.AddChannel(NotifyOnFailures, 0,
+		slack, pagerDuty, WebhookChannel("https://example.com/hook"), &EmailChannel{Email: "ops@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := environment.AddChannel(NotifyOnFailures, 0, slack, &EmailChannel{Email: "ops@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if environment.EmailSettings.NotifyOn != NotifyOnFailures {
+		t.Errorf("Expected notify on %s, actual %s", NotifyOnFailures, environment.EmailSettings.NotifyOn)
+	}
+
+	channels := environment.Channels()
+	expected := []string{"https://example.com/hook", "mailto:ops@example.com", "slack:Slack: #oncall", "pagerduty:2"}
+	if len(channels) != len(expected) {
+		t.Fatalf("Expected %d channels, actual %v", len(expected), channels)
+	}
+
+	for i, channel := range channels {
+		if channel.String() != expected[i] {
+			t.Errorf("Expected channel %s, actual %s", expected[i], channel)
+		}
+	}
+
+	if err := environment.AddChannel("sometimes", 0, slack); err == nil {
+		t.Error("Expected an error for an unknown notify on")
+	}
+}
+
+func TestRemoveChannel(t *testing.T) {
+	environment := &Environment{
+		WebHooks:      []string{"https://example.com/hook"},
+		EmailSettings: &EmailSettings{Recipients: []*Contact{{ID: "42", Email: "ops@example.com"}}},
+		Integrations:  []*EnvironmentIntegration{{ID: "1", IntegrationType: IntegrationTypeSlack}},
+	}
+
+	if !environment.RemoveChannel(&EmailChannel{ID: "42"}) || len(environment.EmailSettings.Recipients) != 0 {
+		t.Errorf("Expected recipient to be removed, actual %v", environment.EmailSettings.Recipients)
+	}
+
+	if !environment.RemoveChannel(&Integration{ID: "1"}) || len(environment.Integrations) != 0 {
+		t.Errorf("Expected integration to be removed, actual %v", environment.Integrations)
+	}
+
+	if environment.RemoveChannel(WebhookChannel("https://example.com/other")) {
+		t.Error("Expected unknown webhook not to be removed")
+	}
+
+	if !environment.RemoveChannel(WebhookChannel("https://example.com/hook")) || len(environment.WebHooks) != 0 {
+		t.Errorf("Expected webhook to be removed, actual %v", environment.WebHooks)
+	}
+}
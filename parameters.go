@@ -0,0 +1,180 @@
+package runscope
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Parameter is a named header or form parameter with its values
+type Parameter struct {
+	Name   string
+	Values []string
+}
+
+// Parameters are the headers or form parameters of a request step in order. They are sent as a json object whose
+// keys keep this order, so exports, diffs and the runscope ui show them stably. Parameters read from the api keep
+// the order of the response
+type Parameters []*Parameter
+
+func (parameter *Parameter) String() string {
+	return fmt.Sprintf("%s: %s", parameter.Name, strings.Join(parameter.Values, ", "))
+}
+
+// Get returns the values of the named parameter
+func (parameters Parameters) Get(name string) []string {
+	for _, parameter := range parameters {
+		if parameter.Name == name {
+			return parameter.Values
+		}
+	}
+
+	return nil
+}
+
+// Has reports whether the named parameter is present
+func (parameters Parameters) Has(name string) bool {
+	for _, parameter := range parameters {
+		if parameter.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Add appends value to the named parameter, adding the parameter last if it is not present yet
+func (parameters *Parameters) Add(name string, value string) {
+	for _, parameter := range *parameters {
+		if parameter.Name == name {
+			parameter.Values = append(parameter.Values, value)
+			return
+		}
+	}
+
+	*parameters = append(*parameters, &Parameter{Name: name, Values: []string{value}})
+}
+
+// Set replaces the values of the named parameter keeping its position, adding it last if it is not present yet
+func (parameters *Parameters) Set(name string, values ...string) {
+	for _, parameter := range *parameters {
+		if parameter.Name == name {
+			parameter.Values = values
+			return
+		}
+	}
+
+	*parameters = append(*parameters, &Parameter{Name: name, Values: values})
+}
+
+// Del removes the named parameter
+func (parameters *Parameters) Del(name string) {
+	kept := (*parameters)[:0]
+	for _, parameter := range *parameters {
+		if parameter.Name != name {
+			kept = append(kept, parameter)
+		}
+	}
+
+	*parameters = kept
+}
+
+// MarshalJSON encodes the parameters as a json object of value arrays, in order
+func (parameters Parameters) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteByte('{')
+	for i, parameter := range parameters {
+		if i > 0 {
+			buffer.WriteByte(',')
+		}
+
+		name, err := json.Marshal(parameter.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		values := parameter.Values
+		if values == nil {
+			values = []string{}
+		}
+
+		encoded, err := json.Marshal(values)
+		if err != nil {
+			return nil, err
+		}
+
+		buffer.Write(name)
+		buffer.WriteByte(':')
+		buffer.Write(encoded)
+	}
+	buffer.WriteByte('}')
+
+	return buffer.Bytes(), nil
+}
+
+// UnmarshalJSON decodes a json object of value arrays or single values keeping the order of its keys
+func (parameters *Parameters) UnmarshalJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	if token == nil {
+		*parameters = nil
+		return nil
+	}
+
+	if delim, ok := token.(json.Delim); !ok || delim != '{' {
+		return fmt.Errorf("Error reading parameters: expected a json object")
+	}
+
+	decoded := Parameters{}
+	for decoder.More() {
+		token, err = decoder.Token()
+		if err != nil {
+			return err
+		}
+
+		var raw json.RawMessage
+		if err = decoder.Decode(&raw); err != nil {
+			return err
+		}
+
+		values, err := parameterValues(raw)
+		if err != nil {
+			return fmt.Errorf("Error reading parameter %s: %s", token, err)
+		}
+
+		decoded = append(decoded, &Parameter{Name: token.(string), Values: values})
+	}
+
+	*parameters = decoded
+	return nil
+}
+
+func parameterValues(raw json.RawMessage) ([]string, error) {
+	var values []string
+	if err := json.Unmarshal(raw, &values); err == nil {
+		return values, nil
+	}
+
+	var value string
+	if err := json.Unmarshal(raw, &value); err == nil {
+		return []string{value}, nil
+	}
+
+	// values the api returns as numbers or booleans
+	var items []interface{}
+	if err := json.Unmarshal(raw, &items); err != nil {
+		return nil, err
+	}
+
+	values = make([]string, 0, len(items))
+	for _, item := range items {
+		values = append(values, fmt.Sprintf("%v", item))
+	}
+
+	return values, nil
+}
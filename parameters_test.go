@@ -0,0 +1,104 @@
+package runscope
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestParametersJSON(t *testing.T) {
+	step := &TestStep{StepType: StepTypeRequest, Method: "POST"}
+	step.Headers.Add("X-Trace", "1")
+	step.Headers.Add("Accept", "application/json")
+	step.Headers.Add("Accept", "text/plain")
+	step.Form.Add("user", "bob")
+	step.Form.Add("password", "{{password}}")
+
+	data, err := json.Marshal(step)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"step_type":"request","headers":{"X-Trace":["1"],"Accept":["application/json","text/plain"]},` +
+		`"form":{"user":["bob"],"password":["{{password}}"]},"method":"POST"}`
+	if string(data) != expected {
+		t.Errorf("Expected %s, actual %s", expected, string(data))
+	}
+
+	decoded := new(TestStep)
+	if err = json.Unmarshal(data, decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(decoded.Headers, step.Headers) || !reflect.DeepEqual(decoded.Form, step.Form) {
+		t.Errorf("Expected order to be kept, actual %v %v", decoded.Headers, decoded.Form)
+	}
+
+	parameters := Parameters{}
+	if err = json.Unmarshal([]byte(`{"Accept": "text/plain"}`), &parameters); err != nil {
+		t.Fatal(err)
+	}
+
+	if parameters.Get("Accept")[0] != "text/plain" {
+		t.Errorf("Expected single value to be read, actual %v", parameters)
+	}
+
+	if err = json.Unmarshal([]byte(`["Accept"]`), &parameters); err == nil {
+		t.Error("Expected error for json array")
+	}
+}
+
+func TestParametersFromResponse(t *testing.T) {
+	responseBody := `{"data": {"name": "Sample", "steps": [{"step_type": "request",
+		"headers": {"X-Trace": ["1"], "Accept": ["application/json", "text/plain"]}, "form": {"b": [2], "a": "1"}}]}}`
+	responseMap := new(response)
+	if err := json.Unmarshal([]byte(responseBody), &responseMap); err != nil {
+		t.Fatal(err)
+	}
+
+	test, err := getTestFromResponse(responseMap.Data, responseMap.raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	step := test.Steps[0]
+	if len(step.Headers) != 2 || step.Headers[0].Name != "X-Trace" || step.Headers[1].Name != "Accept" {
+		t.Errorf("Expected headers in the order of the response, actual %v", step.Headers)
+	}
+
+	if len(step.Headers.Get("Accept")) != 2 {
+		t.Errorf("Expected %d accept values, actual %v", 2, step.Headers.Get("Accept"))
+	}
+
+	if len(step.Form) != 2 || step.Form[0].Name != "b" || step.Form.Get("b")[0] != "2" || step.Form.Get("a")[0] != "1" {
+		t.Errorf("Expected form parameters b=2 a=1, actual %v", step.Form)
+	}
+
+	decoded, err := getTestStepFromResponse(map[string]interface{}{
+		"headers": map[string]interface{}{"X-Trace": []interface{}{"1"}, "Accept": "application/json"},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(decoded.Headers) != 2 || decoded.Headers[0].Name != "Accept" {
+		t.Errorf("Expected headers of decoded maps ordered by name, actual %v", decoded.Headers)
+	}
+}
+
+func TestParametersSetDel(t *testing.T) {
+	var parameters Parameters
+	parameters.Add("a", "1")
+	parameters.Add("b", "2")
+	parameters.Add("c", "3")
+	parameters.Set("a", "10", "11")
+	parameters.Del("b")
+
+	if len(parameters) != 2 || parameters[0].Name != "a" || parameters[1].Name != "c" {
+		t.Errorf("Expected parameters a and c, actual %v", parameters)
+	}
+
+	if !reflect.DeepEqual(parameters.Get("a"), []string{"10", "11"}) || parameters.Has("b") {
+		t.Errorf("Expected a to be replaced and b removed, actual %v", parameters)
+	}
+}
@@ -0,0 +1,177 @@
+package runscope
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultRetryAfter is how long a client waits after being rate limited when the api doesn't say
+	defaultRetryAfter = time.Second
+	// fanOutConcurrency is how many requests the client makes at once to read many resources, e.g. BucketSummary
+	fanOutConcurrency = 8
+)
+
+// PoolOptions controls how a Pool runs functions, zero values use the defaults
+type PoolOptions struct {
+	// Concurrency is the number of functions running at the same time, defaults to 1
+	Concurrency int
+	// Rate caps how many functions start per second, unlimited when 0
+	Rate float64
+	// Retries is how often a function failing because the api rate limited the client is run again, defaults to 3,
+	// a negative value disables retries
+	Retries int
+}
+
+// Pool runs functions making requests with a client concurrently, e.g. a bulk update of many tests. Functions
+// don't start while the client is rate limited, and those that fail because the api rate limited the client, see
+// IsRateLimited, are run again once the api allows it. Errors are collected and returned by Wait
+type Pool struct {
+	client    *Client
+	options   PoolOptions
+	semaphore chan struct{}
+	wg        sync.WaitGroup
+	mu        sync.Mutex
+	// next is the earliest start of the next function allowed by Rate
+	next      time.Time
+	submitted int
+	errors    []error
+}
+
+// rateLimitState is when the api allows a client to make requests again after rejecting one
+type rateLimitState struct {
+	mu    sync.Mutex
+	until time.Time
+}
+
+// NewPool creates a pool running functions with the client
+func (client *Client) NewPool(options *PoolOptions) *Pool {
+	pool := &Pool{client: client, options: PoolOptions{Concurrency: 1, Retries: 3}}
+	if options != nil {
+		if options.Concurrency > 0 {
+			pool.options.Concurrency = options.Concurrency
+		}
+
+		if options.Rate > 0 {
+			pool.options.Rate = options.Rate
+		}
+
+		if options.Retries > 0 {
+			pool.options.Retries = options.Retries
+		} else if options.Retries < 0 {
+			pool.options.Retries = 0
+		}
+	}
+
+	pool.semaphore = make(chan struct{}, pool.options.Concurrency)
+	return pool
+}
+
+// Submit runs fn in the pool, it blocks while Concurrency functions are running
+func (pool *Pool) Submit(fn func() error) {
+	pool.semaphore <- struct{}{}
+	pool.wg.Add(1)
+
+	pool.mu.Lock()
+	pool.submitted++
+	pool.mu.Unlock()
+
+	go func() {
+		defer pool.wg.Done()
+		err := pool.run(fn)
+		<-pool.semaphore
+
+		if err != nil {
+			pool.mu.Lock()
+			pool.errors = append(pool.errors, err)
+			pool.mu.Unlock()
+		}
+	}()
+}
+
+// Wait waits for the submitted functions to finish, it returns an error when any of them failed, see Errors
+func (pool *Pool) Wait() error {
+	pool.wg.Wait()
+
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	if len(pool.errors) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("Error running pool: %d of %d functions failed, first: %s",
+		len(pool.errors), pool.submitted, pool.errors[0])
+}
+
+// Errors returns the errors of the functions that failed so far, in the order they failed
+func (pool *Pool) Errors() []error {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	return append([]error{}, pool.errors...)
+}
+
+func (pool *Pool) run(fn func() error) error {
+	for attempt := 0; ; attempt++ {
+		pool.waitTurn()
+		err := fn()
+		// the client recorded the rate limit, waitTurn waits for it before running fn again
+		if !IsRateLimited(err) || attempt >= pool.options.Retries {
+			return err
+		}
+	}
+}
+
+// waitTurn sleeps until the client is no longer rate limited and Rate allows another function to start
+func (pool *Pool) waitTurn() {
+	pool.mu.Lock()
+	now := time.Now()
+	start := now
+	if until := pool.client.rateLimitedUntil(); until.After(start) {
+		start = until
+	}
+
+	if pool.options.Rate > 0 {
+		if pool.next.After(start) {
+			start = pool.next
+		}
+		pool.next = start.Add(time.Duration(float64(time.Second) / pool.options.Rate))
+	}
+	pool.mu.Unlock()
+
+	time.Sleep(start.Sub(now))
+}
+
+// rateLimited records that the api rejected a request of the client, asking it to wait retryAfter
+func (client *Client) rateLimited(retryAfter time.Duration) {
+	if retryAfter <= 0 {
+		retryAfter = defaultRetryAfter
+	}
+
+	client.limit.mu.Lock()
+	defer client.limit.mu.Unlock()
+	if until := time.Now().Add(retryAfter); until.After(client.limit.until) {
+		client.limit.until = until
+	}
+}
+
+// rateLimitedError returns err as a *rateLimitError and records the rate limit when the api rejected the request
+// with 429 Too Many Requests
+func (client *Client) rateLimitedError(resp *http.Response, err error) error {
+	if resp.StatusCode != http.StatusTooManyRequests {
+		return err
+	}
+
+	limited := newRateLimitError(err, resp.Header.Get("Retry-After"))
+	client.rateLimited(limited.retryAfter)
+	return limited
+}
+
+// rateLimitedUntil returns when the api allows the client to make requests again, in the past if it is not rate
+// limited
+func (client *Client) rateLimitedUntil() time.Time {
+	client.limit.mu.Lock()
+	defer client.limit.mu.Unlock()
+	return client.limit.until
+}
@@ -0,0 +1,155 @@
+package runscope
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPoolConcurrency(t *testing.T) {
+	pool := NewClient("http://localhost", "token").NewPool(&PoolOptions{Concurrency: 2})
+	var mu sync.Mutex
+	running, maxRunning := 0, 0
+	for i := 0; i < 6; i++ {
+		i := i
+		pool.Submit(func() error {
+			mu.Lock()
+			running++
+			if running > maxRunning {
+				maxRunning = running
+			}
+			mu.Unlock()
+
+			time.Sleep(10 * time.Millisecond)
+
+			mu.Lock()
+			running--
+			mu.Unlock()
+			if i%3 == 0 {
+				return fmt.Errorf("failed %d", i)
+			}
+			return nil
+		})
+	}
+
+	err := pool.Wait()
+	if maxRunning != 2 {
+		t.Errorf("Expected 2 functions running at most, actual %d", maxRunning)
+	}
+
+	if err == nil || err.Error() != "Error running pool: 2 of 6 functions failed, first: "+pool.Errors()[0].Error() {
+		t.Errorf("Expected error of 2 failed functions, actual %v", err)
+	}
+
+	if len(pool.Errors()) != 2 {
+		t.Errorf("Expected 2 errors, actual %v", pool.Errors())
+	}
+}
+
+func TestPoolRate(t *testing.T) {
+	pool := NewClient("http://localhost", "token").NewPool(&PoolOptions{Concurrency: 4, Rate: 50})
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		pool.Submit(func() error { return nil })
+	}
+
+	if err := pool.Wait(); err != nil {
+		t.Fatal(err)
+	}
+
+	if elapsed := time.Since(start); elapsed < 60*time.Millisecond {
+		t.Errorf("Expected 4 starts at 50 per second to take at least 60ms, actual %s", elapsed)
+	}
+}
+
+func TestPoolRetriesRateLimited(t *testing.T) {
+	var mu sync.Mutex
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests++
+		if requests == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			fmt.Fprint(w, `{"error": {"status": 429, "message": "slow down"}}`)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "token")
+	pool := client.NewPool(nil)
+	start := time.Now()
+	pool.Submit(func() error {
+		if err := client.DeleteTest(&Test{ID: "1", Bucket: &Bucket{Key: "z3n32gktzx94"}}); err != nil {
+			return fmt.Errorf("Error cleaning up: %w", err)
+		}
+		return nil
+	})
+
+	if err := pool.Wait(); err != nil {
+		t.Fatal(err)
+	}
+
+	if requests != 2 {
+		t.Errorf("Expected the rate limited request to be retried, actual %d requests", requests)
+	}
+
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("Expected the retry to wait for Retry-After, actual %s", elapsed)
+	}
+
+	if !client.rateLimitedUntil().After(start) {
+		t.Errorf("Expected the client to record the rate limit")
+	}
+}
+
+func TestPoolRetriesDisabled(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusTooManyRequests)
+		fmt.Fprint(w, `{"error": {"status": 429, "message": "slow down"}}`)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "token")
+	pool := client.NewPool(&PoolOptions{Retries: -1})
+	pool.Submit(func() error {
+		return client.DeleteTest(&Test{ID: "1", Bucket: &Bucket{Key: "z3n32gktzx94"}})
+	})
+
+	if err := pool.Wait(); err == nil || !IsRateLimited(pool.Errors()[0]) {
+		t.Errorf("Expected the rate limit error, actual %v", err)
+	}
+
+	if requests != 1 {
+		t.Errorf("Expected no retries, actual %d requests", requests)
+	}
+}
+
+func TestIsRateLimited(t *testing.T) {
+	limited := newRateLimitError(errors.New("Status: 429 Too Many Requests"), "2")
+	if !IsRateLimited(limited) || !IsRateLimited(fmt.Errorf("Error deleting tests: %w", limited)) {
+		t.Error("Expected rate limit error to be detected, also when wrapped")
+	}
+
+	if IsRateLimited(errors.New("Status: 404 Not Found")) || IsRateLimited(nil) {
+		t.Error("Expected other errors not to be rate limited")
+	}
+}
+
+func TestDeleteResourceTransportError(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	if err := NewClient(server.URL, "token").DeleteTest(&Test{ID: "1", Bucket: &Bucket{Key: "z3n32gktzx94"}}); err == nil {
+		t.Error("Expected error for unreachable api")
+	}
+}
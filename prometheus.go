@@ -0,0 +1,156 @@
+package runscope
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// prometheusResults is how many of the latest results of a test Poll reads to find the newest finished run
+const prometheusResults = 5
+
+// PrometheusExporter polls the latest finished result of tests and exposes them in the Prometheus text format, as
+// the gauges runscope_test_up, 1 when the run passed and 0 otherwise, and runscope_test_response_ms, the duration of
+// the run. It is an http.Handler to be served on the path scraped by Prometheus
+type PrometheusExporter struct {
+	client   *Client
+	tests    []*Test
+	interval time.Duration
+
+	mu      sync.Mutex
+	samples map[string]*prometheusSample
+}
+
+type prometheusSample struct {
+	test   *Test
+	result *TestResult
+}
+
+// NewPrometheusExporter creates an exporter for tests, which must have their Bucket set. interval defaults to a minute
+func NewPrometheusExporter(client *Client, tests []*Test, interval time.Duration) *PrometheusExporter {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	return &PrometheusExporter{
+		client:   client,
+		tests:    tests,
+		interval: interval,
+		samples:  map[string]*prometheusSample{},
+	}
+}
+
+// Run polls the latest results every interval until ctx is done, errors reading a test are logged and its previous
+// sample is kept
+func (exporter *PrometheusExporter) Run(ctx context.Context) error {
+	ticker := time.NewTicker(exporter.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := exporter.Poll(); err != nil {
+			ErrorF(1, "Error polling runscope results: %s", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll reads the latest finished result of every test once, returning the first error. Runs in progress are
+// skipped, the previous sample of a test is kept while none of its latest runs has finished
+func (exporter *PrometheusExporter) Poll() error {
+	var firstErr error
+	for _, test := range exporter.tests {
+		results, err := exporter.client.ListResults(test, &ListResultsInput{Count: prometheusResults})
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+
+		for _, result := range results {
+			if result.Done() {
+				exporter.record(test, result)
+				break
+			}
+		}
+	}
+
+	return firstErr
+}
+
+func (exporter *PrometheusExporter) record(test *Test, result *TestResult) {
+	exporter.mu.Lock()
+	defer exporter.mu.Unlock()
+	exporter.samples[test.ID] = &prometheusSample{test: test, result: result}
+}
+
+func (exporter *PrometheusExporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	if err := exporter.WriteMetrics(w); err != nil {
+		ErrorF(1, "Error writing metrics: %s", err)
+	}
+}
+
+// WriteMetrics writes the latest samples in the Prometheus text format
+func (exporter *PrometheusExporter) WriteMetrics(writer io.Writer) error {
+	exporter.mu.Lock()
+	samples := make([]*prometheusSample, 0, len(exporter.samples))
+	for _, sample := range exporter.samples {
+		samples = append(samples, sample)
+	}
+	exporter.mu.Unlock()
+
+	sort.Slice(samples, func(i, j int) bool { return samples[i].test.Name < samples[j].test.Name })
+
+	var builder strings.Builder
+	builder.WriteString("# HELP runscope_test_up Whether the latest run of the test passed.\n")
+	builder.WriteString("# TYPE runscope_test_up gauge\n")
+	for _, sample := range samples {
+		up := 0
+		if sample.result.Result == TestResultPass {
+			up = 1
+		}
+		fmt.Fprintf(&builder, "runscope_test_up{%s} %d\n", prometheusLabels(sample), up)
+	}
+
+	builder.WriteString("# HELP runscope_test_response_ms Duration of the latest run of the test in milliseconds.\n")
+	builder.WriteString("# TYPE runscope_test_response_ms gauge\n")
+	for _, sample := range samples {
+		if sample.result.StartedAt == nil || sample.result.FinishedAt == nil {
+			continue
+		}
+
+		duration := sample.result.FinishedAt.Sub(*sample.result.StartedAt) / time.Millisecond
+		fmt.Fprintf(&builder, "runscope_test_response_ms{%s} %d\n", prometheusLabels(sample), duration)
+	}
+
+	_, err := io.WriteString(writer, builder.String())
+	return err
+}
+
+func prometheusLabels(sample *prometheusSample) string {
+	bucketKey := BucketKey("")
+	if sample.test.Bucket != nil {
+		bucketKey = sample.test.Bucket.Key
+	}
+
+	return fmt.Sprintf(`bucket_key="%s",test_id="%s",test_name="%s",region="%s"`,
+		prometheusEscape(string(bucketKey)), prometheusEscape(sample.test.ID), prometheusEscape(sample.test.Name),
+		prometheusEscape(sample.result.Region))
+}
+
+var prometheusEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func prometheusEscape(value string) string {
+	return prometheusEscaper.Replace(value)
+}
@@ -0,0 +1,57 @@
+package runscope
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPrometheusExporterMetrics(t *testing.T) {
+	start := time.Date(2021, 5, 6, 10, 0, 0, 0, time.UTC)
+	bucket := &Bucket{Key: "z3n32gktzx94"}
+	exporter := NewPrometheusExporter(nil, nil, 0)
+
+	passed := aggregateRun(TestResultPass, start, 0, 1500*time.Millisecond)
+	passed.Region = "us1"
+	exporter.record(&Test{ID: "1", Name: "Health", Bucket: bucket}, passed)
+	exporter.record(&Test{ID: "2", Name: `Users "v2"`, Bucket: bucket}, &TestResult{Result: TestResultFail})
+
+	recorder := httptest.NewRecorder()
+	exporter.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	body := recorder.Body.String()
+
+	for _, expected := range []string{
+		`runscope_test_up{bucket_key="z3n32gktzx94",test_id="1",test_name="Health",region="us1"} 1`,
+		`runscope_test_up{bucket_key="z3n32gktzx94",test_id="2",test_name="Users \"v2\"",region=""} 0`,
+		`runscope_test_response_ms{bucket_key="z3n32gktzx94",test_id="1",test_name="Health",region="us1"} 1500`,
+		"# TYPE runscope_test_up gauge",
+	} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("Expected metrics to contain %s, actual\n%s", expected, body)
+		}
+	}
+
+	if strings.Contains(body, `runscope_test_response_ms{bucket_key="z3n32gktzx94",test_id="2"`) {
+		t.Errorf("Expected no duration for an unfinished run, actual\n%s", body)
+	}
+}
+
+func TestPrometheusExporterPollSkipsRunning(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data": [{"test_run_id": "2", "result": "working"}, {"test_run_id": "1", "result": "pass"}]}`)
+	}))
+	defer server.Close()
+
+	test := &Test{ID: "1", Name: "Health", Bucket: &Bucket{Key: "z3n32gktzx94"}}
+	exporter := NewPrometheusExporter(NewClient(server.URL, "token"), []*Test{test}, 0)
+	if err := exporter.Poll(); err != nil {
+		t.Fatal(err)
+	}
+
+	if sample := exporter.samples["1"]; sample == nil || sample.result.TestRunID != "1" {
+		t.Errorf("Expected the finished run 1 to be sampled, actual %v", sample)
+	}
+}
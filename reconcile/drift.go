@@ -0,0 +1,127 @@
+package reconcile
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	"github.com/ewilde/go-runscope"
+)
+
+// Drift statuses of a resource
+const (
+	// DriftMissing resources are specified but don't exist remotely
+	DriftMissing = "missing"
+	// DriftModified resources exist remotely but differ from their spec
+	DriftModified = "modified"
+	// DriftUnmanaged resources exist remotely in a specified bucket but are not specified
+	DriftUnmanaged = "unmanaged"
+)
+
+// Drift is a resource whose remote state doesn't match its spec
+type Drift struct {
+	Status string `json:"status"`
+	Kind   string `json:"kind"`
+	Path   string `json:"path"`
+	// Fields are the json names of the fields of a modified resource that differ
+	Fields []string `json:"fields,omitempty"`
+}
+
+// DriftReport lists the resources that drifted from their specs, it encodes to json for audit pipelines
+type DriftReport struct {
+	Drift []*Drift `json:"drift"`
+}
+
+// HasDrift reports whether any resource drifted
+func (report *DriftReport) HasDrift() bool {
+	return len(report.Drift) > 0
+}
+
+// DetectDrift compares the remote state of the buckets of specs to them without changing anything, it is the
+// read-only half of Reconciler.Reconcile
+func DetectDrift(specs []*BucketSpec, client runscope.ClientAPI) (*DriftReport, error) {
+	return NewReconciler(client).DetectDrift(specs)
+}
+
+// DetectDrift compares the remote state of the buckets of specs to them, see DetectDrift, resolving their secrets
+// with the providers of the reconciler
+func (reconciler *Reconciler) DetectDrift(specs []*BucketSpec) (*DriftReport, error) {
+	plan, err := reconciler.Plan(specs)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &DriftReport{Drift: []*Drift{}}
+	for _, change := range plan.Changes {
+		drift := &Drift{Kind: change.Kind, Path: change.Path}
+		switch change.Action {
+		case Create:
+			drift.Status = DriftMissing
+		case Delete:
+			drift.Status = DriftUnmanaged
+		case Update:
+			drift.Status = DriftModified
+			drift.Fields = changedFields(change)
+		}
+
+		report.Drift = append(report.Drift, drift)
+	}
+
+	return report, nil
+}
+
+// changedFields lists the fields of an updated resource that differ from its spec
+func changedFields(change *Change) []string {
+	var desired, current interface{}
+	switch change.Kind {
+	case KindTest:
+		desiredTest, currentTest := change.Desired.(*runscope.Test), change.Current.(*runscope.Test)
+		desired = comparableTest(desiredTest)
+		current = comparableTest(currentTest)
+	case KindEnvironment:
+		currentEnvironment := change.Current.(*runscope.Environment)
+		desired = comparableEnvironment(change.Desired.(*runscope.Environment), currentEnvironment.ID)
+		current = comparableEnvironment(currentEnvironment, currentEnvironment.ID)
+	case KindSchedule:
+		return []string{"note"}
+	}
+
+	return diffFields(desired, current)
+}
+
+// comparableTest returns the fields of a test that are specified, in normalized form
+func comparableTest(test *runscope.Test) *runscope.Test {
+	return (&runscope.Test{Name: test.Name, Description: test.Description, Steps: test.Steps}).Normalize()
+}
+
+// diffFields returns the names of the top-level json fields that differ between two values
+func diffFields(desired interface{}, current interface{}) []string {
+	left, right := jsonFields(desired), jsonFields(current)
+	names := map[string]bool{}
+	for name := range left {
+		names[name] = true
+	}
+
+	for name := range right {
+		names[name] = true
+	}
+
+	var fields []string
+	for name := range names {
+		if !reflect.DeepEqual(left[name], right[name]) {
+			fields = append(fields, name)
+		}
+	}
+
+	sort.Strings(fields)
+	return fields
+}
+
+func jsonFields(value interface{}) map[string]interface{} {
+	fields := map[string]interface{}{}
+	if data, err := json.Marshal(value); err == nil {
+		json.Unmarshal(data, &fields)
+	}
+
+	return fields
+}
@@ -0,0 +1,48 @@
+package reconcile
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ewilde/go-runscope"
+)
+
+func TestDetectDrift(t *testing.T) {
+	client := newFakeClient()
+	bucket := &runscope.Bucket{Name: "Production", Key: "z3n32gktzx94", Team: &runscope.Team{ID: "acme"}}
+	client.buckets = []*runscope.Bucket{bucket}
+	client.environments[bucket.Key] = []*runscope.Environment{
+		{ID: "1", Name: "prod", InitialVariables: map[string]string{"baseUrl": "https://old.example.com"}},
+	}
+	client.tests[bucket.Key] = []*runscope.Test{
+		{ID: "10", Name: "Health", Description: "Checks the health endpoint"},
+		{ID: "11", Name: "Obsolete"},
+	}
+
+	report, err := DetectDrift(loadSpec(t, productionSpec), client)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(client.calls) != 0 {
+		t.Errorf("Expected drift detection not to change anything, actual %v", client.calls)
+	}
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"drift":[` +
+		`{"status":"modified","kind":"environment","path":"Production/prod","fields":["initial_variables"]},` +
+		`{"status":"modified","kind":"test","path":"Production/Health","fields":["steps"]},` +
+		`{"status":"missing","kind":"schedule","path":"Production/Health/prod@5m"},` +
+		`{"status":"unmanaged","kind":"test","path":"Production/Obsolete"}]}`
+	if string(data) != expected {
+		t.Errorf("Expected report\n%s\nactual\n%s", expected, data)
+	}
+
+	if !report.HasDrift() {
+		t.Error("Expected report to have drift")
+	}
+}
@@ -0,0 +1,455 @@
+package reconcile
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/ewilde/go-runscope"
+)
+
+// Action is what applying a Change does to a resource
+type Action string
+
+// Actions of a Change
+const (
+	Create Action = "create"
+	Update Action = "update"
+	Delete Action = "delete"
+)
+
+// Kinds of resources changed by a plan
+const (
+	KindBucket      = "bucket"
+	KindEnvironment = "environment"
+	KindTest        = "test"
+	KindSchedule    = "schedule"
+)
+
+// phases order the changes of a plan so resources exist before they are referenced and references are removed
+// before the resources they reference
+const (
+	phaseCreateBucket = iota
+	phaseSharedEnvironment
+	phaseTest
+	phaseTestEnvironment
+	phaseSchedule
+	phaseDeleteSchedule
+	phaseDeleteTestEnvironment
+	phaseDeleteTest
+	phaseDeleteSharedEnvironment
+)
+
+// Change is a single create, update or delete of a plan
+type Change struct {
+	Action Action `json:"action"`
+	Kind   string `json:"kind"`
+	// Path names the resource by the names of the resources containing it, e.g. Production/Health/prod for the
+	// environment prod of test Health in bucket Production. Schedules are named by environment and interval
+	Path string `json:"path"`
+	// Desired is the resource as specified, nil for deletes, Current the resource as it exists, nil for creates. Both
+	// hold resolved secrets and are left out of the json encoding
+	Desired interface{} `json:"-"`
+	Current interface{} `json:"-"`
+
+	phase int
+	apply func(client runscope.ClientAPI) error
+}
+
+func (change *Change) String() string {
+	symbol := map[Action]string{Create: "+", Update: "~", Delete: "-"}[change.Action]
+	return fmt.Sprintf("%s %s %s", symbol, change.Kind, change.Path)
+}
+
+// Plan is the ordered list of changes making the remote state match the specs
+type Plan struct {
+	Changes []*Change
+}
+
+// Empty reports whether the remote state already matches the specs
+func (plan *Plan) Empty() bool {
+	return len(plan.Changes) == 0
+}
+
+func (plan *Plan) String() string {
+	var lines []string
+	for _, change := range plan.Changes {
+		lines = append(lines, change.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (plan *Plan) add(change *Change) {
+	plan.Changes = append(plan.Changes, change)
+}
+
+// references to resources shared between the changes of a plan, set once the resource is created
+type bucketRef struct{ bucket *runscope.Bucket }
+type testRef struct{ test *runscope.Test }
+type environmentRef struct{ environment *runscope.Environment }
+
+// remoteBucket is the remote state of the bucket of a spec
+type remoteBucket struct {
+	bucket       *runscope.Bucket
+	environments []*runscope.Environment
+	tests        map[string]*remoteTest
+}
+
+type remoteTest struct {
+	test         *runscope.Test
+	environments []*runscope.Environment
+	schedules    []*runscope.Schedule
+}
+
+// Plan reads the remote state of the buckets of specs and computes the changes making it match them. Tests,
+// environments and schedules of those buckets that are not specified are deleted, except the default environment
+// of a test, other buckets are left alone
+func (reconciler *Reconciler) Plan(specs []*BucketSpec) (*Plan, error) {
+	if err := Validate(specs); err != nil {
+		return nil, err
+	}
+
+	specs, err := resolveSecrets(specs, reconciler.Secrets)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{}
+	teams := map[string][]*runscope.Bucket{}
+	for _, spec := range specs {
+		buckets, ok := teams[spec.Team]
+		if !ok {
+			if buckets, err = reconciler.Client.ListBuckets(&runscope.ListBucketsInput{TeamID: spec.Team}); err != nil {
+				return nil, err
+			}
+			teams[spec.Team] = buckets
+		}
+
+		remote, err := reconciler.readBucket(spec, buckets)
+		if err != nil {
+			return nil, err
+		}
+
+		planBucket(plan, spec, remote)
+	}
+
+	sort.SliceStable(plan.Changes, func(i, j int) bool { return plan.Changes[i].phase < plan.Changes[j].phase })
+	return plan, nil
+}
+
+// readBucket reads the remote state of the bucket of spec, nil if the bucket does not exist
+func (reconciler *Reconciler) readBucket(spec *BucketSpec, buckets []*runscope.Bucket) (*remoteBucket, error) {
+	var bucket *runscope.Bucket
+	for _, candidate := range buckets {
+		if candidate.Name != spec.Name {
+			continue
+		}
+
+		if bucket != nil {
+			return nil, fmt.Errorf("Error reading bucket %s: several buckets of team %s have this name", spec.Name, spec.Team)
+		}
+		bucket = candidate
+	}
+
+	if bucket == nil {
+		return nil, nil
+	}
+
+	client := reconciler.Client
+	environments, err := client.ListSharedEnvironment(bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	tests, err := client.ListAllTests(&runscope.ListTestsInput{BucketKey: bucket.Key})
+	if err != nil {
+		return nil, err
+	}
+
+	remote := &remoteBucket{bucket: bucket, environments: environments, tests: map[string]*remoteTest{}}
+	for _, test := range tests {
+		test.Bucket = bucket
+		if _, ok := remote.tests[test.Name]; ok {
+			return nil, fmt.Errorf("Error reading test %s/%s: several tests have this name", bucket.Name, test.Name)
+		}
+
+		detail, err := client.ReadTest(test)
+		if err != nil {
+			return nil, err
+		}
+
+		testEnvironments, err := client.ListTestEnvironment(bucket, test)
+		if err != nil {
+			return nil, err
+		}
+
+		schedules, err := client.ListSchedules(bucket.Key, test.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		remote.tests[test.Name] = &remoteTest{test: detail, environments: testEnvironments, schedules: schedules}
+	}
+
+	return remote, nil
+}
+
+func planBucket(plan *Plan, spec *BucketSpec, remote *remoteBucket) {
+	bucket := &bucketRef{}
+	if remote == nil {
+		remote = &remoteBucket{tests: map[string]*remoteTest{}}
+		plan.add(&Change{Action: Create, Kind: KindBucket, Path: spec.Name, Desired: spec, phase: phaseCreateBucket,
+			apply: func(client runscope.ClientAPI) error {
+				created, err := client.CreateBucket(&runscope.Bucket{Name: spec.Name, Team: &runscope.Team{ID: spec.Team}})
+				bucket.bucket = created
+				return err
+			}})
+	} else {
+		bucket.bucket = remote.bucket
+	}
+
+	shared := planEnvironments(plan, spec.Name, spec.Environments, remote.environments, "",
+		phaseSharedEnvironment, phaseDeleteSharedEnvironment, &environmentOperations{
+			create: func(client runscope.ClientAPI, environment *runscope.Environment) (*runscope.Environment, error) {
+				return client.CreateSharedEnvironment(environment, bucket.bucket)
+			},
+			update: func(client runscope.ClientAPI, environment *runscope.Environment) (*runscope.Environment, error) {
+				return client.UpdateSharedEnvironment(environment, bucket.bucket)
+			},
+			delete: func(client runscope.ClientAPI, environment *runscope.Environment) error {
+				return client.DeleteEnvironment(environment, bucket.bucket)
+			},
+		})
+
+	specified := map[string]bool{}
+	for _, test := range spec.Tests {
+		specified[test.Name] = true
+		planTest(plan, spec.Name+"/"+test.Name, test, remote.tests[test.Name], bucket, shared, remote.environments)
+	}
+
+	var names []string
+	for name := range remote.tests {
+		if !specified[name] {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+	for _, name := range names {
+		current := remote.tests[name].test
+		plan.add(&Change{Action: Delete, Kind: KindTest, Path: spec.Name + "/" + name, Current: current,
+			phase: phaseDeleteTest, apply: func(client runscope.ClientAPI) error { return client.DeleteTest(current) }})
+	}
+}
+
+func planTest(plan *Plan, path string, spec *TestSpec, remote *remoteTest, bucket *bucketRef,
+	shared map[string]*environmentRef, sharedEnvironments []*runscope.Environment) {
+	desired := &runscope.Test{Name: spec.Name, Description: spec.Description, Steps: spec.Steps}
+	test := &testRef{}
+	if remote == nil {
+		remote = &remoteTest{}
+		plan.add(&Change{Action: Create, Kind: KindTest, Path: path, Desired: desired, phase: phaseTest,
+			apply: func(client runscope.ClientAPI) error {
+				created, err := client.CreateTest(&runscope.Test{
+					Name: spec.Name, Description: spec.Description, Bucket: bucket.bucket})
+				if err != nil {
+					return err
+				}
+
+				test.test = created
+				for _, step := range spec.Steps {
+					newStep := *step
+					newStep.ID = ""
+					if _, err := client.CreateTestStep(&newStep, created.Bucket.Key, created.ID); err != nil {
+						return err
+					}
+				}
+
+				return nil
+			}})
+	} else {
+		test.test = remote.test
+		if !desired.Equal(remote.test) {
+			current := remote.test
+			plan.add(&Change{Action: Update, Kind: KindTest, Path: path, Desired: desired, Current: current,
+				phase: phaseTest, apply: func(client runscope.ClientAPI) error {
+					_, err := client.UpdateTest(&runscope.Test{ID: current.ID, Bucket: current.Bucket,
+						Name: spec.Name, Description: spec.Description, Steps: spec.Steps,
+						DefaultEnvironmentID: current.DefaultEnvironmentID})
+					return err
+				}})
+		}
+	}
+
+	environments := planEnvironments(plan, path, spec.Environments, remote.environments,
+		test.defaultEnvironmentID(), phaseTestEnvironment, phaseDeleteTestEnvironment, &environmentOperations{
+			create: func(client runscope.ClientAPI, environment *runscope.Environment) (*runscope.Environment, error) {
+				return client.CreateTestEnvironment(environment, test.test)
+			},
+			update: func(client runscope.ClientAPI, environment *runscope.Environment) (*runscope.Environment, error) {
+				return client.UpdateTestEnvironment(environment, test.test)
+			},
+			delete: func(client runscope.ClientAPI, environment *runscope.Environment) error {
+				return client.DeleteEnvironment(environment, test.test.Bucket)
+			},
+		})
+
+	planSchedules(plan, path, spec.Schedules, remote, test, environments, shared, sharedEnvironments)
+}
+
+func (test *testRef) defaultEnvironmentID() string {
+	if test.test == nil {
+		return ""
+	}
+
+	return test.test.DefaultEnvironmentID
+}
+
+type environmentOperations struct {
+	create func(client runscope.ClientAPI, environment *runscope.Environment) (*runscope.Environment, error)
+	update func(client runscope.ClientAPI, environment *runscope.Environment) (*runscope.Environment, error)
+	delete func(client runscope.ClientAPI, environment *runscope.Environment) error
+}
+
+// planEnvironments plans the changes of the environments of a bucket or test and returns references to the
+// specified environments by name. The environment with the ID keep is never deleted
+func planEnvironments(plan *Plan, path string, desired []*runscope.Environment, current []*runscope.Environment,
+	keep string, phase int, deletePhase int, operations *environmentOperations) map[string]*environmentRef {
+	existing := map[string]*runscope.Environment{}
+	for _, environment := range current {
+		existing[environment.Name] = environment
+	}
+
+	refs := map[string]*environmentRef{}
+	for _, environment := range desired {
+		environment := environment
+		ref := &environmentRef{}
+		refs[environment.Name] = ref
+
+		currentEnvironment, ok := existing[environment.Name]
+		if !ok {
+			plan.add(&Change{Action: Create, Kind: KindEnvironment, Path: path + "/" + environment.Name,
+				Desired: environment, phase: phase, apply: func(client runscope.ClientAPI) error {
+					newEnvironment := *environment
+					newEnvironment.ID = ""
+					created, err := operations.create(client, &newEnvironment)
+					ref.environment = created
+					return err
+				}})
+			continue
+		}
+
+		ref.environment = currentEnvironment
+		delete(existing, environment.Name)
+		if environmentsEqual(environment, currentEnvironment) {
+			continue
+		}
+
+		plan.add(&Change{Action: Update, Kind: KindEnvironment, Path: path + "/" + environment.Name,
+			Desired: environment, Current: currentEnvironment, phase: phase,
+			apply: func(client runscope.ClientAPI) error {
+				updated := *environment
+				updated.ID = currentEnvironment.ID
+				_, err := operations.update(client, &updated)
+				return err
+			}})
+	}
+
+	for _, environment := range current {
+		environment := environment
+		if _, ok := existing[environment.Name]; !ok || environment.ID == keep {
+			continue
+		}
+
+		plan.add(&Change{Action: Delete, Kind: KindEnvironment, Path: path + "/" + environment.Name,
+			Current: environment, phase: deletePhase, apply: func(client runscope.ClientAPI) error {
+				return operations.delete(client, environment)
+			}})
+	}
+
+	return refs
+}
+
+// environmentsEqual compares environments in their comparable form
+func environmentsEqual(desired *runscope.Environment, current *runscope.Environment) bool {
+	return reflect.DeepEqual(comparableEnvironment(desired, current.ID), comparableEnvironment(current, current.ID))
+}
+
+// comparableEnvironment returns the normalized environment with the given ID, integrations are compared by ID only
+// as the api fills in their description
+func comparableEnvironment(environment *runscope.Environment, id string) *runscope.Environment {
+	comparable := environment.Normalize()
+	comparable.ID = id
+	for _, integration := range comparable.Integrations {
+		integration.Description = ""
+	}
+
+	return comparable
+}
+
+func planSchedules(plan *Plan, path string, desired []*ScheduleSpec, remote *remoteTest, test *testRef,
+	environments map[string]*environmentRef, shared map[string]*environmentRef,
+	sharedEnvironments []*runscope.Environment) {
+	names := map[string]string{}
+	for _, environment := range append(append([]*runscope.Environment{}, sharedEnvironments...), remote.environments...) {
+		names[environment.ID] = environment.Name
+	}
+
+	existing := map[string]*runscope.Schedule{}
+	for _, schedule := range remote.schedules {
+		key := (&ScheduleSpec{Environment: names[schedule.EnvironmentID], Interval: schedule.Interval}).key()
+		if _, ok := existing[key]; ok || names[schedule.EnvironmentID] == "" {
+			key = schedule.ID
+		}
+
+		existing[key] = schedule
+	}
+
+	for _, spec := range desired {
+		spec := spec
+		environment, ok := environments[spec.Environment]
+		if !ok {
+			environment = shared[spec.Environment]
+		}
+
+		schedulePath := path + "/" + spec.key()
+		current, ok := existing[spec.key()]
+		if !ok {
+			plan.add(&Change{Action: Create, Kind: KindSchedule, Path: schedulePath, Desired: spec,
+				phase: phaseSchedule, apply: func(client runscope.ClientAPI) error {
+					_, err := client.CreateSchedule(&runscope.Schedule{EnvironmentID: environment.environment.ID,
+						Interval: spec.Interval, Note: spec.Note}, test.test.Bucket.Key, test.test.ID)
+					return err
+				}})
+			continue
+		}
+
+		delete(existing, spec.key())
+		if current.Note == spec.Note {
+			continue
+		}
+
+		plan.add(&Change{Action: Update, Kind: KindSchedule, Path: schedulePath, Desired: spec, Current: current,
+			phase: phaseSchedule, apply: func(client runscope.ClientAPI) error {
+				_, err := client.UpdateSchedule(&runscope.Schedule{ID: current.ID, EnvironmentID: current.EnvironmentID,
+					Interval: current.Interval, Note: spec.Note}, test.test.Bucket.Key, test.test.ID)
+				return err
+			}})
+	}
+
+	var keys []string
+	for key := range existing {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	for _, key := range keys {
+		current := existing[key]
+		plan.add(&Change{Action: Delete, Kind: KindSchedule, Path: path + "/" + key, Current: current,
+			phase: phaseDeleteSchedule, apply: func(client runscope.ClientAPI) error {
+				return client.DeleteSchedule(current, test.test.Bucket.Key, test.test.ID)
+			}})
+	}
+}
@@ -0,0 +1,51 @@
+package reconcile
+
+import (
+	"fmt"
+
+	"github.com/ewilde/go-runscope"
+)
+
+// Reconciler makes the remote state of buckets match their specs
+type Reconciler struct {
+	Client runscope.ClientAPI
+	// DryRun only plans the changes, Reconcile returns the plan without applying it
+	DryRun bool
+	// Secrets are the providers resolving the ${secret:PROVIDER:REFERENCE} placeholders of the initial variables
+	// and headers of environments and the headers of steps, by provider name. Secrets are resolved when planning
+	// so specs compare with the remote state, they are only kept in memory
+	Secrets map[string]SecretProvider
+}
+
+// NewReconciler creates a reconciler managing the buckets of client
+func NewReconciler(client runscope.ClientAPI) *Reconciler {
+	return &Reconciler{Client: client}
+}
+
+// Reconcile plans the changes making the remote state match specs and applies them unless DryRun is set, it
+// returns the plan
+func (reconciler *Reconciler) Reconcile(specs []*BucketSpec) (*Plan, error) {
+	plan, err := reconciler.Plan(specs)
+	if err != nil {
+		return nil, err
+	}
+
+	if reconciler.DryRun {
+		return plan, nil
+	}
+
+	return plan, reconciler.Apply(plan)
+}
+
+// Apply applies the changes of a plan in order, stopping at the first that fails. Changes applied before are not
+// rolled back, planning again picks up where it stopped
+func (reconciler *Reconciler) Apply(plan *Plan) error {
+	for _, change := range plan.Changes {
+		runscope.DebugF(1, "applying %s", change)
+		if err := change.apply(reconciler.Client); err != nil {
+			return fmt.Errorf("Error applying %s: %s", change, err)
+		}
+	}
+
+	return nil
+}
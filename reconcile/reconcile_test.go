@@ -0,0 +1,273 @@
+package reconcile
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ewilde/go-runscope"
+)
+
+const productionSpec = `
+name: Production
+team: acme
+environments:
+  - name: prod
+    initial_variables:
+      baseUrl: https://example.com
+tests:
+  - name: Health
+    description: Checks the health endpoint
+    steps:
+      - step_type: request
+        method: GET
+        url: "{{baseUrl}}/health"
+        assertions:
+          - {source: response_status, comparison: equal_number, value: 200}
+    schedules:
+      - {environment: prod, interval: 5m, note: every 5 minutes}
+`
+
+// fakeClient keeps buckets in memory, calls of methods it doesn't implement panic
+type fakeClient struct {
+	runscope.ClientAPI
+	buckets      []*runscope.Bucket
+	environments map[runscope.BucketKey][]*runscope.Environment
+	tests        map[runscope.BucketKey][]*runscope.Test
+	schedules    map[string][]*runscope.Schedule
+	calls        []string
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{
+		environments: map[runscope.BucketKey][]*runscope.Environment{},
+		tests:        map[runscope.BucketKey][]*runscope.Test{},
+		schedules:    map[string][]*runscope.Schedule{},
+	}
+}
+
+func (client *fakeClient) call(format string, args ...interface{}) {
+	client.calls = append(client.calls, fmt.Sprintf(format, args...))
+}
+
+func (client *fakeClient) ListBuckets(input *runscope.ListBucketsInput) ([]*runscope.Bucket, error) {
+	return client.buckets, nil
+}
+
+func (client *fakeClient) CreateBucket(bucket *runscope.Bucket) (*runscope.Bucket, error) {
+	client.call("create bucket %s", bucket.Name)
+	created := &runscope.Bucket{Name: bucket.Name, Key: "bucket000001", Team: bucket.Team}
+	client.buckets = append(client.buckets, created)
+	return created, nil
+}
+
+func (client *fakeClient) ListSharedEnvironment(bucket *runscope.Bucket) ([]*runscope.Environment, error) {
+	return client.environments[bucket.Key], nil
+}
+
+func (client *fakeClient) CreateSharedEnvironment(environment *runscope.Environment, bucket *runscope.Bucket) (*runscope.Environment, error) {
+	client.call("create environment %s in %s", environment.Name, bucket.Key)
+	created := *environment
+	created.ID = "environment-" + environment.Name
+	client.environments[bucket.Key] = append(client.environments[bucket.Key], &created)
+	return &created, nil
+}
+
+func (client *fakeClient) UpdateSharedEnvironment(environment *runscope.Environment, bucket *runscope.Bucket) (*runscope.Environment, error) {
+	client.call("update environment %s", environment.ID)
+	return environment, nil
+}
+
+func (client *fakeClient) DeleteEnvironment(environment *runscope.Environment, bucket *runscope.Bucket) error {
+	client.call("delete environment %s", environment.ID)
+	return nil
+}
+
+func (client *fakeClient) ListAllTests(input *runscope.ListTestsInput) ([]*runscope.Test, error) {
+	return client.tests[input.BucketKey], nil
+}
+
+func (client *fakeClient) ReadTest(test *runscope.Test) (*runscope.Test, error) {
+	for _, existing := range client.tests[test.Bucket.Key] {
+		if existing.ID == test.ID {
+			read := *existing
+			read.Bucket = test.Bucket
+			return &read, nil
+		}
+	}
+
+	return nil, fmt.Errorf("test %s not found", test.ID)
+}
+
+func (client *fakeClient) CreateTest(test *runscope.Test) (*runscope.Test, error) {
+	client.call("create test %s in %s", test.Name, test.Bucket.Key)
+	created := &runscope.Test{ID: "test-" + test.Name, Name: test.Name, Description: test.Description, Bucket: test.Bucket}
+	client.tests[test.Bucket.Key] = append(client.tests[test.Bucket.Key], created)
+	return created, nil
+}
+
+func (client *fakeClient) CreateTestStep(step *runscope.TestStep, bucketKey runscope.BucketKey, testID string) (*runscope.TestStep, error) {
+	client.call("create step %s %s in %s", step.Method, step.URL, testID)
+	return step, nil
+}
+
+func (client *fakeClient) UpdateTest(test *runscope.Test) (*runscope.Test, error) {
+	client.call("update test %s with %d steps", test.ID, len(test.Steps))
+	return test, nil
+}
+
+func (client *fakeClient) DeleteTest(test *runscope.Test) error {
+	client.call("delete test %s", test.ID)
+	return nil
+}
+
+func (client *fakeClient) ListTestEnvironment(bucket *runscope.Bucket, test *runscope.Test) ([]*runscope.Environment, error) {
+	return nil, nil
+}
+
+func (client *fakeClient) ListSchedules(bucketKey runscope.BucketKey, testID string) ([]*runscope.Schedule, error) {
+	return client.schedules[testID], nil
+}
+
+func (client *fakeClient) CreateSchedule(schedule *runscope.Schedule, bucketKey runscope.BucketKey, testID string) (*runscope.Schedule, error) {
+	client.call("create schedule %s %s in %s", schedule.EnvironmentID, schedule.Interval, testID)
+	return schedule, nil
+}
+
+func (client *fakeClient) DeleteSchedule(schedule *runscope.Schedule, bucketKey runscope.BucketKey, testID string) error {
+	client.call("delete schedule %s", schedule.ID)
+	return nil
+}
+
+func loadSpec(t *testing.T, spec string) []*BucketSpec {
+	specs, err := Load(strings.NewReader(spec), "production.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return specs
+}
+
+func TestLoad(t *testing.T) {
+	specs := loadSpec(t, productionSpec+"---\nname: Staging\nteam: acme\n")
+	if len(specs) != 2 || specs[1].Name != "Staging" {
+		t.Fatalf("Expected buckets Production and Staging, actual %v", specs)
+	}
+
+	test := specs[0].Tests[0]
+	if len(test.Steps) != 1 || test.Steps[0].URL != "{{baseUrl}}/health" || test.Steps[0].Assertions[0].Value != float64(200) {
+		t.Errorf("Expected health request step, actual %v", test.Steps)
+	}
+
+	if specs[0].Environments[0].InitialVariables["baseUrl"] != "https://example.com" {
+		t.Errorf("Expected initial variable baseUrl, actual %v", specs[0].Environments[0].InitialVariables)
+	}
+
+	if err := Validate(specs); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, spec := range []string{
+		"name: Production\n",
+		productionSpec + "---\n" + productionSpec,
+		strings.Replace(productionSpec, "environment: prod", "environment: staging", 1),
+		strings.Replace(productionSpec, "interval: 5m", "interval: 2m", 1),
+	} {
+		if err := Validate(loadSpec(t, spec)); err == nil {
+			t.Errorf("Expected spec to be invalid\n%s", spec)
+		}
+	}
+}
+
+func TestReconcileNewBucket(t *testing.T) {
+	client := newFakeClient()
+	reconciler := NewReconciler(client)
+	reconciler.DryRun = true
+
+	plan, err := reconciler.Reconcile(loadSpec(t, productionSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "+ bucket Production\n+ environment Production/prod\n+ test Production/Health\n+ schedule Production/Health/prod@5m"
+	if plan.String() != expected {
+		t.Errorf("Expected plan\n%s\nactual\n%s", expected, plan)
+	}
+
+	if len(client.calls) != 0 {
+		t.Errorf("Expected dry run not to change anything, actual %v", client.calls)
+	}
+
+	if err := reconciler.Apply(plan); err != nil {
+		t.Fatal(err)
+	}
+
+	calls := strings.Join(client.calls, "\n")
+	expected = `create bucket Production
+create environment prod in bucket000001
+create test Health in bucket000001
+create step GET {{baseUrl}}/health in test-Health
+create schedule environment-prod 5m in test-Health`
+	if calls != expected {
+		t.Errorf("Expected calls\n%s\nactual\n%s", expected, calls)
+	}
+}
+
+func TestReconcileExistingBucket(t *testing.T) {
+	client := newFakeClient()
+	bucket := &runscope.Bucket{Name: "Production", Key: "z3n32gktzx94", Team: &runscope.Team{ID: "acme"}}
+	client.buckets = []*runscope.Bucket{bucket}
+	client.environments[bucket.Key] = []*runscope.Environment{
+		{ID: "1", Name: "prod", InitialVariables: map[string]string{"baseUrl": "https://old.example.com"}},
+		{ID: "2", Name: "legacy"},
+	}
+	client.tests[bucket.Key] = []*runscope.Test{
+		{ID: "10", Name: "Health", Description: "Checks the health endpoint"},
+		{ID: "11", Name: "Obsolete"},
+	}
+	client.schedules["10"] = []*runscope.Schedule{{ID: "20", EnvironmentID: "1", Interval: "5m", Note: "every 5 minutes"}}
+
+	plan, err := NewReconciler(client).Reconcile(loadSpec(t, productionSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `~ environment Production/prod
+~ test Production/Health
+- test Production/Obsolete
+- environment Production/legacy`
+	if plan.String() != expected {
+		t.Errorf("Expected plan\n%s\nactual\n%s", expected, plan)
+	}
+
+	calls := strings.Join(client.calls, "\n")
+	expected = "update environment 1\nupdate test 10 with 1 steps\ndelete test 11\ndelete environment 2"
+	if calls != expected {
+		t.Errorf("Expected calls\n%s\nactual\n%s", expected, calls)
+	}
+
+	client.calls = nil
+	client.tests[bucket.Key][0].Steps = loadSpec(t, productionSpec)[0].Tests[0].Steps
+	client.tests[bucket.Key] = client.tests[bucket.Key][:1]
+	client.environments[bucket.Key] = []*runscope.Environment{
+		{ID: "1", Name: "prod", InitialVariables: map[string]string{"baseUrl": "https://example.com"}}}
+	if plan, err = NewReconciler(client).Plan(loadSpec(t, productionSpec)); err != nil || !plan.Empty() {
+		t.Errorf("Expected an empty plan once in sync, actual %s %v", plan, err)
+	}
+}
+
+func TestChangeJSONLeavesOutSecrets(t *testing.T) {
+	change := &Change{Action: Update, Kind: "environment", Path: "Production/prod",
+		Desired: &runscope.Environment{Name: "prod", InitialVariables: map[string]string{"token": "s3cret"}}}
+	data, err := json.Marshal(change)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(string(data), "s3cret") || !strings.Contains(string(data), `"path":"Production/prod"`) {
+		t.Errorf("Expected only action, kind and path to be encoded, actual %s", data)
+	}
+}
@@ -0,0 +1,189 @@
+package reconcile
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/ewilde/go-runscope"
+)
+
+// SecretProvider resolves references to secrets kept outside of specs, e.g. the path of a secret in Vault
+type SecretProvider interface {
+	Secret(reference string) (string, error)
+}
+
+// secretPlaceholder matches the references to secrets in specs, ${secret:PROVIDER:REFERENCE}
+var secretPlaceholder = regexp.MustCompile(`\$\{secret:([A-Za-z0-9_-]+):([^}]+)\}`)
+
+// EnvSecrets resolves references to the environment variables of the process, ${secret:env:API_TOKEN}
+type EnvSecrets struct{}
+
+// Secret returns the value of the environment variable named reference
+func (EnvSecrets) Secret(reference string) (string, error) {
+	value, ok := os.LookupEnv(reference)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s is not set", reference)
+	}
+
+	return value, nil
+}
+
+// DefaultSecretProviders returns the providers configured by the environment of the process: env always, vault
+// when VAULT_ADDR is set, see NewVaultSecrets, and aws when a region is set, see NewAWSSecrets
+func DefaultSecretProviders() map[string]SecretProvider {
+	providers := map[string]SecretProvider{"env": EnvSecrets{}}
+	if os.Getenv("VAULT_ADDR") != "" {
+		providers["vault"] = NewVaultSecrets()
+	}
+
+	if aws := NewAWSSecrets(); aws.Region != "" {
+		providers["aws"] = aws
+	}
+
+	return providers
+}
+
+// secretResolver replaces the secret placeholders of specs, resolving every reference once
+type secretResolver struct {
+	providers map[string]SecretProvider
+	resolved  map[string]string
+}
+
+// resolveSecrets returns copies of specs with the placeholders of the initial variables and headers of their
+// environments and the headers of their steps replaced by the secrets they reference
+func resolveSecrets(specs []*BucketSpec, providers map[string]SecretProvider) ([]*BucketSpec, error) {
+	resolver := &secretResolver{providers: providers, resolved: map[string]string{}}
+	var resolved []*BucketSpec
+	for _, spec := range specs {
+		bucket := *spec
+		bucket.Environments = nil
+		for _, environment := range spec.Environments {
+			resolvedEnvironment, err := resolver.environment(environment)
+			if err != nil {
+				return nil, fmt.Errorf("Error resolving secrets of %s/%s: %s", spec.Name, environment.Name, err)
+			}
+			bucket.Environments = append(bucket.Environments, resolvedEnvironment)
+		}
+
+		bucket.Tests = nil
+		for _, test := range spec.Tests {
+			resolvedTest, err := resolver.test(test)
+			if err != nil {
+				return nil, fmt.Errorf("Error resolving secrets of %s/%s: %s", spec.Name, test.Name, err)
+			}
+			bucket.Tests = append(bucket.Tests, resolvedTest)
+		}
+
+		resolved = append(resolved, &bucket)
+	}
+
+	return resolved, nil
+}
+
+func (resolver *secretResolver) test(spec *TestSpec) (*TestSpec, error) {
+	test := *spec
+	test.Steps = nil
+	for _, step := range spec.Steps {
+		resolved := step.Clone()
+		if err := resolver.steps([]*runscope.TestStep{resolved}); err != nil {
+			return nil, err
+		}
+		test.Steps = append(test.Steps, resolved)
+	}
+
+	test.Environments = nil
+	for _, environment := range spec.Environments {
+		resolved, err := resolver.environment(environment)
+		if err != nil {
+			return nil, err
+		}
+		test.Environments = append(test.Environments, resolved)
+	}
+
+	return &test, nil
+}
+
+func (resolver *secretResolver) steps(steps []*runscope.TestStep) error {
+	for _, step := range steps {
+		for _, header := range step.Headers {
+			if err := resolver.values(header.Values); err != nil {
+				return err
+			}
+		}
+
+		if err := resolver.steps(step.Steps); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (resolver *secretResolver) environment(environment *runscope.Environment) (*runscope.Environment, error) {
+	resolved := *environment
+	if environment.InitialVariables != nil {
+		resolved.InitialVariables = map[string]string{}
+		for name, value := range environment.InitialVariables {
+			var err error
+			if resolved.InitialVariables[name], err = resolver.value(value); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if environment.Headers != nil {
+		resolved.Headers = map[string][]string{}
+		for name, values := range environment.Headers {
+			resolved.Headers[name] = append([]string{}, values...)
+			if err := resolver.values(resolved.Headers[name]); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return &resolved, nil
+}
+
+func (resolver *secretResolver) values(values []string) error {
+	for i, value := range values {
+		var err error
+		if values[i], err = resolver.value(value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// value replaces the placeholders of a value, e.g. "Bearer ${secret:vault:secret/data/runscope#token}"
+func (resolver *secretResolver) value(value string) (string, error) {
+	var err error
+	resolved := secretPlaceholder.ReplaceAllStringFunc(value, func(placeholder string) string {
+		if err != nil {
+			return placeholder
+		}
+
+		if secret, ok := resolver.resolved[placeholder]; ok {
+			return secret
+		}
+
+		match := secretPlaceholder.FindStringSubmatch(placeholder)
+		provider, ok := resolver.providers[match[1]]
+		if !ok {
+			err = fmt.Errorf("no secret provider %s", match[1])
+			return placeholder
+		}
+
+		var secret string
+		if secret, err = provider.Secret(match[2]); err != nil {
+			err = fmt.Errorf("secret %s of %s: %s", match[2], match[1], err)
+			return placeholder
+		}
+
+		resolver.resolved[placeholder] = secret
+		return secret
+	})
+
+	return resolved, err
+}
@@ -0,0 +1,174 @@
+package reconcile
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-cleanhttp"
+)
+
+// AWSSecrets reads secrets from AWS Secrets Manager. References are the name or ARN of a secret, optionally with
+// the key of a value of a json secret, e.g. ${secret:aws:prod/runscope#apiToken}. See
+// https://docs.aws.amazon.com/secretsmanager/latest/apireference/API_GetSecretValue.html
+type AWSSecrets struct {
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	// Endpoint overrides the regional endpoint, e.g. for a vpc endpoint
+	Endpoint string
+	HTTP     *http.Client
+}
+
+// NewAWSSecrets creates a provider configured by the AWS_REGION, or AWS_DEFAULT_REGION, AWS_ACCESS_KEY_ID,
+// AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN environment variables. Only these static credentials are used, unlike
+// the aws sdks it doesn't read shared config profiles, web identity tokens or the instance metadata service. Where
+// those provide the credentials, e.g. an instance role in ci, export them to the environment first with
+// `aws configure export-credentials --format env`, or set the fields of AWSSecrets
+func NewAWSSecrets() *AWSSecrets {
+	region := os.Getenv("AWS_REGION")
+	if region == "" {
+		region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+
+	return &AWSSecrets{
+		Region:          region,
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		HTTP:            cleanhttp.DefaultClient(),
+	}
+}
+
+// Secret reads the secret string of the referenced secret, or the value of its key when the reference has one
+func (aws *AWSSecrets) Secret(reference string) (string, error) {
+	secretID, key := reference, ""
+	if separator := strings.LastIndex(reference, "#"); separator >= 0 {
+		secretID, key = reference[:separator], reference[separator+1:]
+	}
+
+	endpoint := aws.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://secretsmanager.%s.amazonaws.com/", aws.Region)
+	}
+
+	body, err := json.Marshal(map[string]string{"SecretId": secretID})
+	if err != nil {
+		return "", err
+	}
+
+	request, err := http.NewRequest("POST", endpoint, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	request.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	request.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	aws.sign(request, body, "secretsmanager", time.Now())
+
+	response, err := aws.HTTP.Do(request)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+
+	responseBody, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return "", err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("secrets manager responded %s: %s", response.Status, responseBody)
+	}
+
+	secret := struct {
+		SecretString string
+	}{}
+	if err = json.Unmarshal(responseBody, &secret); err != nil {
+		return "", err
+	}
+
+	if key == "" {
+		return secret.SecretString, nil
+	}
+
+	var values map[string]interface{}
+	if err = json.Unmarshal([]byte(secret.SecretString), &values); err != nil {
+		return "", fmt.Errorf("secret %s is not a json object: %s", secretID, err)
+	}
+
+	value, ok := values[key]
+	if !ok {
+		return "", fmt.Errorf("no key %s in secret %s", key, secretID)
+	}
+
+	return fmt.Sprint(value), nil
+}
+
+// sign adds the AWS signature version 4 of the request to its headers, every header of the request is signed. See
+// https://docs.aws.amazon.com/general/latest/gr/sigv4_signing.html
+func (aws *AWSSecrets) sign(request *http.Request, body []byte, service string, now time.Time) {
+	timestamp := now.UTC().Format("20060102T150405Z")
+	date := timestamp[:8]
+	request.Header.Set("X-Amz-Date", timestamp)
+	if aws.SessionToken != "" {
+		request.Header.Set("X-Amz-Security-Token", aws.SessionToken)
+	}
+
+	headers := map[string]string{"host": request.URL.Host}
+	for name, values := range request.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+
+	var names []string
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := request.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+
+	canonicalRequest := strings.Join([]string{request.Method, path,
+		strings.Replace(request.URL.Query().Encode(), "+", "%20", -1),
+		canonicalHeaders.String(), signedHeaders, hashHex(body)}, "\n")
+
+	scope := strings.Join([]string{date, aws.Region, service, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", timestamp, scope, hashHex([]byte(canonicalRequest))}, "\n")
+
+	key := []byte("AWS4" + aws.SecretAccessKey)
+	for _, part := range []string{date, aws.Region, service, "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+
+	request.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		aws.AccessKeyID, scope, signedHeaders, hex.EncodeToString(hmacSHA256(key, stringToSign))))
+}
+
+func hashHex(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,167 @@
+package reconcile
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ewilde/go-runscope"
+)
+
+// staticSecrets resolves references from a map, counting lookups
+type staticSecrets struct {
+	secrets map[string]string
+	lookups int
+}
+
+func (secrets *staticSecrets) Secret(reference string) (string, error) {
+	secrets.lookups++
+	secret, ok := secrets.secrets[reference]
+	if !ok {
+		return "", fmt.Errorf("no secret %s", reference)
+	}
+
+	return secret, nil
+}
+
+const secretSpec = `
+name: Production
+team: acme
+environments:
+  - name: prod
+    initial_variables:
+      apiToken: ${secret:static:api-token}
+    headers:
+      Authorization: ["Bearer ${secret:static:api-token}"]
+tests:
+  - name: Health
+    steps:
+      - step_type: request
+        method: GET
+        url: "{{baseUrl}}/health"
+        headers:
+          X-Api-Key: ["${secret:static:api-key}"]
+`
+
+func TestResolveSecrets(t *testing.T) {
+	specs := loadSpec(t, secretSpec)
+	static := &staticSecrets{secrets: map[string]string{"api-token": "abc123", "api-key": "def456"}}
+
+	resolved, err := resolveSecrets(specs, map[string]SecretProvider{"static": static})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	environment := resolved[0].Environments[0]
+	if environment.InitialVariables["apiToken"] != "abc123" || environment.Headers["Authorization"][0] != "Bearer abc123" {
+		t.Errorf("Expected resolved apiToken and Authorization, actual %v %v",
+			environment.InitialVariables, environment.Headers)
+	}
+
+	if value := resolved[0].Tests[0].Steps[0].Headers.Get("X-Api-Key"); value[0] != "def456" {
+		t.Errorf("Expected resolved X-Api-Key, actual %v", value)
+	}
+
+	if static.lookups != 2 {
+		t.Errorf("Expected each secret to be looked up once, actual %d lookups", static.lookups)
+	}
+
+	if specs[0].Environments[0].InitialVariables["apiToken"] != "${secret:static:api-token}" {
+		t.Errorf("Expected specs to keep their placeholders, actual %v", specs[0].Environments[0].InitialVariables)
+	}
+}
+
+func TestResolveSecretsUnknownProvider(t *testing.T) {
+	if _, err := resolveSecrets(loadSpec(t, secretSpec), nil); err == nil ||
+		!strings.Contains(err.Error(), "no secret provider static") {
+		t.Errorf("Expected an error for the unknown provider, actual %v", err)
+	}
+}
+
+func TestVaultSecrets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/secret/data/runscope" || r.Header.Get("X-Vault-Token") != "root" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		fmt.Fprint(w, `{"data": {"data": {"apiToken": "abc123"}, "metadata": {"version": 3}}}`)
+	}))
+	defer server.Close()
+
+	vault := &VaultSecrets{Address: server.URL, Token: "root", HTTP: server.Client()}
+	secret, err := vault.Secret("secret/data/runscope#apiToken")
+	if err != nil || secret != "abc123" {
+		t.Errorf("Expected secret abc123, actual %q, %v", secret, err)
+	}
+
+	if _, err = vault.Secret("secret/data/runscope#missing"); err == nil {
+		t.Error("Expected an error for a missing key")
+	}
+}
+
+func TestAWSSecrets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Amz-Target") != "secretsmanager.GetSecretValue" ||
+			!strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		fmt.Fprint(w, `{"Name": "prod/runscope", "SecretString": "{\"apiToken\": \"abc123\"}"}`)
+	}))
+	defer server.Close()
+
+	aws := &AWSSecrets{Region: "us-east-1", AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "secret",
+		Endpoint: server.URL, HTTP: server.Client()}
+	secret, err := aws.Secret("prod/runscope#apiToken")
+	if err != nil || secret != "abc123" {
+		t.Errorf("Expected secret abc123, actual %q, %v", secret, err)
+	}
+
+	if secret, err = aws.Secret("prod/runscope"); err != nil || secret != `{"apiToken": "abc123"}` {
+		t.Errorf("Expected the secret string, actual %q, %v", secret, err)
+	}
+}
+
+// TestAWSSignature signs the example request of the AWS signature version 4 documentation
+func TestAWSSignature(t *testing.T) {
+	request, _ := http.NewRequest("GET", "https://iam.amazonaws.com/?Action=ListUsers&Version=2010-05-08", nil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+
+	aws := &AWSSecrets{Region: "us-east-1", AccessKeyID: "AKIDEXAMPLE",
+		SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"}
+	aws.sign(request, nil, "iam", time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC))
+
+	expected := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/iam/aws4_request, " +
+		"SignedHeaders=content-type;host;x-amz-date, " +
+		"Signature=5d672d79c15b13162d9279b0855cfba6789a8edb4c82c400e06b5924a6f2b5d7"
+	if actual := request.Header.Get("Authorization"); actual != expected {
+		t.Errorf("Expected Authorization %s, actual %s", expected, actual)
+	}
+}
+
+func TestPlanResolvesSecrets(t *testing.T) {
+	client := newFakeClient()
+	reconciler := NewReconciler(client)
+	reconciler.Secrets = map[string]SecretProvider{
+		"static": &staticSecrets{secrets: map[string]string{"api-token": "abc123", "api-key": "def456"}}}
+
+	plan, err := reconciler.Plan(loadSpec(t, secretSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, change := range plan.Changes {
+		if change.Kind != KindEnvironment {
+			continue
+		}
+
+		if environment := change.Desired.(*runscope.Environment); environment.InitialVariables["apiToken"] != "abc123" {
+			t.Errorf("Expected apiToken of %s to be resolved, actual %v", change.Path, environment.InitialVariables)
+		}
+	}
+}
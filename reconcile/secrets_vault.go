@@ -0,0 +1,77 @@
+package reconcile
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/go-cleanhttp"
+)
+
+// VaultSecrets reads secrets from HashiCorp Vault. References are the api path of a secret and the key of the
+// value, e.g. ${secret:vault:secret/data/runscope#apiToken}, both kv version 1 and 2 secrets are supported. See
+// https://www.vaultproject.io/api-docs/secret/kv
+type VaultSecrets struct {
+	// Address of the vault server, e.g. https://vault.example.com:8200
+	Address string
+	Token   string
+	HTTP    *http.Client
+}
+
+// NewVaultSecrets creates a provider configured by the VAULT_ADDR and VAULT_TOKEN environment variables
+func NewVaultSecrets() *VaultSecrets {
+	return &VaultSecrets{
+		Address: os.Getenv("VAULT_ADDR"),
+		Token:   os.Getenv("VAULT_TOKEN"),
+		HTTP:    cleanhttp.DefaultClient(),
+	}
+}
+
+// Secret reads the value with the key of the reference from the secret at its path
+func (vault *VaultSecrets) Secret(reference string) (string, error) {
+	separator := strings.LastIndex(reference, "#")
+	if separator < 0 {
+		return "", fmt.Errorf("expected PATH#KEY, actual %q", reference)
+	}
+	path, key := reference[:separator], reference[separator+1:]
+
+	request, err := http.NewRequest("GET", strings.TrimSuffix(vault.Address, "/")+"/v1/"+strings.TrimPrefix(path, "/"), nil)
+	if err != nil {
+		return "", err
+	}
+	request.Header.Set("X-Vault-Token", vault.Token)
+
+	response, err := vault.HTTP.Do(request)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("vault responded %s", response.Status)
+	}
+
+	secret := struct {
+		Data map[string]interface{} `json:"data"`
+	}{}
+	if err = json.NewDecoder(response.Body).Decode(&secret); err != nil {
+		return "", err
+	}
+
+	data := secret.Data
+	// kv version 2 nests the values along with the metadata of the version
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		if _, ok := data["metadata"]; ok {
+			data = nested
+		}
+	}
+
+	value, ok := data[key]
+	if !ok {
+		return "", fmt.Errorf("no key %s at %s", key, path)
+	}
+
+	return fmt.Sprint(value), nil
+}
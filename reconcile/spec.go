@@ -0,0 +1,245 @@
+// Package reconcile keeps Runscope buckets, tests, environments and schedules in sync with declarative YAML
+// definitions, computing a plan of the changes against the remote state and applying it.
+package reconcile
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ewilde/go-runscope"
+	"gopkg.in/yaml.v2"
+)
+
+// BucketSpec is the declarative definition of a bucket and everything it contains. Buckets are identified by name
+// within their team, tests by name within their bucket and environments by name within their bucket or test
+//
+//	name: Production
+//	team: 870ed937-bc6e-4d8b-a9a5-d7f9f2412fa3
+//	environments:
+//	  - name: prod
+//	    initial_variables:
+//	      baseUrl: https://example.com
+//	tests:
+//	  - name: Health
+//	    steps:
+//	      - step_type: request
+//	        method: GET
+//	        url: "{{baseUrl}}/health"
+//	        assertions:
+//	          - {source: response_status, comparison: equal_number, value: 200}
+//	    schedules:
+//	      - {environment: prod, interval: 5m}
+//
+// Environments and steps use the field names of the Runscope api. Initial variables and headers reference
+// secrets kept out of the specs with ${secret:PROVIDER:REFERENCE}, e.g. ${secret:env:API_TOKEN}, see
+// Reconciler.Secrets
+type BucketSpec struct {
+	Name         string                  `json:"name"`
+	Team         string                  `json:"team"`
+	Environments []*runscope.Environment `json:"environments"`
+	Tests        []*TestSpec             `json:"tests"`
+	// Source is the file the spec was loaded from
+	Source string `json:"-"`
+}
+
+// TestSpec is the declarative definition of a test, its steps, test environments and schedules
+type TestSpec struct {
+	Name         string                  `json:"name"`
+	Description  string                  `json:"description"`
+	Steps        []*runscope.TestStep    `json:"steps"`
+	Environments []*runscope.Environment `json:"environments"`
+	Schedules    []*ScheduleSpec         `json:"schedules"`
+}
+
+// ScheduleSpec runs a test in a test or shared environment, by name, at an interval. A test has at most one
+// schedule per environment and interval
+type ScheduleSpec struct {
+	Environment string                    `json:"environment"`
+	Interval    runscope.ScheduleInterval `json:"interval"`
+	Note        string                    `json:"note"`
+}
+
+// LoadDir loads the bucket specs of every .yaml and .yml file in dir and its subdirectories, a file may hold
+// several specs separated by ---
+func LoadDir(dir string) ([]*BucketSpec, error) {
+	var files []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if ext := filepath.Ext(path); !info.IsDir() && (ext == ".yaml" || ext == ".yml") {
+			files = append(files, path)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(files)
+	var specs []*BucketSpec
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		fileSpecs, err := Load(bytes.NewReader(data), file)
+		if err != nil {
+			return nil, err
+		}
+
+		specs = append(specs, fileSpecs...)
+	}
+
+	return specs, Validate(specs)
+}
+
+// Load reads the bucket specs of a YAML document stream, source names it in errors
+func Load(reader io.Reader, source string) ([]*BucketSpec, error) {
+	var specs []*BucketSpec
+	decoder := yaml.NewDecoder(reader)
+	for {
+		var document interface{}
+		err := decoder.Decode(&document)
+		if err == io.EOF {
+			return specs, nil
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("Error reading spec %s: %s", source, err)
+		}
+
+		if document == nil {
+			continue
+		}
+
+		// the runscope types are decoded from json, which yaml is converted to
+		data, err := json.Marshal(jsonValue(document))
+		if err != nil {
+			return nil, fmt.Errorf("Error reading spec %s: %s", source, err)
+		}
+
+		spec := &BucketSpec{Source: source}
+		if err := json.Unmarshal(data, spec); err != nil {
+			return nil, fmt.Errorf("Error reading spec %s: %s", source, err)
+		}
+
+		specs = append(specs, spec)
+	}
+}
+
+// jsonValue converts the maps decoded from yaml, which may have keys of any type, to json objects
+func jsonValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[interface{}]interface{}:
+		object := make(map[string]interface{}, len(value))
+		for key, nested := range value {
+			object[fmt.Sprint(key)] = jsonValue(nested)
+		}
+		return object
+	case []interface{}:
+		array := make([]interface{}, len(value))
+		for i, nested := range value {
+			array[i] = jsonValue(nested)
+		}
+		return array
+	default:
+		return value
+	}
+}
+
+// Validate checks that specs are complete and unambiguous: buckets, tests and environments are named and unique,
+// schedules reference an environment of their test or bucket and have a valid interval
+func Validate(specs []*BucketSpec) error {
+	buckets := map[string]string{}
+	for _, bucket := range specs {
+		if bucket.Name == "" || bucket.Team == "" {
+			return fmt.Errorf("Error validating spec %s: bucket requires a name and a team", bucket.Source)
+		}
+
+		key := bucket.Team + "/" + bucket.Name
+		if source, ok := buckets[key]; ok {
+			return fmt.Errorf("Error validating spec %s: bucket %s is also defined in %s", bucket.Source, bucket.Name, source)
+		}
+		buckets[key] = bucket.Source
+
+		shared, err := environmentNames(bucket.Environments)
+		if err != nil {
+			return fmt.Errorf("Error validating spec %s: bucket %s: %s", bucket.Source, bucket.Name, err)
+		}
+
+		tests := map[string]bool{}
+		for _, test := range bucket.Tests {
+			if err := validateTest(test, tests, shared); err != nil {
+				return fmt.Errorf("Error validating spec %s: bucket %s: %s", bucket.Source, bucket.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateTest(test *TestSpec, tests map[string]bool, shared map[string]bool) error {
+	if test.Name == "" {
+		return fmt.Errorf("test requires a name")
+	}
+
+	if tests[test.Name] {
+		return fmt.Errorf("test %s is defined twice", test.Name)
+	}
+	tests[test.Name] = true
+
+	environments, err := environmentNames(test.Environments)
+	if err != nil {
+		return fmt.Errorf("test %s: %s", test.Name, err)
+	}
+
+	schedules := map[string]bool{}
+	for _, schedule := range test.Schedules {
+		if err := schedule.Interval.Validate(); err != nil {
+			return fmt.Errorf("test %s: %s", test.Name, err)
+		}
+
+		if !environments[schedule.Environment] && !shared[schedule.Environment] {
+			return fmt.Errorf("test %s: schedule references unknown environment %q", test.Name, schedule.Environment)
+		}
+
+		key := schedule.key()
+		if schedules[key] {
+			return fmt.Errorf("test %s: schedule %s is defined twice", test.Name, key)
+		}
+		schedules[key] = true
+	}
+
+	return nil
+}
+
+func environmentNames(environments []*runscope.Environment) (map[string]bool, error) {
+	names := map[string]bool{}
+	for _, environment := range environments {
+		if environment.Name == "" {
+			return nil, fmt.Errorf("environment requires a name")
+		}
+
+		if names[environment.Name] {
+			return nil, fmt.Errorf("environment %s is defined twice", environment.Name)
+		}
+		names[environment.Name] = true
+	}
+
+	return names, nil
+}
+
+func (schedule *ScheduleSpec) key() string {
+	return strings.Join([]string{schedule.Environment, string(schedule.Interval)}, "@")
+}
@@ -0,0 +1,192 @@
+package runscope
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"strings"
+)
+
+// RedactedValue replaces the secrets removed by Redacted
+const RedactedValue = "REDACTED"
+
+// Redacted returns a copy of the test with the credentials of its steps replaced by RedactedValue: passwords and
+// credential query parameters of urls, credential headers and form parameters, auth values with credential names,
+// and values with credential names in json and url encoded bodies, which are encoded again when redacted. Values
+// referencing a variable, e.g. {{token}}, are kept as they hold no secret. Scripts, bodies in other formats and step
+// fields not modeled by TestStep, see Extra, are not redacted, review them before sharing the test
+func (test *Test) Redacted() *Test {
+	redacted := test.Clone()
+	redactSteps(redacted.Steps)
+	for i, environment := range redacted.Environments {
+		redacted.Environments[i] = environment.Redacted()
+	}
+
+	return redacted
+}
+
+// Redacted returns a copy of the environment with initial variables and headers with credential names and the
+// client certificate replaced by RedactedValue
+func (environment *Environment) Redacted() *Environment {
+	redacted := environment.clone()
+	for name, value := range redacted.InitialVariables {
+		if isCredentialName(name) && value != "" && !isTemplated(value) {
+			redacted.InitialVariables[name] = RedactedValue
+		}
+	}
+
+	for name, values := range redacted.Headers {
+		if credentialHeaders[strings.ToLower(name)] || isCredentialName(name) {
+			redactValues(values)
+		}
+	}
+
+	if redacted.ClientCertificate != "" {
+		redacted.ClientCertificate = RedactedValue
+	}
+
+	return redacted
+}
+
+func redactSteps(steps []*TestStep) {
+	for _, step := range steps {
+		step.URL = redactURL(step.URL)
+		for _, header := range step.Headers {
+			if credentialHeaders[strings.ToLower(header.Name)] || isCredentialName(header.Name) {
+				redactValues(header.Values)
+			}
+		}
+
+		for _, field := range step.Form {
+			if isCredentialName(field.Name) {
+				redactValues(field.Values)
+			}
+		}
+
+		for name, value := range step.Auth {
+			if isCredentialName(name) && value != "" && !isTemplated(value) {
+				step.Auth[name] = RedactedValue
+			}
+		}
+
+		step.Body = redactBody(step.Body)
+		redactSteps(step.Steps)
+	}
+}
+
+func redactValues(values []string) {
+	for i, value := range values {
+		if value != "" && !isTemplated(value) {
+			values[i] = RedactedValue
+		}
+	}
+}
+
+// redactBody replaces the values with credential names of a json or url encoded body, bodies in other formats and
+// bodies without credentials are kept as they are
+func redactBody(body string) string {
+	var value interface{}
+	decoder := json.NewDecoder(strings.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err == nil {
+		if !redactJSON(value) {
+			return body
+		}
+
+		var buffer bytes.Buffer
+		encoder := json.NewEncoder(&buffer)
+		encoder.SetEscapeHTML(false)
+		if err := encoder.Encode(value); err != nil {
+			return body
+		}
+
+		return strings.TrimSuffix(buffer.String(), "\n")
+	}
+
+	fields, err := url.ParseQuery(body)
+	if err != nil || !strings.Contains(body, "=") {
+		return body
+	}
+
+	redacted := false
+	for name, values := range fields {
+		if isCredentialName(name) {
+			for i, value := range values {
+				if value != "" && !isTemplated(value) {
+					values[i] = RedactedValue
+					redacted = true
+				}
+			}
+		}
+	}
+
+	if !redacted {
+		return body
+	}
+
+	return fields.Encode()
+}
+
+// redactJSON replaces the string and number values with credential names in a decoded json document, it reports
+// whether any value was replaced
+func redactJSON(value interface{}) bool {
+	redacted := false
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for name, field := range value {
+			switch field := field.(type) {
+			case string:
+				if isCredentialName(name) && field != "" && !isTemplated(field) {
+					value[name] = RedactedValue
+					redacted = true
+				}
+			case json.Number:
+				if isCredentialName(name) {
+					value[name] = RedactedValue
+					redacted = true
+				}
+			default:
+				redacted = redactJSON(field) || redacted
+			}
+		}
+	case []interface{}:
+		for _, item := range value {
+			redacted = redactJSON(item) || redacted
+		}
+	}
+
+	return redacted
+}
+
+// redactURL replaces the password and the credential query parameters of a url, urls that don't parse are kept
+func redactURL(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+
+	redacted := false
+	if password, ok := parsed.User.Password(); ok && !isTemplated(password) {
+		parsed.User = url.UserPassword(parsed.User.Username(), RedactedValue)
+		redacted = true
+	}
+
+	query := parsed.Query()
+	for name, values := range query {
+		if isCredentialName(name) {
+			for i, value := range values {
+				if value != "" && !isTemplated(value) {
+					values[i] = RedactedValue
+					redacted = true
+				}
+			}
+		}
+	}
+
+	if !redacted {
+		return rawURL
+	}
+
+	parsed.RawQuery = query.Encode()
+	return parsed.String()
+}
@@ -0,0 +1,378 @@
+package runscope
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+const (
+	// TestResultPass is the result of a run where every assertion passed
+	TestResultPass = "pass"
+	// TestResultFail is the result of a run where at least one assertion failed
+	TestResultFail = "fail"
+	// TestResultWorking is the result of a run that is still in progress
+	TestResultWorking = "working"
+	// TestResultQueued is the result of a run that has not started yet
+	TestResultQueued = "queued"
+	// TestResultCanceled is the result of a run that was canceled before it finished
+	TestResultCanceled = "canceled"
+)
+
+// TestResult represents the outcome of a single test run. See https://www.runscope.com/docs/api/results
+type TestResult struct {
+	TestRunID         string     `json:"test_run_id,omitempty"`
+	TestID            string     `json:"test_id,omitempty"`
+	BucketKey         BucketKey  `json:"bucket_key,omitempty"`
+	Result            string     `json:"result,omitempty"`
+	Region            string     `json:"region,omitempty"`
+	EnvironmentID     string     `json:"environment_id,omitempty"`
+	EnvironmentName   string     `json:"environment_name,omitempty"`
+	TestRunURL        string     `json:"test_run_url,omitempty"`
+	StartedAt         *time.Time `json:"started_at,omitempty"`
+	FinishedAt        *time.Time `json:"finished_at,omitempty"`
+	AssertionsDefined int        `json:"assertions_defined,omitempty"`
+	AssertionsPassed  int        `json:"assertions_passed,omitempty"`
+	AssertionsFailed  int        `json:"assertions_failed,omitempty"`
+	ScriptsDefined    int        `json:"scripts_defined,omitempty"`
+	ScriptsPassed     int        `json:"scripts_passed,omitempty"`
+	ScriptsFailed     int        `json:"scripts_failed,omitempty"`
+	VariablesDefined  int        `json:"variables_defined,omitempty"`
+	VariablesPassed   int        `json:"variables_passed,omitempty"`
+	VariablesFailed   int        `json:"variables_failed,omitempty"`
+	RequestsExecuted  int        `json:"requests_executed,omitempty"`
+	Agent             string     `json:"agent,omitempty"`
+	// InitialVariables are the variables the run started with, from the environment and the trigger
+	InitialVariables map[string]string `json:"initial_variables,omitempty"`
+	// Variables are the variables at the end of the run, the initial variables along with those extracted by requests
+	Variables map[string]string `json:"variables,omitempty"`
+	// Requests are only included by ReadResult, not by ListResults
+	Requests []*RequestResult `json:"requests,omitempty"`
+}
+
+// RequestResult is the outcome of a single request step within a run
+type RequestResult struct {
+	UUID              string `json:"uuid,omitempty"`
+	StepType          string `json:"step_type,omitempty"`
+	Method            string `json:"method,omitempty"`
+	URL               string `json:"url,omitempty"`
+	Result            string `json:"result,omitempty"`
+	ResponseStatus    int    `json:"response_status_code,omitempty"`
+	ResponseSizeBytes int    `json:"response_size_bytes,omitempty"`
+	ResponseTimeMs    int    `json:"response_time_ms,omitempty"`
+	Note              string `json:"note,omitempty"`
+	// Timings break the response time down into its phases, when the api provides them
+	Timings *RequestTimings `json:"timings,omitempty"`
+	// CaptureURL is the api url of the request and response captured for the request, see ReadRunRequest
+	CaptureURL        string             `json:"capture_url,omitempty"`
+	Assertions        []*AssertionResult `json:"assertions,omitempty"`
+	Scripts           []*ScriptResult    `json:"scripts,omitempty"`
+	Variables         []*VariableResult  `json:"variables,omitempty"`
+	AssertionsDefined int                `json:"assertions_defined,omitempty"`
+	AssertionsPassed  int                `json:"assertions_passed,omitempty"`
+	AssertionsFailed  int                `json:"assertions_failed,omitempty"`
+	ScriptsDefined    int                `json:"scripts_defined,omitempty"`
+	ScriptsPassed     int                `json:"scripts_passed,omitempty"`
+	ScriptsFailed     int                `json:"scripts_failed,omitempty"`
+	VariablesDefined  int                `json:"variables_defined,omitempty"`
+	VariablesPassed   int                `json:"variables_passed,omitempty"`
+	VariablesFailed   int                `json:"variables_failed,omitempty"`
+}
+
+// RequestTimings are the phases of a request in milliseconds, phases that did not happen, e.g. the tls handshake of
+// a reused connection, are zero
+type RequestTimings struct {
+	DNSLookupMs       float64 `json:"dns_lookup_ms,omitempty"`
+	DialMs            float64 `json:"dial_ms,omitempty"`
+	SSLHandshakeMs    float64 `json:"ssl_handshake_ms,omitempty"`
+	SendHeadersMs     float64 `json:"send_headers_ms,omitempty"`
+	SendBodyMs        float64 `json:"send_body_ms,omitempty"`
+	WaitForResponseMs float64 `json:"wait_for_response_ms,omitempty"`
+	ReceiveResponseMs float64 `json:"receive_response_ms,omitempty"`
+}
+
+// AssertionResult is the outcome of an assertion of a request, comparing ActualValue with TargetValue
+type AssertionResult struct {
+	Result      string      `json:"result,omitempty"`
+	Source      string      `json:"source,omitempty"`
+	Property    string      `json:"property,omitempty"`
+	Comparison  string      `json:"comparison,omitempty"`
+	TargetValue interface{} `json:"target_value,omitempty"`
+	ActualValue interface{} `json:"actual_value,omitempty"`
+	Error       string      `json:"error,omitempty"`
+}
+
+// AssertionFailure is a failed assertion along with the request step it belongs to, Step is the position of the
+// request in the run starting at 1
+type AssertionFailure struct {
+	Step       int
+	Method     string
+	URL        string
+	Source     string
+	Property   string
+	Comparison string
+	Expected   interface{}
+	Actual     interface{}
+	Error      string
+}
+
+// ScriptResult is the outcome of a script of a request along with its log output
+type ScriptResult struct {
+	Result string `json:"result,omitempty"`
+	Output string `json:"output,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// VariableResult is a variable extracted from the response of a request
+type VariableResult struct {
+	Result   string `json:"result,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Source   string `json:"source,omitempty"`
+	Property string `json:"property,omitempty"`
+	Value    string `json:"value,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// ListResultsInput filters the results listed by ListResults, zero values are not sent
+type ListResultsInput struct {
+	// Count is the number of results to list, the api defaults to 10 and allows at most 50
+	Count int
+	// Since only lists results of runs started after this time
+	Since time.Time
+	// Before only lists results of runs started before this time
+	Before time.Time
+}
+
+// ReadResult reads the details of a single run of a test, including the outcome of each request. See
+// https://www.runscope.com/docs/api/results#detail
+func (client *Client) ReadResult(test *Test, testRunID string) (*TestResult, error) {
+	endpoint, error := bucketEndpoint(test.Bucket.Key, "/tests/%s/results/%s", test.ID, testRunID)
+	if error != nil {
+		return nil, error
+	}
+
+	if cached, ok := client.ResultCache.get(endpoint); ok {
+		return cached[0], nil
+	}
+
+	resource, error := client.readResource("test result", testRunID, endpoint)
+	if error != nil {
+		return nil, error
+	}
+
+	result, error := getTestResultFromResponse(resource.Data)
+	if error != nil {
+		return nil, error
+	}
+
+	client.setCaptureURLs(test, result)
+	if result.Done() {
+		client.ResultCache.put(endpoint, []*TestResult{result})
+	}
+
+	return result, nil
+}
+
+// ListResults lists the most recent runs of a test, newest first. See https://www.runscope.com/docs/api/results#list
+func (client *Client) ListResults(test *Test, input *ListResultsInput) ([]*TestResult, error) {
+	endpoint, error := bucketEndpoint(test.Bucket.Key, "/tests/%s/results", test.ID)
+	if error != nil {
+		return nil, error
+	}
+
+	if input != nil {
+		query := url.Values{}
+		if input.Count > 0 {
+			query.Add("count", fmt.Sprintf("%d", input.Count))
+		}
+
+		if !input.Since.IsZero() {
+			query.Add("since", fmt.Sprintf("%d", input.Since.Unix()))
+		}
+
+		if !input.Before.IsZero() {
+			query.Add("before", fmt.Sprintf("%d", input.Before.Unix()))
+		}
+
+		if len(query) > 0 {
+			endpoint = endpoint + "?" + query.Encode()
+		}
+	}
+
+	if cached, ok := client.ResultCache.get(endpoint); ok {
+		return cached, nil
+	}
+
+	resource, error := client.readResource("[]test result", test.ID, endpoint)
+	if error != nil {
+		return nil, error
+	}
+
+	results, error := getTestResultsFromResponse(resource.Data)
+	if error != nil {
+		return nil, error
+	}
+
+	if input != nil {
+		client.ResultCache.putList(endpoint, input.Before, results)
+	}
+	return results, nil
+}
+
+// FailedAssertions returns the assertions that failed in the request
+func (request *RequestResult) FailedAssertions() []*AssertionResult {
+	var failed []*AssertionResult
+	for _, assertion := range request.Assertions {
+		if assertion.Result == TestResultFail {
+			failed = append(failed, assertion)
+		}
+	}
+
+	return failed
+}
+
+// DNSLookup returns the time spent resolving the host name
+func (timings *RequestTimings) DNSLookup() time.Duration {
+	return milliseconds(timings.DNSLookupMs)
+}
+
+// Connect returns the time spent opening the tcp connection
+func (timings *RequestTimings) Connect() time.Duration {
+	return milliseconds(timings.DialMs)
+}
+
+// TLSHandshake returns the time spent on the tls handshake
+func (timings *RequestTimings) TLSHandshake() time.Duration {
+	return milliseconds(timings.SSLHandshakeMs)
+}
+
+// TimeToFirstByte returns the time from sending the request until the first byte of the response was received
+func (timings *RequestTimings) TimeToFirstByte() time.Duration {
+	return milliseconds(timings.WaitForResponseMs)
+}
+
+// Total returns the sum of all phases
+func (timings *RequestTimings) Total() time.Duration {
+	return milliseconds(timings.DNSLookupMs + timings.DialMs + timings.SSLHandshakeMs + timings.SendHeadersMs +
+		timings.SendBodyMs + timings.WaitForResponseMs + timings.ReceiveResponseMs)
+}
+
+func milliseconds(value float64) time.Duration {
+	return time.Duration(value * float64(time.Millisecond))
+}
+
+// FinalVariables returns the variables at the end of the run, to see what each {{variable}} resolved to. When the api
+// does not return them they are rebuilt from the initial variables and the variables extracted by each request
+func (result *TestResult) FinalVariables() map[string]string {
+	variables := make(map[string]string, len(result.InitialVariables)+len(result.Variables))
+	if len(result.Variables) > 0 {
+		for name, value := range result.Variables {
+			variables[name] = value
+		}
+
+		return variables
+	}
+
+	for name, value := range result.InitialVariables {
+		variables[name] = value
+	}
+
+	for _, request := range result.Requests {
+		for _, variable := range request.Variables {
+			if variable.Result != TestResultFail {
+				variables[variable.Name] = variable.Value
+			}
+		}
+	}
+
+	return variables
+}
+
+// AssertionFailures returns every failed assertion of the run in step order. The requests are only included by
+// ReadResult, results listed by ListResults have no failures
+func (result *TestResult) AssertionFailures() []*AssertionFailure {
+	var failures []*AssertionFailure
+	for i, request := range result.Requests {
+		for _, assertion := range request.FailedAssertions() {
+			failures = append(failures, &AssertionFailure{
+				Step:       i + 1,
+				Method:     request.Method,
+				URL:        request.URL,
+				Source:     assertion.Source,
+				Property:   assertion.Property,
+				Comparison: assertion.Comparison,
+				Expected:   assertion.TargetValue,
+				Actual:     assertion.ActualValue,
+				Error:      assertion.Error,
+			})
+		}
+	}
+
+	return failures
+}
+
+func (assertion *AssertionResult) String() string {
+	return assertionMessage(assertion.Source, assertion.Property, assertion.Comparison, assertion.TargetValue,
+		assertion.ActualValue, assertion.Error)
+}
+
+func (failure *AssertionFailure) String() string {
+	return fmt.Sprintf("%s on step %d", assertionMessage(failure.Source, failure.Property, failure.Comparison,
+		failure.Expected, failure.Actual, failure.Error), failure.Step)
+}
+
+// assertionMessage describes an assertion outcome, e.g. "response_status equal_number: expected 200, got 503"
+func assertionMessage(source, property, comparison string, expected, actual interface{}, err string) string {
+	subject := source
+	if property != "" {
+		subject = fmt.Sprintf("%s %s", source, property)
+	}
+
+	message := fmt.Sprintf("%s %s: expected %v, got %v", subject, comparison, resultValue(expected),
+		resultValue(actual))
+	if err != "" {
+		message = fmt.Sprintf("%s (%s)", message, err)
+	}
+
+	return message
+}
+
+func resultValue(value interface{}) interface{} {
+	if value == nil {
+		return "(none)"
+	}
+
+	return value
+}
+
+// Done reports whether the run has finished and its result is final
+func (result *TestResult) Done() bool {
+	switch result.Result {
+	case TestResultPass, TestResultFail, TestResultCanceled:
+		return true
+	}
+
+	return false
+}
+
+func (result *TestResult) String() string {
+	value, err := json.Marshal(result)
+	if err != nil {
+		return ""
+	}
+
+	return string(value)
+}
+
+func getTestResultFromResponse(response interface{}) (*TestResult, error) {
+	result := new(TestResult)
+	err := decode(result, response)
+	return result, err
+}
+
+func getTestResultsFromResponse(response interface{}) ([]*TestResult, error) {
+	var results []*TestResult
+	err := decode(&results, response)
+	return results, err
+}
@@ -0,0 +1,122 @@
+package runscope
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// ResultAggregate summarises a set of runs for SLO reporting. Only passed and failed runs are counted, runs still in
+// progress and canceled runs are ignored
+type ResultAggregate struct {
+	Runs   int
+	Passed int
+	Failed int
+	// PassRate is the fraction of runs that passed, between 0 and 1
+	PassRate float64
+	// MTBF is the mean time between failures, the time from the start of the first run to the end of the last run
+	// divided by the number of failures. It is zero when no run failed
+	MTBF time.Duration
+	// LongestFailureStreak is the highest number of consecutive failed runs
+	LongestFailureStreak int
+	// CurrentFailureStreak is the number of failed runs since the latest passed run
+	CurrentFailureStreak int
+	// P50, P90, P95 and P99 are percentiles of the duration of the runs
+	P50 time.Duration
+	P90 time.Duration
+	P95 time.Duration
+	P99 time.Duration
+
+	durations []time.Duration
+}
+
+// Aggregate computes the pass rate, mean time between failures, failure streaks and latency percentiles of runs,
+// which may be given in any order
+func Aggregate(results []*TestResult) *ResultAggregate {
+	var runs []*TestResult
+	for _, result := range results {
+		if result.Result == TestResultPass || result.Result == TestResultFail {
+			runs = append(runs, result)
+		}
+	}
+
+	sort.SliceStable(runs, func(i, j int) bool {
+		return resultStarted(runs[i]).Before(resultStarted(runs[j]))
+	})
+
+	aggregate := &ResultAggregate{Runs: len(runs)}
+	var first, last time.Time
+	for _, run := range runs {
+		if run.Result == TestResultPass {
+			aggregate.Passed++
+			aggregate.CurrentFailureStreak = 0
+		} else {
+			aggregate.Failed++
+			aggregate.CurrentFailureStreak++
+			if aggregate.CurrentFailureStreak > aggregate.LongestFailureStreak {
+				aggregate.LongestFailureStreak = aggregate.CurrentFailureStreak
+			}
+		}
+
+		if run.StartedAt == nil {
+			continue
+		}
+
+		if first.IsZero() || run.StartedAt.Before(first) {
+			first = *run.StartedAt
+		}
+
+		end := *run.StartedAt
+		if run.FinishedAt != nil {
+			end = *run.FinishedAt
+			aggregate.durations = append(aggregate.durations, run.FinishedAt.Sub(*run.StartedAt))
+		}
+
+		if end.After(last) {
+			last = end
+		}
+	}
+
+	if aggregate.Runs > 0 {
+		aggregate.PassRate = float64(aggregate.Passed) / float64(aggregate.Runs)
+	}
+
+	if aggregate.Failed > 0 {
+		aggregate.MTBF = last.Sub(first) / time.Duration(aggregate.Failed)
+	}
+
+	sort.Slice(aggregate.durations, func(i, j int) bool { return aggregate.durations[i] < aggregate.durations[j] })
+	aggregate.P50 = aggregate.Percentile(50)
+	aggregate.P90 = aggregate.Percentile(90)
+	aggregate.P95 = aggregate.Percentile(95)
+	aggregate.P99 = aggregate.Percentile(99)
+
+	return aggregate
+}
+
+// Percentile returns the duration below which p percent of the runs finished, using the nearest rank method. It is
+// zero when no run has both a start and finish time
+func (aggregate *ResultAggregate) Percentile(p float64) time.Duration {
+	if len(aggregate.durations) == 0 {
+		return 0
+	}
+
+	rank := int(math.Ceil(p / 100 * float64(len(aggregate.durations))))
+	if rank < 1 {
+		rank = 1
+	}
+
+	if rank > len(aggregate.durations) {
+		rank = len(aggregate.durations)
+	}
+
+	return aggregate.durations[rank-1]
+}
+
+func resultStarted(result *TestResult) time.Time {
+	if result.StartedAt == nil {
+		return time.Time{}
+	}
+
+	return *result.StartedAt
+}
@@ -0,0 +1,56 @@
+package runscope
+
+import (
+	"testing"
+	"time"
+)
+
+func aggregateRun(result string, start time.Time, offset time.Duration, duration time.Duration) *TestResult {
+	started := start.Add(offset)
+	finished := started.Add(duration)
+	return &TestResult{Result: result, StartedAt: &started, FinishedAt: &finished}
+}
+
+func TestAggregate(t *testing.T) {
+	start := time.Date(2021, 5, 6, 10, 0, 0, 0, time.UTC)
+	results := []*TestResult{
+		aggregateRun(TestResultFail, start, 5*time.Hour, 500*time.Millisecond),
+		aggregateRun(TestResultPass, start, 0, 100*time.Millisecond),
+		aggregateRun(TestResultFail, start, 1*time.Hour, 200*time.Millisecond),
+		aggregateRun(TestResultFail, start, 2*time.Hour, 300*time.Millisecond),
+		aggregateRun(TestResultPass, start, 3*time.Hour, 400*time.Millisecond),
+		aggregateRun(TestResultFail, start, 4*time.Hour, 1000*time.Millisecond),
+		{Result: TestResultWorking},
+	}
+
+	aggregate := Aggregate(results)
+	if aggregate.Runs != 6 || aggregate.Passed != 2 || aggregate.Failed != 4 {
+		t.Errorf("Expected 6 runs, 2 passed and 4 failed, actual %d %d %d",
+			aggregate.Runs, aggregate.Passed, aggregate.Failed)
+	}
+
+	if aggregate.PassRate != 2.0/6.0 {
+		t.Errorf("Expected pass rate %f, actual %f", 2.0/6.0, aggregate.PassRate)
+	}
+
+	expectedMTBF := (5*time.Hour + 500*time.Millisecond) / 4
+	if aggregate.MTBF != expectedMTBF {
+		t.Errorf("Expected MTBF %s, actual %s", expectedMTBF, aggregate.MTBF)
+	}
+
+	if aggregate.LongestFailureStreak != 2 || aggregate.CurrentFailureStreak != 2 {
+		t.Errorf("Expected longest and current failure streak of 2, actual %d %d",
+			aggregate.LongestFailureStreak, aggregate.CurrentFailureStreak)
+	}
+
+	if aggregate.P50 != 300*time.Millisecond || aggregate.P99 != 1000*time.Millisecond {
+		t.Errorf("Expected p50 300ms and p99 1s, actual %s %s", aggregate.P50, aggregate.P99)
+	}
+}
+
+func TestAggregateEmpty(t *testing.T) {
+	aggregate := Aggregate(nil)
+	if aggregate.Runs != 0 || aggregate.PassRate != 0 || aggregate.MTBF != 0 || aggregate.P95 != 0 {
+		t.Errorf("Expected empty aggregate, actual %#v", aggregate)
+	}
+}
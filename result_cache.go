@@ -0,0 +1,86 @@
+package runscope
+
+import (
+	"sync"
+	"time"
+)
+
+// ResultCache memoizes the results read by ReadResult and ListResults for ttl, set it on Client.ResultCache so
+// dashboards refreshing often don't re-read the same runs. Only results that no longer change are cached: finished
+// runs read by ReadResult, and lists of ListResults with a Before in the past whose runs are all finished
+type ResultCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*resultCacheEntry
+}
+
+type resultCacheEntry struct {
+	expires time.Time
+	results []*TestResult
+}
+
+// NewResultCache creates a cache keeping results for ttl
+func NewResultCache(ttl time.Duration) *ResultCache {
+	return &ResultCache{ttl: ttl, now: time.Now, entries: map[string]*resultCacheEntry{}}
+}
+
+// Purge removes every cached result
+func (cache *ResultCache) Purge() {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	cache.entries = map[string]*resultCacheEntry{}
+}
+
+func (cache *ResultCache) get(key string) ([]*TestResult, bool) {
+	if cache == nil {
+		return nil, false
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	entry, ok := cache.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	if !cache.now().Before(entry.expires) {
+		delete(cache.entries, key)
+		return nil, false
+	}
+
+	return entry.results, true
+}
+
+// putList caches a list of results read with before unless runs may still be added to it or change
+func (cache *ResultCache) putList(key string, before time.Time, results []*TestResult) {
+	if cache == nil || before.IsZero() || before.After(cache.now()) {
+		return
+	}
+
+	for _, result := range results {
+		if !result.Done() {
+			return
+		}
+	}
+
+	cache.put(key, results)
+}
+
+func (cache *ResultCache) put(key string, results []*TestResult) {
+	if cache == nil {
+		return
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	now := cache.now()
+	for existing, entry := range cache.entries {
+		if !now.Before(entry.expires) {
+			delete(cache.entries, existing)
+		}
+	}
+
+	cache.entries[key] = &resultCacheEntry{expires: now.Add(cache.ttl), results: results}
+}
@@ -0,0 +1,58 @@
+package runscope
+
+import (
+	"testing"
+	"time"
+)
+
+func TestResultCache(t *testing.T) {
+	now := time.Date(2021, 5, 6, 10, 0, 0, 0, time.UTC)
+	cache := NewResultCache(time.Minute)
+	cache.now = func() time.Time { return now }
+
+	result := &TestResult{TestRunID: "cd5b1b4a-3c4e-4a48-b3c7-a7c2b7cb3d6a", Result: TestResultPass}
+	cache.put("/buckets/z3n32gktzx94/tests/1/results/cd5b1b4a", []*TestResult{result})
+
+	now = now.Add(59 * time.Second)
+	if cached, ok := cache.get("/buckets/z3n32gktzx94/tests/1/results/cd5b1b4a"); !ok || cached[0] != result {
+		t.Errorf("Expected cached result within ttl, actual %v %t", cached, ok)
+	}
+
+	now = now.Add(time.Second)
+	if _, ok := cache.get("/buckets/z3n32gktzx94/tests/1/results/cd5b1b4a"); ok {
+		t.Error("Expected result to expire after ttl")
+	}
+
+	cache.put("/buckets/z3n32gktzx94/tests/1/results", []*TestResult{result})
+	cache.Purge()
+	if _, ok := cache.get("/buckets/z3n32gktzx94/tests/1/results"); ok {
+		t.Error("Expected purge to remove results")
+	}
+
+	working := &TestResult{TestRunID: "5e9a3b8e-0d8c-4a5e-9f0e-2c1f8f7e6d5c", Result: TestResultWorking}
+	lists := []struct {
+		before  time.Time
+		results []*TestResult
+		cached  bool
+	}{
+		{time.Time{}, []*TestResult{result}, false},
+		{now.Add(time.Hour), []*TestResult{result}, false},
+		{now.Add(-time.Hour), []*TestResult{result, working}, false},
+		{now.Add(-time.Hour), []*TestResult{result}, true},
+	}
+
+	for _, list := range lists {
+		cache.Purge()
+		cache.putList("/buckets/z3n32gktzx94/tests/1/results", list.before, list.results)
+		if _, ok := cache.get("/buckets/z3n32gktzx94/tests/1/results"); ok != list.cached {
+			t.Errorf("Expected list before %s of %d results cached %t, actual %t", list.before, len(list.results),
+				list.cached, ok)
+		}
+	}
+
+	var disabled *ResultCache
+	disabled.put("/buckets/z3n32gktzx94/tests/1/results", []*TestResult{result})
+	if _, ok := disabled.get("/buckets/z3n32gktzx94/tests/1/results"); ok {
+		t.Error("Expected nil cache to cache nothing")
+	}
+}
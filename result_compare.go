@@ -0,0 +1,131 @@
+package runscope
+
+import (
+	"fmt"
+	"time"
+)
+
+// RunComparison is the difference between two runs of the same test, from Base to Target
+type RunComparison struct {
+	Base   *TestResult
+	Target *TestResult
+	// Steps pairs the requests of both runs, a request missing from one of the runs has a nil Base or Target
+	Steps []*StepComparison
+	// NewFailures are the assertions failing in Target that did not fail in Base
+	NewFailures []*AssertionFailure
+	// ResolvedFailures are the assertions failing in Base that no longer fail in Target
+	ResolvedFailures []*AssertionFailure
+}
+
+// StepComparison is a request of a test in both runs, Step is its position starting at 1
+type StepComparison struct {
+	Step         int
+	Base         *RequestResult
+	Target       *RequestResult
+	LatencyDelta time.Duration
+}
+
+// StatusChanged reports whether the request passed in one run and not in the other
+func (step *StepComparison) StatusChanged() bool {
+	return stepResult(step.Base) != stepResult(step.Target)
+}
+
+// CompareRuns compares two runs of the same test, typically the last passing run and a failing one. The requests are
+// paired by UUID when both runs include it and by position otherwise, so the results must be read with ReadResult
+func CompareRuns(a, b *TestResult) *RunComparison {
+	comparison := &RunComparison{Base: a, Target: b}
+
+	for i := 0; i < len(a.Requests) || i < len(b.Requests); i++ {
+		step := &StepComparison{Step: i + 1}
+		if i < len(a.Requests) {
+			step.Base = a.Requests[i]
+		}
+
+		if i < len(b.Requests) {
+			step.Target = b.Requests[i]
+		}
+
+		if step.Base != nil && step.Target != nil && step.Base.UUID != "" && step.Target.UUID != "" &&
+			step.Base.UUID != step.Target.UUID {
+			step.Target = findRequestResult(b.Requests, step.Base.UUID)
+		}
+
+		if step.Base != nil && step.Target != nil {
+			step.LatencyDelta = time.Duration(step.Target.ResponseTimeMs-step.Base.ResponseTimeMs) * time.Millisecond
+		}
+
+		comparison.Steps = append(comparison.Steps, step)
+	}
+
+	baseFailures := assertionFailureKeys(a.AssertionFailures())
+	targetFailures := assertionFailureKeys(b.AssertionFailures())
+	for _, failure := range b.AssertionFailures() {
+		if _, ok := baseFailures[assertionFailureKey(failure)]; !ok {
+			comparison.NewFailures = append(comparison.NewFailures, failure)
+		}
+	}
+
+	for _, failure := range a.AssertionFailures() {
+		if _, ok := targetFailures[assertionFailureKey(failure)]; !ok {
+			comparison.ResolvedFailures = append(comparison.ResolvedFailures, failure)
+		}
+	}
+
+	return comparison
+}
+
+// StatusChanges returns the requests that passed in one run and not in the other
+func (comparison *RunComparison) StatusChanges() []*StepComparison {
+	var changed []*StepComparison
+	for _, step := range comparison.Steps {
+		if step.StatusChanged() {
+			changed = append(changed, step)
+		}
+	}
+
+	return changed
+}
+
+// LatencyChanges returns the requests whose response time changed by more than threshold, faster or slower
+func (comparison *RunComparison) LatencyChanges(threshold time.Duration) []*StepComparison {
+	var changed []*StepComparison
+	for _, step := range comparison.Steps {
+		if step.LatencyDelta > threshold || step.LatencyDelta < -threshold {
+			changed = append(changed, step)
+		}
+	}
+
+	return changed
+}
+
+func stepResult(request *RequestResult) string {
+	if request == nil {
+		return ""
+	}
+
+	return request.Result
+}
+
+func findRequestResult(requests []*RequestResult, uuid string) *RequestResult {
+	for _, request := range requests {
+		if request.UUID == uuid {
+			return request
+		}
+	}
+
+	return nil
+}
+
+func assertionFailureKey(failure *AssertionFailure) string {
+	return fmt.Sprintf("%d %s %s %s %s %s", failure.Step, failure.Method, failure.URL, failure.Source,
+		failure.Property, failure.Comparison)
+}
+
+func assertionFailureKeys(failures []*AssertionFailure) map[string]*AssertionFailure {
+	keys := make(map[string]*AssertionFailure, len(failures))
+	for _, failure := range failures {
+		keys[assertionFailureKey(failure)] = failure
+	}
+
+	return keys
+}
@@ -0,0 +1,48 @@
+package runscope
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCompareRuns(t *testing.T) {
+	statusFailure := &AssertionResult{Result: TestResultFail, Source: AssertionSourceResponseStatus,
+		Comparison: ComparisonEqualNumber, TargetValue: float64(200), ActualValue: float64(503)}
+	timeFailure := &AssertionResult{Result: TestResultFail, Source: AssertionSourceResponseTime,
+		Comparison: ComparisonIsLessThan, TargetValue: float64(500), ActualValue: float64(900)}
+
+	green := &TestResult{Result: TestResultFail, Requests: []*RequestResult{
+		{UUID: "1", Method: "GET", URL: "https://example.com/health", Result: TestResultPass, ResponseTimeMs: 100},
+		{UUID: "2", Method: "GET", URL: "https://example.com/users", Result: TestResultFail, ResponseTimeMs: 900,
+			Assertions: []*AssertionResult{timeFailure}},
+	}}
+	red := &TestResult{Result: TestResultFail, Requests: []*RequestResult{
+		{UUID: "1", Method: "GET", URL: "https://example.com/health", Result: TestResultFail, ResponseTimeMs: 2100,
+			Assertions: []*AssertionResult{statusFailure}},
+		{UUID: "2", Method: "GET", URL: "https://example.com/users", Result: TestResultPass, ResponseTimeMs: 950},
+		{UUID: "3", Method: "DELETE", URL: "https://example.com/users/1", Result: TestResultPass, ResponseTimeMs: 80},
+	}}
+
+	comparison := CompareRuns(green, red)
+	if len(comparison.Steps) != 3 {
+		t.Fatalf("Expected %d steps, actual %d", 3, len(comparison.Steps))
+	}
+
+	changes := comparison.StatusChanges()
+	if len(changes) != 3 || changes[0].Step != 1 || changes[2].Base != nil {
+		t.Errorf("Expected status of all 3 steps to change, actual %d", len(changes))
+	}
+
+	latency := comparison.LatencyChanges(time.Second)
+	if len(latency) != 1 || latency[0].LatencyDelta != 2*time.Second {
+		t.Errorf("Expected step 1 to be 2s slower, actual %v", latency)
+	}
+
+	if len(comparison.NewFailures) != 1 || comparison.NewFailures[0].Source != AssertionSourceResponseStatus {
+		t.Errorf("Expected new status failure, actual %v", comparison.NewFailures)
+	}
+
+	if len(comparison.ResolvedFailures) != 1 || comparison.ResolvedFailures[0].Source != AssertionSourceResponseTime {
+		t.Errorf("Expected resolved response time failure, actual %v", comparison.ResolvedFailures)
+	}
+}
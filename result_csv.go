@@ -0,0 +1,66 @@
+package runscope
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+var resultCSVHeader = []string{"test", "test_run_id", "region", "environment", "started_at", "run_result", "step",
+	"method", "url", "status", "result", "duration_ms", "assertions_passed", "assertions_failed"}
+
+// WriteCSV writes runs of a test as CSV for analysis in spreadsheets, with a header row followed by a row for each
+// request of each run. Runs read by ListResults have no requests and are written as a single row, with the step
+// columns empty and the duration and assertion counts of the whole run
+func WriteCSV(writer io.Writer, test *Test, results []*TestResult) error {
+	csvWriter := csv.NewWriter(writer)
+	if err := csvWriter.Write(resultCSVHeader); err != nil {
+		return fmt.Errorf("Error writing CSV results: %s", err)
+	}
+
+	for _, result := range results {
+		for _, row := range resultCSVRows(test, result) {
+			if err := csvWriter.Write(row); err != nil {
+				return fmt.Errorf("Error writing CSV results: %s", err)
+			}
+		}
+	}
+
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+func resultCSVRows(test *Test, result *TestResult) [][]string {
+	startedAt := ""
+	if result.StartedAt != nil {
+		startedAt = result.StartedAt.UTC().Format(time.RFC3339)
+	}
+
+	run := []string{test.Name, result.TestRunID, result.Region, result.EnvironmentName, startedAt, result.Result}
+	if len(result.Requests) == 0 {
+		duration := ""
+		if result.StartedAt != nil && result.FinishedAt != nil {
+			duration = strconv.FormatInt(int64(result.FinishedAt.Sub(*result.StartedAt)/time.Millisecond), 10)
+		}
+
+		return [][]string{append(run, "", "", "", "", result.Result, duration,
+			strconv.Itoa(result.AssertionsPassed), strconv.Itoa(result.AssertionsFailed))}
+	}
+
+	var rows [][]string
+	for i, request := range result.Requests {
+		status := ""
+		if request.ResponseStatus != 0 {
+			status = strconv.Itoa(request.ResponseStatus)
+		}
+
+		row := append([]string{}, run...)
+		rows = append(rows, append(row, strconv.Itoa(i+1), request.Method, request.URL, status, request.Result,
+			strconv.Itoa(request.ResponseTimeMs), strconv.Itoa(request.AssertionsPassed),
+			strconv.Itoa(request.AssertionsFailed)))
+	}
+
+	return rows
+}
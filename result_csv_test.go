@@ -0,0 +1,59 @@
+package runscope
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteCSV(t *testing.T) {
+	started := time.Date(2021, 5, 6, 10, 0, 0, 0, time.UTC)
+	finished := started.Add(2 * time.Second)
+	results := []*TestResult{
+		{
+			TestRunID: "cd5b1b4a-3c4e-4a48-b3c7-a7c2b7cb3d6a", Result: TestResultFail, Region: "us1",
+			EnvironmentName: "staging", StartedAt: &started, FinishedAt: &finished,
+			Requests: []*RequestResult{
+				{Method: "GET", URL: "https://example.com/health", Result: TestResultPass, ResponseStatus: 200,
+					ResponseTimeMs: 120, AssertionsPassed: 1},
+				{Method: "GET", URL: "https://example.com/users/1", Result: TestResultFail, ResponseStatus: 404,
+					ResponseTimeMs: 80, AssertionsPassed: 1, AssertionsFailed: 1},
+			},
+		},
+		{
+			TestRunID: "0f1e7d48-3b0c-4c43-9d61-1a7b8d6f2b17", Result: TestResultPass, Region: "eu1",
+			StartedAt: &started, FinishedAt: &finished, AssertionsPassed: 2,
+		},
+	}
+
+	var buffer bytes.Buffer
+	if err := WriteCSV(&buffer, &Test{Name: "Smoke test"}, results); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := csv.NewReader(&buffer).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rows) != 4 {
+		t.Fatalf("Expected %d rows, actual %d", 4, len(rows))
+	}
+
+	if strings.Join(rows[0], ",") != strings.Join(resultCSVHeader, ",") {
+		t.Errorf("Expected header %v, actual %v", resultCSVHeader, rows[0])
+	}
+
+	expected := "Smoke test,cd5b1b4a-3c4e-4a48-b3c7-a7c2b7cb3d6a,us1,staging,2021-05-06T10:00:00Z,fail,2,GET," +
+		"https://example.com/users/1,404,fail,80,1,1"
+	if strings.Join(rows[2], ",") != expected {
+		t.Errorf("Expected row %s, actual %s", expected, strings.Join(rows[2], ","))
+	}
+
+	expected = "Smoke test,0f1e7d48-3b0c-4c43-9d61-1a7b8d6f2b17,eu1,,2021-05-06T10:00:00Z,pass,,,,,pass,2000,2,0"
+	if strings.Join(rows[3], ",") != expected {
+		t.Errorf("Expected row %s, actual %s", expected, strings.Join(rows[3], ","))
+	}
+}
@@ -0,0 +1,59 @@
+package runscope
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// RecentFailure is a failed run along with the test it belongs to
+type RecentFailure struct {
+	Test   *Test
+	Result *TestResult
+}
+
+// RecentFailures lists the failed runs of every test in a bucket started after since, newest first. The results of
+// the tests are read concurrently
+func (client *Client) RecentFailures(bucket *Bucket, since time.Time) ([]*RecentFailure, error) {
+	tests, err := client.ListAllTests(&ListTestsInput{BucketKey: bucket.Key})
+	if err != nil {
+		return nil, err
+	}
+
+	var mu sync.Mutex
+	var failures []*RecentFailure
+	pool := client.NewPool(&PoolOptions{Concurrency: fanOutConcurrency})
+	for _, test := range tests {
+		test := test
+		test.Bucket = bucket
+		pool.Submit(func() error {
+			var testFailures []*RecentFailure
+			results := client.IterateResults(test, &ResultFilter{Since: since, Results: []string{TestResultFail}})
+			for results.Next() {
+				testFailures = append(testFailures, &RecentFailure{Test: test, Result: results.Result()})
+			}
+
+			if err := results.Err(); err != nil {
+				return err
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			failures = append(failures, testFailures...)
+			return nil
+		})
+	}
+
+	if pool.Wait() != nil {
+		return nil, pool.Errors()[0]
+	}
+
+	sortRecentFailures(failures)
+	return failures, nil
+}
+
+func sortRecentFailures(failures []*RecentFailure) {
+	sort.SliceStable(failures, func(i, j int) bool {
+		return resultStarted(failures[i].Result).After(resultStarted(failures[j].Result))
+	})
+}
@@ -0,0 +1,48 @@
+package runscope
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecentFailures(t *testing.T) {
+	testPreCheck(t)
+	client := clientConfigure()
+	bucket, err := client.CreateBucket(&Bucket{Name: "test", Team: &Team{ID: teamID}})
+	defer client.DeleteBucket(bucket.Key)
+	if err != nil {
+		t.Error(err)
+	}
+
+	test, err := client.CreateTest(&Test{Name: "tf_test", Description: "This is a tf test", Bucket: bucket})
+	defer client.DeleteTest(test)
+	if err != nil {
+		t.Error(err)
+	}
+
+	failures, err := client.RecentFailures(bucket, time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, failure := range failures {
+		if failure.Result.Result != TestResultFail {
+			t.Errorf("Expected result %s, actual %s", TestResultFail, failure.Result.Result)
+		}
+	}
+}
+
+func TestSortRecentFailures(t *testing.T) {
+	start := time.Date(2021, 5, 6, 10, 0, 0, 0, time.UTC)
+	failures := []*RecentFailure{
+		{Test: &Test{Name: "a"}, Result: aggregateRun(TestResultFail, start, time.Minute, time.Second)},
+		{Test: &Test{Name: "b"}, Result: aggregateRun(TestResultFail, start, 3*time.Minute, time.Second)},
+		{Test: &Test{Name: "c"}, Result: aggregateRun(TestResultFail, start, 2*time.Minute, time.Second)},
+	}
+
+	sortRecentFailures(failures)
+	if failures[0].Test.Name != "b" || failures[1].Test.Name != "c" || failures[2].Test.Name != "a" {
+		t.Errorf("Expected failures newest first, actual %s %s %s",
+			failures[0].Test.Name, failures[1].Test.Name, failures[2].Test.Name)
+	}
+}
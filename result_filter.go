@@ -0,0 +1,193 @@
+package runscope
+
+import (
+	"time"
+)
+
+// defaultResultsPageSize is the most results the api lists at once
+const defaultResultsPageSize = 50
+
+// ResultFilter selects the results IterateResults returns. Since and Before are applied by the api, the other
+// criteria are checked by the client as the api does not support them, empty criteria match every result
+type ResultFilter struct {
+	// Since only returns results of runs started after this time
+	Since time.Time
+	// Before only returns results of runs started before this time
+	Before time.Time
+	// Results only returns runs with one of these results, e.g. TestResultFail
+	Results []string
+	// Regions only returns runs from one of these regions, e.g. us1
+	Regions []string
+	// EnvironmentID only returns runs in this environment
+	EnvironmentID string
+	// PageSize is the number of results read at once, defaults to 50
+	PageSize int
+}
+
+// ResultIterator pages through the results of a test newest first, see IterateResults
+type ResultIterator struct {
+	client  *Client
+	test    *Test
+	filter  ResultFilter
+	page    []*TestResult
+	seen    map[string]bool
+	current *TestResult
+	// cursor resumes the iteration after current, see Cursor
+	cursor time.Time
+	done   bool
+	err    error
+}
+
+// IterateResults returns an iterator over the results of a test matching filter, newest first, reading further pages
+// as needed using the start time of the oldest run read as cursor, until the retention window of the api is
+// exhausted. Only a page of results is held in memory at a time, e.g. to pull every failed run of an incident window:
+//
+//	results := client.IterateResults(test, &ResultFilter{Since: start, Before: end, Results: []string{TestResultFail}})
+//	for results.Next() {
+//		result := results.Result()
+//	}
+//	if err := results.Err(); err != nil {
+//	}
+func (client *Client) IterateResults(test *Test, filter *ResultFilter) *ResultIterator {
+	iterator := &ResultIterator{client: client, test: test, seen: map[string]bool{}}
+	if filter != nil {
+		iterator.filter = *filter
+	}
+
+	if iterator.filter.PageSize <= 0 {
+		iterator.filter.PageSize = defaultResultsPageSize
+	}
+
+	iterator.cursor = iterator.filter.Before
+	return iterator
+}
+
+// Next advances to the next matching result, it returns false when there are no more results or reading failed
+func (iterator *ResultIterator) Next() bool {
+	for {
+		for len(iterator.page) > 0 {
+			result := iterator.page[0]
+			iterator.page = iterator.page[1:]
+			if iterator.filter.matches(result) {
+				iterator.current = result
+				if result.StartedAt != nil {
+					iterator.cursor = result.StartedAt.Add(time.Second).Truncate(time.Second)
+				}
+				return true
+			}
+		}
+
+		if iterator.done || iterator.err != nil {
+			iterator.current = nil
+			return false
+		}
+
+		iterator.readPage()
+	}
+}
+
+// Result returns the current result
+func (iterator *ResultIterator) Result() *TestResult {
+	return iterator.current
+}
+
+// Err returns the error that stopped the iteration, if any
+func (iterator *ResultIterator) Err() error {
+	return iterator.err
+}
+
+func (iterator *ResultIterator) readPage() {
+	results, err := iterator.client.ListResults(iterator.test, &ListResultsInput{
+		Count:  iterator.filter.PageSize,
+		Since:  iterator.filter.Since,
+		Before: iterator.filter.Before,
+	})
+	if err != nil {
+		iterator.err = err
+		return
+	}
+
+	if len(results) < iterator.filter.PageSize {
+		iterator.done = true
+	}
+
+	// the test has no runs, or the previous page ended with the oldest run
+	if len(results) == 0 {
+		return
+	}
+
+	// before has a resolution of seconds, runs started within the same second can be listed on both pages
+	fresh := 0
+	for _, result := range results {
+		if iterator.seen[result.TestRunID] {
+			continue
+		}
+
+		iterator.seen[result.TestRunID] = true
+		iterator.page = append(iterator.page, result)
+		fresh++
+	}
+
+	oldest := results[len(results)-1]
+	if fresh == 0 && !iterator.done && oldest.StartedAt != nil {
+		// more than a page of runs started within the second before the cursor, the api can't list the others as
+		// before has a resolution of seconds, continue with the runs started earlier
+		ErrorF(1, "more than %d runs of test %s started at %s, some of them are skipped", iterator.filter.PageSize,
+			iterator.test.ID, oldest.StartedAt.Truncate(time.Second))
+		iterator.filter.Before = oldest.StartedAt.Truncate(time.Second)
+		return
+	}
+
+	if fresh == 0 || oldest.StartedAt == nil {
+		iterator.done = true
+		return
+	}
+
+	before := oldest.StartedAt.Add(time.Second).Truncate(time.Second)
+	if !iterator.filter.Before.IsZero() && !before.Before(iterator.filter.Before) {
+		before = iterator.filter.Before.Add(-time.Second)
+	}
+	iterator.filter.Before = before
+
+	// only runs of the last second can be listed again, forget the others so memory stays flat over long histories
+	iterator.seen = map[string]bool{}
+	for _, result := range results {
+		if result.StartedAt != nil && !result.StartedAt.Before(before.Add(-time.Second)) {
+			iterator.seen[result.TestRunID] = true
+		}
+	}
+}
+
+// Cursor returns the start time before which the results following the last one returned by Next were started. A job
+// streaming the history of a test can save it and later resume with ResultFilter{Before: cursor}, runs started in the
+// same second as the last result returned may then be returned again. The zero time means the iteration starts from
+// the newest run
+func (iterator *ResultIterator) Cursor() time.Time {
+	return iterator.cursor
+}
+
+func (filter *ResultFilter) matches(result *TestResult) bool {
+	if len(filter.Results) > 0 && !containsString(filter.Results, result.Result) {
+		return false
+	}
+
+	if len(filter.Regions) > 0 && !containsString(filter.Regions, result.Region) {
+		return false
+	}
+
+	if filter.EnvironmentID != "" && result.EnvironmentID != filter.EnvironmentID {
+		return false
+	}
+
+	return true
+}
+
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,163 @@
+package runscope
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestResultFilterMatches(t *testing.T) {
+	filter := &ResultFilter{Results: []string{TestResultFail}, Regions: []string{"us1", "eu1"}}
+
+	if !filter.matches(&TestResult{Result: TestResultFail, Region: "eu1"}) {
+		t.Error("Expected failed run in eu1 to match")
+	}
+
+	if filter.matches(&TestResult{Result: TestResultPass, Region: "us1"}) {
+		t.Error("Expected passed run not to match")
+	}
+
+	if filter.matches(&TestResult{Result: TestResultFail, Region: "ap1"}) {
+		t.Error("Expected run in ap1 not to match")
+	}
+
+	filter = &ResultFilter{EnvironmentID: "1eeb3695-5d0f-467c-9d51-8b773dce29ba"}
+	if filter.matches(&TestResult{EnvironmentID: "other"}) {
+		t.Error("Expected run in other environment not to match")
+	}
+
+	if !(&ResultFilter{}).matches(&TestResult{}) {
+		t.Error("Expected empty filter to match every run")
+	}
+}
+
+func TestIterateResults(t *testing.T) {
+	testPreCheck(t)
+	client := clientConfigure()
+	bucket, err := client.CreateBucket(&Bucket{Name: "test", Team: &Team{ID: teamID}})
+	defer client.DeleteBucket(bucket.Key)
+	if err != nil {
+		t.Error(err)
+	}
+
+	test, err := client.CreateTest(&Test{Name: "tf_test", Description: "This is a tf test", Bucket: bucket})
+	defer client.DeleteTest(test)
+	if err != nil {
+		t.Error(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err = client.TriggerTest(test, nil, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	results := client.IterateResults(test, &ResultFilter{Since: time.Now().Add(-time.Hour), PageSize: 2})
+	count := 0
+	for results.Next() {
+		if results.Result().TestID != test.ID {
+			t.Errorf("Expected test id %s, actual %s", test.ID, results.Result().TestID)
+		}
+		count++
+	}
+
+	if err = results.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if count != 3 {
+		t.Errorf("Expected %d results, actual %d", 3, count)
+	}
+
+	if results.Cursor().IsZero() {
+		t.Error("Expected cursor to be set after reading a page")
+	}
+}
+
+// resultsServer lists the runs started at the given unix times, newest first, like the api pages through results
+func resultsServer(startedAt []float64) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count, _ := strconv.Atoi(r.URL.Query().Get("count"))
+		before, _ := strconv.ParseInt(r.URL.Query().Get("before"), 10, 64)
+		var results []string
+		for i, started := range startedAt {
+			if (before == 0 || started < float64(before)) && len(results) < count {
+				results = append(results, fmt.Sprintf(`{"test_run_id": "%d", "started_at": %f}`, i, started))
+			}
+		}
+		fmt.Fprintf(w, `{"data": [%s]}`, strings.Join(results, ","))
+	}))
+}
+
+func iterateResultIDs(iterator *ResultIterator, limit int) []string {
+	var ids []string
+	for len(ids) < limit && iterator.Next() {
+		ids = append(ids, iterator.Result().TestRunID)
+	}
+
+	return ids
+}
+
+func TestIterateResultsCursor(t *testing.T) {
+	server := resultsServer([]float64{1000.5, 999.5, 998.5, 997.5, 996.5})
+	defer server.Close()
+
+	client := NewClient(server.URL, "token")
+	test := &Test{ID: "1", Bucket: &Bucket{Key: "z3n32gktzx94"}}
+	results := client.IterateResults(test, &ResultFilter{PageSize: 2})
+	if ids := iterateResultIDs(results, 3); strings.Join(ids, ",") != "0,1,2" {
+		t.Fatalf("Expected runs 0,1,2, actual %v", ids)
+	}
+
+	// run 3 was read with the second page but not returned yet, resuming must not skip it
+	resumed := client.IterateResults(test, &ResultFilter{PageSize: 2, Before: results.Cursor()})
+	ids := iterateResultIDs(resumed, 10)
+	if err := resumed.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Join(ids, ",") != "2,3,4" {
+		t.Errorf("Expected runs 2,3,4 after resuming, actual %v", ids)
+	}
+}
+
+func TestIterateResultsSameSecond(t *testing.T) {
+	RegisterLogHandlers(defaultHandler, defaultHandler, func(int, string, ...interface{}) {})
+	defer RegisterLogHandlers(defaultHandler, defaultHandler, defaultHandler)
+
+	server := resultsServer([]float64{1000.9, 1000.5, 1000.1, 999.5})
+	defer server.Close()
+
+	results := NewClient(server.URL, "token").IterateResults(&Test{ID: "1", Bucket: &Bucket{Key: "z3n32gktzx94"}},
+		&ResultFilter{PageSize: 2})
+	ids := iterateResultIDs(results, 10)
+	if err := results.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	// run 2 can't be listed, the api only pages by second, but the older history is still read
+	if strings.Join(ids, ",") != "0,1,3" {
+		t.Errorf("Expected runs 0,1,3, actual %v", ids)
+	}
+}
+
+func TestIterateResultsEmptyPage(t *testing.T) {
+	for _, startedAt := range [][]float64{nil, {1000.5, 999.5}} {
+		server := resultsServer(startedAt)
+		results := NewClient(server.URL, "token").IterateResults(
+			&Test{ID: "1", Bucket: &Bucket{Key: "z3n32gktzx94"}}, &ResultFilter{PageSize: 2})
+		ids := iterateResultIDs(results, 10)
+		server.Close()
+		if err := results.Err(); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(ids) != len(startedAt) {
+			t.Errorf("Expected %d runs, actual %v", len(startedAt), ids)
+		}
+	}
+}
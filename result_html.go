@@ -0,0 +1,138 @@
+package runscope
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"time"
+)
+
+var htmlReportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Test.Name}}</title>
+<style>
+body { font-family: -apple-system, Helvetica, Arial, sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 2em; }
+th, td { border-bottom: 1px solid #ddd; padding: 6px 8px; text-align: left; vertical-align: top; }
+.pass { color: #1a7f37; } .fail { color: #cf222e; } .other { color: #6e7781; }
+.bar { background: #54aeff; height: 10px; min-width: 1px; }
+.bar.fail { background: #ff8182; }
+ul { margin: 0; padding-left: 1.2em; }
+</style>
+</head>
+<body>
+<h1>{{.Test.Name}}</h1>
+{{range .Runs}}
+<h2><span class="{{.Class}}">{{.Result.Result}}</span> &middot; {{.Title}}</h2>
+<p>{{if .Result.StartedAt}}Started {{.Started}}, took {{.Duration}}. {{end}}Assertions passed {{.Result.AssertionsPassed}},
+failed {{.Result.AssertionsFailed}}.{{if .URL}} <a href="{{.URL}}">View run</a>{{end}}</p>
+<table>
+<tr><th>#</th><th>Request</th><th>Status</th><th>Result</th><th>Time</th><th></th><th>Assertions</th></tr>
+{{range .Steps}}
+<tr>
+<td>{{.Step}}</td>
+<td>{{.Name}}</td>
+<td>{{if .Request.ResponseStatus}}{{.Request.ResponseStatus}}{{end}}</td>
+<td class="{{.Class}}">{{.Request.Result}}</td>
+<td>{{.Request.ResponseTimeMs}} ms</td>
+<td style="width: 25%"><div class="bar {{.Class}}" style="width: {{.Width}}%"></div></td>
+<td><ul>{{range .Request.Assertions}}<li class="{{if eq .Result "pass"}}pass{{else}}fail{{end}}">{{.String}}</li>{{end}}</ul></td>
+</tr>
+{{end}}
+</table>
+{{end}}
+</body>
+</html>
+`))
+
+type htmlReport struct {
+	Test *Test
+	Runs []*htmlReportRun
+}
+
+type htmlReportRun struct {
+	Result   *TestResult
+	Title    string
+	Class    string
+	Started  string
+	Duration time.Duration
+	URL      string
+	Steps    []*htmlReportStep
+}
+
+type htmlReportStep struct {
+	Step    int
+	Name    string
+	Class   string
+	Width   int
+	Request *RequestResult
+}
+
+// WriteHTMLReport writes runs of a test as a self-contained html page, with a table of the requests of each run, a
+// chart of their response times and the outcome of their assertions, e.g. to attach to a deployment ticket. The
+// results must be read with ReadResult to include their requests
+func WriteHTMLReport(writer io.Writer, test *Test, results []*TestResult) error {
+	report := &htmlReport{Test: test}
+	for _, result := range results {
+		report.Runs = append(report.Runs, newHTMLReportRun(result))
+	}
+
+	if err := htmlReportTemplate.Execute(writer, report); err != nil {
+		return fmt.Errorf("Error writing html report: %s", err)
+	}
+
+	return nil
+}
+
+func newHTMLReportRun(result *TestResult) *htmlReportRun {
+	run := &htmlReportRun{Result: result, Title: result.TestRunID, Class: htmlResultClass(result.Result)}
+	if result.EnvironmentName != "" || result.Region != "" {
+		run.Title = fmt.Sprintf("%s %s", result.EnvironmentName, result.Region)
+	}
+
+	if result.TestRunID != "" && (result.TestRunURL != "" || result.BucketKey != "") {
+		run.URL = result.WebURL()
+	}
+
+	if result.StartedAt != nil {
+		run.Started = result.StartedAt.UTC().Format(time.RFC1123)
+		if result.FinishedAt != nil {
+			run.Duration = result.FinishedAt.Sub(*result.StartedAt)
+		}
+	}
+
+	slowest := 0
+	for _, request := range result.Requests {
+		if request.ResponseTimeMs > slowest {
+			slowest = request.ResponseTimeMs
+		}
+	}
+
+	for i, request := range result.Requests {
+		step := &htmlReportStep{
+			Step:    i + 1,
+			Name:    requestName(request),
+			Class:   htmlResultClass(request.Result),
+			Request: request,
+		}
+
+		if slowest > 0 {
+			step.Width = request.ResponseTimeMs * 100 / slowest
+		}
+
+		run.Steps = append(run.Steps, step)
+	}
+
+	return run
+}
+
+func htmlResultClass(result string) string {
+	switch result {
+	case TestResultPass, TestResultFail:
+		return result
+	}
+
+	return "other"
+}
@@ -0,0 +1,50 @@
+package runscope
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteHTMLReport(t *testing.T) {
+	started := time.Date(2021, 5, 6, 10, 0, 0, 0, time.UTC)
+	finished := started.Add(1500 * time.Millisecond)
+	result := &TestResult{
+		TestRunID:  "cd5b1b4a-3c4e-4a48-b3c7-a7c2b7cb3d6a",
+		TestID:     "8e7afae4-23b6-492a-b4b9-75d515b5082b",
+		BucketKey:  "z3n32gktzx94",
+		Result:     TestResultFail,
+		Region:     "us1",
+		StartedAt:  &started,
+		FinishedAt: &finished,
+		Requests: []*RequestResult{
+			{Method: "GET", URL: "https://example.com/health", Result: TestResultPass, ResponseStatus: 200,
+				ResponseTimeMs: 50},
+			{Method: "GET", URL: "https://example.com/users?name=<script>", Result: TestResultFail,
+				ResponseStatus: 503, ResponseTimeMs: 200, Assertions: []*AssertionResult{
+					{Result: TestResultFail, Source: AssertionSourceResponseStatus, Comparison: ComparisonEqualNumber,
+						TargetValue: float64(200), ActualValue: float64(503)},
+				}},
+		},
+	}
+
+	var buffer bytes.Buffer
+	if err := WriteHTMLReport(&buffer, &Test{Name: "Smoke test"}, []*TestResult{result}); err != nil {
+		t.Fatal(err)
+	}
+
+	report := buffer.String()
+	for _, expected := range []string{
+		"<title>Smoke test</title>",
+		"response_status equal_number: expected 200, got 503",
+		`<div class="bar fail" style="width: 100%"></div>`,
+		`<div class="bar pass" style="width: 25%"></div>`,
+		"https://example.com/users?name=&lt;script&gt;",
+		`href="https://www.runscope.com/radar/z3n32gktzx94/`,
+	} {
+		if !strings.Contains(report, expected) {
+			t.Errorf("Expected report to contain %s, actual\n%s", expected, report)
+		}
+	}
+}
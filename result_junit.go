@@ -0,0 +1,162 @@
+package runscope
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+type junitTestSuites struct {
+	XMLName xml.Name          `xml:"testsuites"`
+	Suites  []*junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string           `xml:"name,attr"`
+	ID        string           `xml:"id,attr,omitempty"`
+	Tests     int              `xml:"tests,attr"`
+	Failures  int              `xml:"failures,attr"`
+	Skipped   int              `xml:"skipped,attr"`
+	Time      string           `xml:"time,attr"`
+	Timestamp string           `xml:"timestamp,attr,omitempty"`
+	Cases     []*junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *struct{}     `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit writes runs of a test as JUnit XML, so runs triggered from CI show in the test reports of Jenkins,
+// GitLab or GitHub. Each run becomes a test suite and each of its requests a test case, failed assertions, scripts
+// and variables are reported as the failure of their test case. The results must be read with ReadResult to include
+// their requests
+func WriteJUnit(writer io.Writer, test *Test, results []*TestResult) error {
+	report := &junitTestSuites{}
+	for _, result := range results {
+		report.Suites = append(report.Suites, newJUnitTestSuite(test, result))
+	}
+
+	if _, err := io.WriteString(writer, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(writer)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return fmt.Errorf("Error writing JUnit report: %s", err)
+	}
+
+	_, err := io.WriteString(writer, "\n")
+	return err
+}
+
+func newJUnitTestSuite(test *Test, result *TestResult) *junitTestSuite {
+	name := test.Name
+	if result.Region != "" || result.EnvironmentName != "" {
+		name = fmt.Sprintf("%s (%s)", name, strings.Trim(result.EnvironmentName+" "+result.Region, " "))
+	}
+
+	suite := &junitTestSuite{Name: name, ID: result.TestRunID}
+	if result.StartedAt != nil {
+		suite.Timestamp = result.StartedAt.UTC().Format("2006-01-02T15:04:05")
+		if result.FinishedAt != nil {
+			suite.Time = junitSeconds(result.FinishedAt.Sub(*result.StartedAt))
+		}
+	}
+
+	for i, request := range result.Requests {
+		testCase := &junitTestCase{
+			Name:      junitTestCaseName(i, request),
+			ClassName: test.Name,
+			Time:      junitSeconds(time.Duration(request.ResponseTimeMs) * time.Millisecond),
+		}
+
+		switch request.Result {
+		case TestResultFail:
+			testCase.Failure = newJUnitFailure(request)
+			suite.Failures++
+		case TestResultPass:
+		default:
+			testCase.Skipped = &struct{}{}
+			suite.Skipped++
+		}
+
+		var output []string
+		for _, script := range request.Scripts {
+			if script.Output != "" {
+				output = append(output, script.Output)
+			}
+		}
+		testCase.SystemOut = strings.Join(output, "\n")
+
+		suite.Cases = append(suite.Cases, testCase)
+	}
+
+	suite.Tests = len(suite.Cases)
+	if suite.Time == "" {
+		suite.Time = junitSeconds(0)
+	}
+
+	return suite
+}
+
+func junitTestCaseName(index int, request *RequestResult) string {
+	return fmt.Sprintf("%d. %s", index+1, requestName(request))
+}
+
+// requestName describes a request of a run by its note, or its method and url when it has none
+func requestName(request *RequestResult) string {
+	name := strings.TrimSpace(request.Method + " " + request.URL)
+	if request.Note != "" {
+		name = request.Note
+	}
+
+	if name == "" {
+		name = request.StepType
+	}
+
+	return name
+}
+
+func newJUnitFailure(request *RequestResult) *junitFailure {
+	var lines []string
+	for _, assertion := range request.FailedAssertions() {
+		lines = append(lines, "assertion "+assertion.String())
+	}
+
+	for _, script := range request.Scripts {
+		if script.Result == TestResultFail {
+			lines = append(lines, fmt.Sprintf("script failed: %s", script.Error))
+		}
+	}
+
+	for _, variable := range request.Variables {
+		if variable.Result == TestResultFail {
+			lines = append(lines, fmt.Sprintf("variable %s not extracted: %s", variable.Name, variable.Error))
+		}
+	}
+
+	message := "request failed"
+	if len(lines) > 0 {
+		message = lines[0]
+	}
+
+	return &junitFailure{Message: message, Type: "AssertionFailure", Text: strings.Join(lines, "\n")}
+}
+
+func junitSeconds(duration time.Duration) string {
+	return fmt.Sprintf("%.3f", duration.Seconds())
+}
@@ -0,0 +1,65 @@
+package runscope
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteJUnit(t *testing.T) {
+	started := time.Date(2021, 5, 6, 10, 0, 0, 0, time.UTC)
+	finished := started.Add(1500 * time.Millisecond)
+	result := &TestResult{
+		TestRunID:       "cd5b1b4a-3c4e-4a48-b3c7-a7c2b7cb3d6a",
+		Result:          TestResultFail,
+		Region:          "us1",
+		EnvironmentName: "staging",
+		StartedAt:       &started,
+		FinishedAt:      &finished,
+		Requests: []*RequestResult{
+			{Method: "GET", URL: "https://example.com/health", Result: TestResultPass, ResponseTimeMs: 120,
+				Scripts: []*ScriptResult{{Result: TestResultPass, Output: "healthy"}}},
+			{Method: "GET", URL: "https://example.com/users/1", Result: TestResultFail, ResponseTimeMs: 80,
+				Assertions: []*AssertionResult{
+					{Result: TestResultFail, Source: AssertionSourceResponseStatus, Comparison: ComparisonEqualNumber,
+						TargetValue: float64(200), ActualValue: float64(404)},
+					{Result: TestResultPass, Source: AssertionSourceResponseTime, Comparison: ComparisonIsLessThan},
+				}},
+			{StepType: StepTypePause, Result: "skipped"},
+		},
+	}
+
+	var buffer bytes.Buffer
+	if err := WriteJUnit(&buffer, &Test{Name: "Smoke test"}, []*TestResult{result}); err != nil {
+		t.Fatal(err)
+	}
+
+	report := new(junitTestSuites)
+	if err := xml.Unmarshal(buffer.Bytes(), report); err != nil {
+		t.Fatal(err)
+	}
+
+	suite := report.Suites[0]
+	if suite.Name != "Smoke test (staging us1)" || suite.Tests != 3 || suite.Failures != 1 || suite.Skipped != 1 {
+		t.Errorf("Expected suite with 3 tests, 1 failure and 1 skipped, actual %#v", suite)
+	}
+
+	if suite.Time != "1.500" || suite.Timestamp != "2021-05-06T10:00:00" {
+		t.Errorf("Expected time 1.500 at 2021-05-06T10:00:00, actual %s %s", suite.Time, suite.Timestamp)
+	}
+
+	if suite.Cases[0].Name != "1. GET https://example.com/health" || suite.Cases[0].SystemOut != "healthy" {
+		t.Errorf("Expected passed health case with output, actual %#v", suite.Cases[0])
+	}
+
+	failure := suite.Cases[1].Failure
+	if failure == nil || failure.Message != "assertion response_status equal_number: expected 200, got 404" {
+		t.Errorf("Expected status assertion failure, actual %#v", failure)
+	}
+
+	if !strings.HasPrefix(buffer.String(), xml.Header) {
+		t.Error("Expected xml header")
+	}
+}
@@ -0,0 +1,80 @@
+package runscope
+
+import (
+	"time"
+)
+
+// RunRequest is the request sent and the response received by a request step of a run, including their bodies. See
+// https://www.runscope.com/docs/api/results#step-detail
+type RunRequest struct {
+	UUID     string            `json:"uuid,omitempty"`
+	Result   string            `json:"result,omitempty"`
+	Request  *CapturedRequest  `json:"request,omitempty"`
+	Response *CapturedResponse `json:"response,omitempty"`
+}
+
+// CapturedRequest is the http request sent by a request step
+type CapturedRequest struct {
+	Method    string     `json:"method,omitempty"`
+	URL       string     `json:"url,omitempty"`
+	Headers   Parameters `json:"headers,omitempty"`
+	Form      Parameters `json:"form,omitempty"`
+	Body      string     `json:"body,omitempty"`
+	SizeBytes int        `json:"size_bytes,omitempty"`
+	Timestamp *time.Time `json:"timestamp,omitempty"`
+}
+
+// CapturedResponse is the http response received by a request step
+type CapturedResponse struct {
+	Status         int        `json:"status,omitempty"`
+	Reason         string     `json:"reason,omitempty"`
+	Headers        Parameters `json:"headers,omitempty"`
+	Body           string     `json:"body,omitempty"`
+	SizeBytes      int        `json:"size_bytes,omitempty"`
+	ResponseTimeMs int        `json:"response_time_ms,omitempty"`
+	Timestamp      *time.Time `json:"timestamp,omitempty"`
+}
+
+// ReadRunRequest reads the request and response captured for a request of a run, requestID is the UUID of the
+// RequestResult returned by ReadResult
+func (client *Client) ReadRunRequest(test *Test, testRunID string, requestID string) (*RunRequest, error) {
+	endpoint, error := runRequestEndpoint(test, testRunID, requestID)
+	if error != nil {
+		return nil, error
+	}
+
+	resource, error := client.readResource("run request", requestID, endpoint)
+	if error != nil {
+		return nil, error
+	}
+
+	runRequest, error := getRunRequestFromResponse(resource.Data)
+	if error != nil {
+		return nil, error
+	}
+
+	return runRequest, nil
+}
+
+func runRequestEndpoint(test *Test, testRunID string, requestID string) (string, error) {
+	return bucketEndpoint(test.Bucket.Key, "/tests/%s/results/%s/steps/%s", test.ID, testRunID, requestID)
+}
+
+// setCaptureURLs points the requests of a run at the endpoint returning their captured request and response
+func (client *Client) setCaptureURLs(test *Test, result *TestResult) {
+	for _, request := range result.Requests {
+		if request.UUID == "" || request.CaptureURL != "" {
+			continue
+		}
+
+		if endpoint, err := runRequestEndpoint(test, result.TestRunID, request.UUID); err == nil {
+			request.CaptureURL = client.APIURL + endpoint
+		}
+	}
+}
+
+func getRunRequestFromResponse(response interface{}) (*RunRequest, error) {
+	runRequest := new(RunRequest)
+	err := decode(runRequest, response)
+	return runRequest, err
+}
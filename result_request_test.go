@@ -0,0 +1,87 @@
+package runscope
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestReadRunRequestFromResponse(t *testing.T) {
+	responseBody := `
+{
+  "meta": {
+    "status": "success"
+  },
+  "data": {
+    "uuid": "a3b5f0d2-9d7a-4a3e-8f0c-2c6c1e9b3a11",
+    "result": "fail",
+    "request": {
+      "method": "POST",
+      "url": "https://example.com/users",
+      "headers": {
+        "Content-Type": ["application/json"]
+      },
+      "body": "{\"name\": \"ada\"}",
+      "size_bytes": 15,
+      "timestamp": 1494023235.0
+    },
+    "response": {
+      "status": 503,
+      "reason": "Service Unavailable",
+      "headers": {
+        "Retry-After": "30"
+      },
+      "body": "upstream unavailable",
+      "size_bytes": 20,
+      "response_time_ms": 134
+    }
+  },
+  "error": null
+}
+`
+	responseMap := new(response)
+	if err := json.Unmarshal([]byte(responseBody), &responseMap); err != nil {
+		t.Error(err)
+	}
+
+	runRequest, err := getRunRequestFromResponse(responseMap.Data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if runRequest.Request.Method != "POST" || runRequest.Request.Body != `{"name": "ada"}` {
+		t.Errorf("Expected POST with body, actual %s %s", runRequest.Request.Method, runRequest.Request.Body)
+	}
+
+	if runRequest.Request.Headers.Get("Content-Type")[0] != "application/json" {
+		t.Errorf("Expected content type %s, actual %v", "application/json", runRequest.Request.Headers.Get("Content-Type"))
+	}
+
+	if runRequest.Response.Status != 503 || runRequest.Response.Body != "upstream unavailable" {
+		t.Errorf("Expected 503 upstream unavailable, actual %d %s", runRequest.Response.Status, runRequest.Response.Body)
+	}
+
+	if runRequest.Response.Headers.Get("Retry-After")[0] != "30" {
+		t.Errorf("Expected retry after %s, actual %v", "30", runRequest.Response.Headers.Get("Retry-After"))
+	}
+}
+
+func TestSetCaptureURLs(t *testing.T) {
+	client := &Client{APIURL: "https://api.runscope.com"}
+	test := &Test{ID: "8e7afae4-23b6-492a-b4b9-75d515b5082b", Bucket: &Bucket{Key: "z3n32gktzx94"}}
+	result := &TestResult{
+		TestRunID: "cd5b1b4a-3c4e-4a48-b3c7-a7c2b7cb3d6a",
+		Requests:  []*RequestResult{{UUID: "a3b5f0d2-9d7a-4a3e-8f0c-2c6c1e9b3a11"}, {StepType: StepTypePause}},
+	}
+
+	client.setCaptureURLs(test, result)
+
+	expected := "https://api.runscope.com/buckets/z3n32gktzx94/tests/8e7afae4-23b6-492a-b4b9-75d515b5082b" +
+		"/results/cd5b1b4a-3c4e-4a48-b3c7-a7c2b7cb3d6a/steps/a3b5f0d2-9d7a-4a3e-8f0c-2c6c1e9b3a11"
+	if result.Requests[0].CaptureURL != expected {
+		t.Errorf("Expected capture url %s, actual %s", expected, result.Requests[0].CaptureURL)
+	}
+
+	if result.Requests[1].CaptureURL != "" {
+		t.Errorf("Expected no capture url for pause step, actual %s", result.Requests[1].CaptureURL)
+	}
+}
@@ -0,0 +1,92 @@
+package runscope
+
+import (
+	"sort"
+	"sync"
+)
+
+// FailureAlert reports that a test failed Threshold times in a row, in Region when the detector is per region.
+// Failures are the failed runs of the streak, oldest first
+type FailureAlert struct {
+	Region   string
+	Failures []*TestResult
+}
+
+// FailureDetector watches a stream of results of a test and reports when threshold consecutive runs failed, e.g. to
+// escalate beyond the built-in notification thresholds. An alert is reported once per streak, a passed run resets it
+type FailureDetector struct {
+	threshold int
+	perRegion bool
+
+	mu      sync.Mutex
+	streaks map[string][]*TestResult
+}
+
+// NewFailureDetector creates a detector alerting after threshold consecutive failures, counted separately for each
+// region when perRegion is set
+func NewFailureDetector(threshold int, perRegion bool) *FailureDetector {
+	if threshold < 1 {
+		threshold = 1
+	}
+
+	return &FailureDetector{threshold: threshold, perRegion: perRegion, streaks: map[string][]*TestResult{}}
+}
+
+// Observe adds the result of a finished run, in the order the runs started, and returns an alert when it completes a
+// streak of failures, nil otherwise
+func (detector *FailureDetector) Observe(result *TestResult) *FailureAlert {
+	if result.Result != TestResultPass && result.Result != TestResultFail {
+		return nil
+	}
+
+	region := ""
+	if detector.perRegion {
+		region = result.Region
+	}
+
+	detector.mu.Lock()
+	defer detector.mu.Unlock()
+	if result.Result == TestResultPass {
+		delete(detector.streaks, region)
+		return nil
+	}
+
+	streak := append(detector.streaks[region], result)
+	detector.streaks[region] = streak
+	if len(streak) != detector.threshold {
+		return nil
+	}
+
+	return &FailureAlert{Region: region, Failures: append([]*TestResult{}, streak...)}
+}
+
+// ConsecutiveFailures reads the latest results of a test and returns an alert for each ongoing streak of at least
+// threshold failures, per region when perRegion is set
+func (client *Client) ConsecutiveFailures(test *Test, threshold int, perRegion bool) ([]*FailureAlert, error) {
+	results, err := client.ListResults(test, &ListResultsInput{Count: defaultResultsPageSize})
+	if err != nil {
+		return nil, err
+	}
+
+	return ongoingFailureStreaks(results, threshold, perRegion), nil
+}
+
+func ongoingFailureStreaks(results []*TestResult, threshold int, perRegion bool) []*FailureAlert {
+	runs := append([]*TestResult{}, results...)
+	sort.SliceStable(runs, func(i, j int) bool { return resultStarted(runs[i]).Before(resultStarted(runs[j])) })
+
+	detector := NewFailureDetector(threshold, perRegion)
+	for _, run := range runs {
+		detector.Observe(run)
+	}
+
+	var alerts []*FailureAlert
+	for region, streak := range detector.streaks {
+		if len(streak) >= detector.threshold {
+			alerts = append(alerts, &FailureAlert{Region: region, Failures: streak})
+		}
+	}
+
+	sort.Slice(alerts, func(i, j int) bool { return alerts[i].Region < alerts[j].Region })
+	return alerts
+}
@@ -0,0 +1,63 @@
+package runscope
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFailureDetector(t *testing.T) {
+	detector := NewFailureDetector(2, true)
+	run := func(result string, region string) *TestResult {
+		return &TestResult{Result: result, Region: region}
+	}
+
+	steps := []struct {
+		result *TestResult
+		alert  bool
+	}{
+		{run(TestResultFail, "us1"), false},
+		{run(TestResultFail, "eu1"), false},
+		{run(TestResultWorking, "us1"), false},
+		{run(TestResultFail, "us1"), true},
+		{run(TestResultFail, "us1"), false},
+		{run(TestResultPass, "eu1"), false},
+		{run(TestResultFail, "eu1"), false},
+		{run(TestResultFail, "eu1"), true},
+	}
+
+	for i, step := range steps {
+		alert := detector.Observe(step.result)
+		if (alert != nil) != step.alert {
+			t.Errorf("Expected alert %t for run %d, actual %v", step.alert, i, alert)
+		}
+
+		if alert != nil && (alert.Region != step.result.Region || len(alert.Failures) != 2) {
+			t.Errorf("Expected alert for 2 failures in %s, actual %d in %s",
+				step.result.Region, len(alert.Failures), alert.Region)
+		}
+	}
+}
+
+func TestOngoingFailureStreaks(t *testing.T) {
+	start := time.Date(2021, 5, 6, 10, 0, 0, 0, time.UTC)
+	results := []*TestResult{
+		aggregateRun(TestResultFail, start, 4*time.Minute, time.Second),
+		aggregateRun(TestResultFail, start, 3*time.Minute, time.Second),
+		aggregateRun(TestResultFail, start, 2*time.Minute, time.Second),
+		aggregateRun(TestResultPass, start, time.Minute, time.Second),
+		aggregateRun(TestResultFail, start, 0, time.Second),
+	}
+
+	alerts := ongoingFailureStreaks(results, 3, false)
+	if len(alerts) != 1 || len(alerts[0].Failures) != 3 {
+		t.Fatalf("Expected one streak of 3 failures, actual %v", alerts)
+	}
+
+	if !alerts[0].Failures[0].StartedAt.Equal(start.Add(2 * time.Minute)) {
+		t.Errorf("Expected streak to start at %s, actual %s", start.Add(2*time.Minute), alerts[0].Failures[0].StartedAt)
+	}
+
+	if alerts := ongoingFailureStreaks(results, 4, false); len(alerts) != 0 {
+		t.Errorf("Expected no streak of 4 failures, actual %v", alerts)
+	}
+}
@@ -0,0 +1,323 @@
+package runscope
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestReadResultFromResponse(t *testing.T) {
+	responseBody := `
+{
+  "meta": {
+    "status": "success"
+  },
+  "data": {
+    "test_run_id": "cd5b1b4a-3c4e-4a48-b3c7-a7c2b7cb3d6a",
+    "test_id": "8e7afae4-23b6-492a-b4b9-75d515b5082b",
+    "bucket_key": "z3n32gktzx94",
+    "result": "fail",
+    "region": "us1",
+    "environment_id": "1eeb3695-5d0f-467c-9d51-8b773dce29ba",
+    "environment_name": "Test Settings",
+    "started_at": 1494023235.0,
+    "finished_at": 1494023236.5,
+    "assertions_defined": 2,
+    "assertions_passed": 1,
+    "assertions_failed": 1
+  },
+  "error": null
+}
+`
+	responseMap := new(response)
+	if err := json.Unmarshal([]byte(responseBody), &responseMap); err != nil {
+		t.Error(err)
+	}
+
+	result, err := getTestResultFromResponse(responseMap.Data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Result != TestResultFail {
+		t.Errorf("Expected result %s, actual %s", TestResultFail, result.Result)
+	}
+
+	if !result.Done() {
+		t.Error("Expected failed result to be done")
+	}
+
+	if result.AssertionsFailed != 1 {
+		t.Errorf("Expected assertions failed %d, actual %d", 1, result.AssertionsFailed)
+	}
+
+	if result.FinishedAt.Sub(*result.StartedAt) != 1500*time.Millisecond {
+		t.Errorf("Expected duration %s, actual %s", 1500*time.Millisecond, result.FinishedAt.Sub(*result.StartedAt))
+	}
+
+	if result.BucketKey != "z3n32gktzx94" {
+		t.Errorf("Expected bucket key %s, actual %s", "z3n32gktzx94", result.BucketKey)
+	}
+}
+
+func TestTestResultDone(t *testing.T) {
+	for _, status := range []string{TestResultWorking, TestResultQueued, ""} {
+		if (&TestResult{Result: status}).Done() {
+			t.Errorf("Expected result %q not to be done", status)
+		}
+	}
+}
+
+func TestListResults(t *testing.T) {
+	testPreCheck(t)
+	client := clientConfigure()
+	bucket, err := client.CreateBucket(&Bucket{Name: "test", Team: &Team{ID: teamID}})
+	defer client.DeleteBucket(bucket.Key)
+	if err != nil {
+		t.Error(err)
+	}
+
+	test, err := client.CreateTest(&Test{Name: "tf_test", Description: "This is a tf test", Bucket: bucket})
+	defer client.DeleteTest(test)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if _, err = client.TriggerTest(test, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := client.ListResults(test, &ListResultsInput{Count: 5, Since: time.Now().Add(-time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, result := range results {
+		if result.TestID != test.ID {
+			t.Errorf("Expected test id %s, actual %s", test.ID, result.TestID)
+		}
+	}
+}
+
+func TestListResultsFromResponse(t *testing.T) {
+	responseBody := `
+{
+  "meta": {
+    "status": "success"
+  },
+  "data": [
+    {
+      "agent": null,
+      "test_run_id": "cd5b1b4a-3c4e-4a48-b3c7-a7c2b7cb3d6a",
+      "test_id": "8e7afae4-23b6-492a-b4b9-75d515b5082b",
+      "result": "pass",
+      "region": "us1",
+      "started_at": 1494023235.0,
+      "finished_at": 1494023236.5,
+      "assertions_defined": 2,
+      "assertions_passed": 2,
+      "assertions_failed": 0,
+      "requests_executed": 3
+    },
+    {
+      "test_run_id": "0f1e7d48-3b0c-4c43-9d61-1a7b8d6f2b17",
+      "test_id": "8e7afae4-23b6-492a-b4b9-75d515b5082b",
+      "result": "working",
+      "region": "eu1",
+      "started_at": 1494023835.0
+    }
+  ],
+  "error": null
+}
+`
+	responseMap := new(response)
+	if err := json.Unmarshal([]byte(responseBody), &responseMap); err != nil {
+		t.Error(err)
+	}
+
+	results, err := getTestResultsFromResponse(responseMap.Data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("Expected %d results, actual %d", 2, len(results))
+	}
+
+	if results[0].RequestsExecuted != 3 || results[0].Result != TestResultPass {
+		t.Errorf("Expected passed run with 3 requests, actual %s %d", results[0].Result, results[0].RequestsExecuted)
+	}
+
+	if results[1].Region != "eu1" || results[1].FinishedAt != nil || results[1].Done() {
+		t.Errorf("Expected unfinished run in eu1, actual %s %v", results[1].Region, results[1].FinishedAt)
+	}
+}
+
+func TestReadResultDetailFromResponse(t *testing.T) {
+	responseBody := `
+{
+  "meta": {
+    "status": "success"
+  },
+  "data": {
+    "test_run_id": "cd5b1b4a-3c4e-4a48-b3c7-a7c2b7cb3d6a",
+    "test_id": "8e7afae4-23b6-492a-b4b9-75d515b5082b",
+    "result": "fail",
+    "region": "us1",
+    "requests": [
+      {
+        "uuid": "a3b5f0d2-9d7a-4a3e-8f0c-2c6c1e9b3a11",
+        "step_type": "request",
+        "method": "GET",
+        "url": "https://example.com/users/1",
+        "result": "fail",
+        "response_status_code": 404,
+        "response_size_bytes": 21,
+        "response_time_ms": 134,
+        "timings": {
+          "dns_lookup_ms": 12.5,
+          "dial_ms": 20,
+          "ssl_handshake_ms": 40.25,
+          "send_headers_ms": 0.5,
+          "wait_for_response_ms": 55,
+          "receive_response_ms": 5.75
+        },
+        "assertions": [
+          {
+            "result": "fail",
+            "source": "response_status",
+            "comparison": "equal_number",
+            "target_value": 200,
+            "actual_value": 404,
+            "error": null
+          },
+          {
+            "result": "pass",
+            "source": "response_time_ms",
+            "comparison": "is_less_than",
+            "target_value": 500,
+            "actual_value": 134
+          }
+        ],
+        "scripts": [
+          {
+            "result": "pass",
+            "output": "checked user",
+            "error": null
+          }
+        ],
+        "variables": [
+          {
+            "result": "pass",
+            "name": "userId",
+            "source": "response_json",
+            "property": "id",
+            "value": "1"
+          }
+        ],
+        "assertions_defined": 2,
+        "assertions_passed": 1,
+        "assertions_failed": 1
+      }
+    ]
+  },
+  "error": null
+}
+`
+	responseMap := new(response)
+	if err := json.Unmarshal([]byte(responseBody), &responseMap); err != nil {
+		t.Error(err)
+	}
+
+	result, err := getTestResultFromResponse(responseMap.Data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Requests) != 1 {
+		t.Fatalf("Expected %d requests, actual %d", 1, len(result.Requests))
+	}
+
+	request := result.Requests[0]
+	if request.Method != "GET" || request.ResponseStatus != 404 || request.ResponseTimeMs != 134 {
+		t.Errorf("Expected GET with status 404 in 134ms, actual %s %d %d",
+			request.Method, request.ResponseStatus, request.ResponseTimeMs)
+	}
+
+	if request.Timings.DNSLookup() != 12500*time.Microsecond || request.Timings.TLSHandshake() != 40250*time.Microsecond {
+		t.Errorf("Expected dns 12.5ms and tls 40.25ms, actual %s %s",
+			request.Timings.DNSLookup(), request.Timings.TLSHandshake())
+	}
+
+	if request.Timings.TimeToFirstByte() != 55*time.Millisecond || request.Timings.Total() != 134*time.Millisecond {
+		t.Errorf("Expected ttfb 55ms of 134ms, actual %s %s", request.Timings.TimeToFirstByte(), request.Timings.Total())
+	}
+
+	failed := request.FailedAssertions()
+	if len(failed) != 1 || failed[0].Source != AssertionSourceResponseStatus || failed[0].ActualValue != float64(404) {
+		t.Errorf("Expected failed status assertion, actual %v", failed)
+	}
+
+	if request.Scripts[0].Output != "checked user" {
+		t.Errorf("Expected script output %s, actual %s", "checked user", request.Scripts[0].Output)
+	}
+
+	if request.Variables[0].Name != "userId" || request.Variables[0].Value != "1" {
+		t.Errorf("Expected variable userId=1, actual %s=%s", request.Variables[0].Name, request.Variables[0].Value)
+	}
+}
+
+func TestAssertionFailures(t *testing.T) {
+	result := &TestResult{
+		Requests: []*RequestResult{
+			{Method: "GET", URL: "https://example.com/health", Assertions: []*AssertionResult{
+				{Result: TestResultPass, Source: AssertionSourceResponseStatus, Comparison: ComparisonEqualNumber},
+			}},
+			{Method: "POST", URL: "https://example.com/users", Assertions: []*AssertionResult{
+				{Result: TestResultFail, Source: AssertionSourceResponseStatus, Comparison: ComparisonEqualNumber,
+					TargetValue: float64(200), ActualValue: float64(503)},
+				{Result: TestResultFail, Source: AssertionSourceResponseJSON, Property: "id",
+					Comparison: ComparisonNotEmpty, Error: "property not found"},
+			}},
+		},
+	}
+
+	failures := result.AssertionFailures()
+	if len(failures) != 2 {
+		t.Fatalf("Expected %d failures, actual %d", 2, len(failures))
+	}
+
+	if failures[0].Step != 2 || failures[0].Method != "POST" || failures[0].Expected != float64(200) {
+		t.Errorf("Expected failed POST on step 2, actual %#v", failures[0])
+	}
+
+	expected := "response_status equal_number: expected 200, got 503 on step 2"
+	if failures[0].String() != expected {
+		t.Errorf("Expected message %q, actual %q", expected, failures[0].String())
+	}
+
+	expected = "response_json id not_empty: expected (none), got (none) (property not found) on step 2"
+	if failures[1].String() != expected {
+		t.Errorf("Expected message %q, actual %q", expected, failures[1].String())
+	}
+}
+
+func TestFinalVariables(t *testing.T) {
+	result := &TestResult{
+		InitialVariables: map[string]string{"baseUrl": "https://example.com", "userId": "0"},
+		Requests: []*RequestResult{
+			{Variables: []*VariableResult{{Result: TestResultPass, Name: "userId", Value: "1"}}},
+			{Variables: []*VariableResult{{Result: TestResultFail, Name: "token", Error: "property not found"}}},
+		},
+	}
+
+	variables := result.FinalVariables()
+	if variables["baseUrl"] != "https://example.com" || variables["userId"] != "1" || len(variables) != 2 {
+		t.Errorf("Expected baseUrl and extracted userId, actual %v", variables)
+	}
+
+	result.Variables = map[string]string{"token": "abc"}
+	if variables := result.FinalVariables(); variables["token"] != "abc" || len(variables) != 1 {
+		t.Errorf("Expected variables returned by the api, actual %v", variables)
+	}
+}
@@ -0,0 +1,43 @@
+package runscope
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WebURL is the address of the Runscope web interface that result urls link to
+var WebURL = "https://www.runscope.com"
+
+// ResultURL returns the link to a run in the Runscope web interface, for notifications to deep-link readers straight to
+// the run. Readers need access to the bucket unless its results are shared
+func ResultURL(bucketKey BucketKey, testID string, testRunID string) string {
+	return fmt.Sprintf("%s/radar/%s/%s/history/%s", strings.TrimRight(WebURL, "/"), bucketKey, testID, testRunID)
+}
+
+// WebURL returns the link to the run in the Runscope web interface. The url returned by the api is preferred, as it
+// follows the sharing settings of the bucket
+func (result *TestResult) WebURL() string {
+	if result.TestRunURL != "" {
+		return result.TestRunURL
+	}
+
+	return ResultURL(result.BucketKey, result.TestID, result.TestRunID)
+}
+
+// WebURL returns the link to the run in the Runscope web interface, see TestResult.WebURL
+func (run *TriggerRun) WebURL() string {
+	if run.TestRunURL != "" {
+		return run.TestRunURL
+	}
+
+	return ResultURL(run.BucketKey, run.TestID, run.TestRunID)
+}
+
+// WebURL returns the link to the run in the Runscope web interface, see TestResult.WebURL
+func (notification *ResultNotification) WebURL() string {
+	if notification.TestRunURL != "" {
+		return notification.TestRunURL
+	}
+
+	return ResultURL(notification.BucketKey, notification.TestID, notification.TestRunID)
+}
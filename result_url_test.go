@@ -0,0 +1,29 @@
+package runscope
+
+import (
+	"testing"
+)
+
+func TestResultURL(t *testing.T) {
+	expected := "https://www.runscope.com/radar/z3n32gktzx94/8e7afae4-23b6-492a-b4b9-75d515b5082b/history/" +
+		"cd5b1b4a-3c4e-4a48-b3c7-a7c2b7cb3d6a"
+	result := &TestResult{
+		BucketKey: "z3n32gktzx94",
+		TestID:    "8e7afae4-23b6-492a-b4b9-75d515b5082b",
+		TestRunID: "cd5b1b4a-3c4e-4a48-b3c7-a7c2b7cb3d6a",
+	}
+
+	if result.WebURL() != expected {
+		t.Errorf("Expected url %s, actual %s", expected, result.WebURL())
+	}
+
+	result.TestRunURL = "https://www.runscope.com/radar/shared/cd5b1b4a"
+	if result.WebURL() != result.TestRunURL {
+		t.Errorf("Expected url %s, actual %s", result.TestRunURL, result.WebURL())
+	}
+
+	run := &TriggerRun{BucketKey: result.BucketKey, TestID: result.TestID, TestRunID: result.TestRunID}
+	if run.WebURL() != expected {
+		t.Errorf("Expected url %s, actual %s", expected, run.WebURL())
+	}
+}
@@ -0,0 +1,170 @@
+package runscope
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+)
+
+// resultPollInterval is how long WaitForResult first waits between reads of an unfinished run
+var resultPollInterval = 5 * time.Second
+
+// PollOptions controls how often WaitForResult reads an unfinished run, zero values use the defaults
+type PollOptions struct {
+	// Interval is the wait before the second read, defaults to 5 seconds
+	Interval time.Duration
+	// MaxInterval caps the wait between reads, defaults to 1 minute
+	MaxInterval time.Duration
+	// Multiplier grows the wait after each read of an unfinished run, defaults to 1.5
+	Multiplier float64
+}
+
+// rateLimitError is returned by requests the api rejected with 429 Too Many Requests, see IsRateLimited
+type rateLimitError struct {
+	err        error
+	retryAfter time.Duration
+}
+
+func newRateLimitError(err error, retryAfter string) *rateLimitError {
+	seconds, _ := strconv.Atoi(retryAfter)
+	return &rateLimitError{err: err, retryAfter: time.Duration(seconds) * time.Second}
+}
+
+func (err *rateLimitError) Error() string {
+	return err.err.Error()
+}
+
+func (err *rateLimitError) Unwrap() error {
+	return err.err
+}
+
+// IsRateLimited reports whether err, or an error it wraps, is a request the api rejected with 429 Too Many Requests
+func IsRateLimited(err error) bool {
+	var limited *rateLimitError
+	return errors.As(err, &limited)
+}
+
+// WaitForResult reads a run until its result is final, pass, fail or canceled, waiting longer after each read of an
+// unfinished run. Reads rejected by rate limiting are retried after the wait the api asks for. It returns ctx.Err()
+// if ctx is done first
+func (client *Client) WaitForResult(ctx context.Context, test *Test, testRunID string, opts *PollOptions) (*TestResult, error) {
+	poll := opts.withDefaults()
+	interval := poll.Interval
+	for {
+		wait := interval
+		result, err := client.ReadResult(test, testRunID)
+		var limited *rateLimitError
+		if errors.As(err, &limited) {
+			if limited.retryAfter > wait {
+				wait = limited.retryAfter
+			}
+		} else if err != nil {
+			return nil, err
+		} else if result.Done() {
+			return result, nil
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+
+		interval = poll.next(interval)
+	}
+}
+
+func (opts *PollOptions) withDefaults() *PollOptions {
+	poll := &PollOptions{Interval: resultPollInterval, MaxInterval: time.Minute, Multiplier: 1.5}
+	if opts == nil {
+		return poll
+	}
+
+	if opts.Interval > 0 {
+		poll.Interval = opts.Interval
+	}
+
+	if opts.MaxInterval > 0 {
+		poll.MaxInterval = opts.MaxInterval
+	}
+
+	if opts.Multiplier >= 1 {
+		poll.Multiplier = opts.Multiplier
+	}
+
+	return poll
+}
+
+func (opts *PollOptions) next(interval time.Duration) time.Duration {
+	next := time.Duration(float64(interval) * opts.Multiplier)
+	if next > opts.MaxInterval {
+		return opts.MaxInterval
+	}
+
+	return next
+}
+
+// WatchRun reads a run until its result is final, calling fn once for each request of the run as it completes, in
+// order, e.g. to print live progress of a long test. If fn returns an error watching stops and the error is returned.
+// It returns the final result, or ctx.Err() if ctx is done first
+func (client *Client) WatchRun(
+	ctx context.Context, test *Test, testRunID string, fn func(request *RequestResult) error) (*TestResult, error) {
+	reported := 0
+	for {
+		wait := resultPollInterval
+		result, err := client.ReadResult(test, testRunID)
+		var limited *rateLimitError
+		if errors.As(err, &limited) {
+			if limited.retryAfter > wait {
+				wait = limited.retryAfter
+			}
+		} else if err != nil {
+			return nil, err
+		} else {
+			var completed []*RequestResult
+			completed, reported = completedRequests(result, reported)
+			for _, request := range completed {
+				if err = fn(request); err != nil {
+					return result, err
+				}
+			}
+
+			if result.Done() {
+				return result, nil
+			}
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// completedRequests returns the requests completed since the first reported ones and the new number reported.
+// Requests run in order so the first unfinished request ends the completed ones, once the run is done every request
+// is reported
+func completedRequests(result *TestResult, reported int) ([]*RequestResult, int) {
+	// a later read may return fewer requests, e.g. from a replica that is behind
+	if reported > len(result.Requests) {
+		return nil, reported
+	}
+
+	var completed []*RequestResult
+	for _, request := range result.Requests[reported:] {
+		if !result.Done() && (request.Result == "" || request.Result == TestResultWorking ||
+			request.Result == TestResultQueued) {
+			break
+		}
+
+		completed = append(completed, request)
+	}
+
+	return completed, reported + len(completed)
+}
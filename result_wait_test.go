@@ -0,0 +1,154 @@
+package runscope
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWaitForResult(t *testing.T) {
+	testPreCheck(t)
+	client := clientConfigure()
+	bucket, err := client.CreateBucket(&Bucket{Name: "test", Team: &Team{ID: teamID}})
+	defer client.DeleteBucket(bucket.Key)
+	if err != nil {
+		t.Error(err)
+	}
+
+	test, err := client.CreateTest(&Test{Name: "tf_test", Description: "This is a tf test", Bucket: bucket})
+	defer client.DeleteTest(test)
+	if err != nil {
+		t.Error(err)
+	}
+
+	triggered, err := client.TriggerTest(test, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	result, err := client.WaitForResult(ctx, test, triggered.Runs[0].TestRunID, &PollOptions{Interval: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !result.Done() {
+		t.Errorf("Expected result to be done, actual %s", result.Result)
+	}
+}
+
+func TestPollOptions(t *testing.T) {
+	poll := (*PollOptions)(nil).withDefaults()
+	if poll.Interval != resultPollInterval || poll.MaxInterval != time.Minute || poll.Multiplier != 1.5 {
+		t.Errorf("Expected default poll options, actual %#v", poll)
+	}
+
+	poll = (&PollOptions{Interval: time.Second, MaxInterval: 3 * time.Second, Multiplier: 2}).withDefaults()
+	var intervals []time.Duration
+	for interval := poll.Interval; len(intervals) < 4; interval = poll.next(interval) {
+		intervals = append(intervals, interval)
+	}
+
+	expected := []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 3 * time.Second}
+	for i := range expected {
+		if intervals[i] != expected[i] {
+			t.Errorf("Expected intervals %v, actual %v", expected, intervals)
+			break
+		}
+	}
+}
+
+func TestRateLimitError(t *testing.T) {
+	err := newRateLimitError(errors.New("Status: 429 Too Many Requests Error reading test result: run"), "30")
+	if err.retryAfter != 30*time.Second {
+		t.Errorf("Expected retry after %s, actual %s", 30*time.Second, err.retryAfter)
+	}
+
+	if err.Error() != "Status: 429 Too Many Requests Error reading test result: run" {
+		t.Errorf("Expected wrapped message, actual %s", err.Error())
+	}
+
+	if newRateLimitError(err.err, "").retryAfter != 0 {
+		t.Error("Expected no retry after without header")
+	}
+}
+
+func TestCompletedRequests(t *testing.T) {
+	result := &TestResult{Result: TestResultWorking, Requests: []*RequestResult{
+		{URL: "https://example.com/1", Result: TestResultPass},
+		{URL: "https://example.com/2", Result: TestResultFail},
+		{URL: "https://example.com/3", Result: TestResultWorking},
+		{URL: "https://example.com/4"},
+	}}
+
+	completed, reported := completedRequests(result, 0)
+	if len(completed) != 2 || reported != 2 || completed[1].URL != "https://example.com/2" {
+		t.Errorf("Expected first 2 requests completed, actual %d %d", len(completed), reported)
+	}
+
+	completed, reported = completedRequests(result, reported)
+	if len(completed) != 0 || reported != 2 {
+		t.Errorf("Expected no new requests, actual %d %d", len(completed), reported)
+	}
+
+	result.Requests[2].Result = TestResultPass
+	result.Result = TestResultCanceled
+	completed, reported = completedRequests(result, reported)
+	if len(completed) != 2 || reported != 4 {
+		t.Errorf("Expected remaining requests once done, actual %d %d", len(completed), reported)
+	}
+
+	result.Requests = result.Requests[:1]
+	completed, reported = completedRequests(result, reported)
+	if len(completed) != 0 || reported != 4 {
+		t.Errorf("Expected no new requests from a read with fewer requests, actual %d %d", len(completed), reported)
+	}
+}
+
+func TestWatchRun(t *testing.T) {
+	testPreCheck(t)
+	client := clientConfigure()
+	bucket, err := client.CreateBucket(&Bucket{Name: "test", Team: &Team{ID: teamID}})
+	defer client.DeleteBucket(bucket.Key)
+	if err != nil {
+		t.Error(err)
+	}
+
+	test, err := client.CreateTest(&Test{Name: "tf_test", Description: "This is a tf test", Bucket: bucket})
+	defer client.DeleteTest(test)
+	if err != nil {
+		t.Error(err)
+	}
+
+	step := NewTestStep()
+	step.StepType = "request"
+	step.URL = "http://example.com"
+	step.Method = "GET"
+	if _, err = client.CreateTestStep(step, bucket.Key, test.ID); err != nil {
+		t.Error(err)
+	}
+
+	triggered, err := client.TriggerTest(test, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	var urls []string
+	_, err = client.WatchRun(ctx, test, triggered.Runs[0].TestRunID, func(request *RequestResult) error {
+		urls = append(urls, request.URL)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(urls) != 1 {
+		t.Errorf("Expected %d completed requests, actual %v", 1, urls)
+	}
+}
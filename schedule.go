@@ -1,13 +1,70 @@
 package runscope
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Schedule intervals supported by runscope
+const (
+	Every1Minute   ScheduleInterval = "1m"
+	Every5Minutes  ScheduleInterval = "5m"
+	Every15Minutes ScheduleInterval = "15m"
+	Every30Minutes ScheduleInterval = "30m"
+	EveryHour      ScheduleInterval = "1h"
+	Every6Hours    ScheduleInterval = "6h"
+	EveryDay       ScheduleInterval = "1d"
+)
+
+var scheduleIntervals = map[ScheduleInterval]time.Duration{
+	Every1Minute:   time.Minute,
+	Every5Minutes:  5 * time.Minute,
+	Every15Minutes: 15 * time.Minute,
+	Every30Minutes: 30 * time.Minute,
+	EveryHour:      time.Hour,
+	Every6Hours:    6 * time.Hour,
+	EveryDay:       24 * time.Hour,
+}
+
+// ScheduleInterval is how often a scheduled test runs, for example "5m" or "1h"
+type ScheduleInterval string
 
 // Schedule determines how often a test is executed. See https://www.runscope.com/docs/api/schedules
 type Schedule struct {
-	ID            string `json:"id,omitempty"`
-	EnvironmentID string `json:"environment_id,omitempty"`
-	Interval      string `json:"interval,omitempty"`
-	Note          string `json:"note,omitempty"`
+	ID            string           `json:"id,omitempty"`
+	EnvironmentID string           `json:"environment_id,omitempty"`
+	Interval      ScheduleInterval `json:"interval,omitempty"`
+	Note          string           `json:"note,omitempty"`
+	// environment is the not yet created environment a schedule built with TestBuilder runs in
+	environment *Environment
+}
+
+// ParseScheduleInterval converts a duration into the matching schedule interval, durations runscope does not support
+// return an error
+func ParseScheduleInterval(duration time.Duration) (ScheduleInterval, error) {
+	for interval, intervalDuration := range scheduleIntervals {
+		if intervalDuration == duration {
+			return interval, nil
+		}
+	}
+
+	return "", fmt.Errorf("Unsupported schedule interval: %s, expected one of 1m, 5m, 15m, 30m, 1h, 6h or 1d", duration)
+}
+
+// Validate checks the interval is one supported by runscope
+func (interval ScheduleInterval) Validate() error {
+	if _, ok := scheduleIntervals[interval]; !ok {
+		return fmt.Errorf("Unsupported schedule interval: %q, expected one of 1m, 5m, 15m, 30m, 1h, 6h or 1d",
+			string(interval))
+	}
+
+	return nil
+}
+
+// Duration returns the time between runs, zero for an unsupported interval
+func (interval ScheduleInterval) Duration() time.Duration {
+	return scheduleIntervals[interval]
 }
 
 // NewSchedule creates a new schedule struct
@@ -15,10 +72,23 @@ func NewSchedule() *Schedule {
 	return &Schedule{}
 }
 
+// NewEnvironmentSchedule creates a new schedule struct that runs a test in the environment at the interval
+func NewEnvironmentSchedule(environment *Environment, interval ScheduleInterval) *Schedule {
+	return &Schedule{EnvironmentID: environment.ID, Interval: interval}
+}
+
 // CreateSchedule creates a new test schedule. See https://www.runscope.com/docs/api/schedules#create
-func (client *Client) CreateSchedule(schedule *Schedule, bucketKey string, testID string) (*Schedule, error) {
-	newResource, error := client.createResource(schedule, "schedule", schedule.Note,
-		fmt.Sprintf("/buckets/%s/tests/%s/schedules", bucketKey, testID))
+func (client *Client) CreateSchedule(schedule *Schedule, bucketKey BucketKey, testID string) (*Schedule, error) {
+	if error := schedule.validate(); error != nil {
+		return nil, error
+	}
+
+	endpoint, error := bucketEndpoint(bucketKey, "/tests/%s/schedules", testID)
+	if error != nil {
+		return nil, error
+	}
+
+	newResource, error := client.createResource(schedule, "schedule", schedule.Note, endpoint)
 	if error != nil {
 		return nil, error
 	}
@@ -32,9 +102,13 @@ func (client *Client) CreateSchedule(schedule *Schedule, bucketKey string, testI
 }
 
 // ReadSchedule list details about an existing test schedule. See https://www.runscope.com/docs/api/schedules#detail
-func (client *Client) ReadSchedule(schedule *Schedule, bucketKey string, testID string) (*Schedule, error) {
-	resource, error := client.readResource("schedule", schedule.ID,
-		fmt.Sprintf("/buckets/%s/tests/%s/schedules/%s", bucketKey, testID, schedule.ID))
+func (client *Client) ReadSchedule(schedule *Schedule, bucketKey BucketKey, testID string) (*Schedule, error) {
+	endpoint, error := bucketEndpoint(bucketKey, "/tests/%s/schedules/%s", testID, schedule.ID)
+	if error != nil {
+		return nil, error
+	}
+
+	resource, error := client.readResource("schedule", schedule.ID, endpoint)
 	if error != nil {
 		return nil, error
 	}
@@ -48,9 +122,13 @@ func (client *Client) ReadSchedule(schedule *Schedule, bucketKey string, testID
 }
 
 // ListSchedules list all the schedules for a given test. See https://www.runscope.com/docs/api/schedules#list
-func (client *Client) ListSchedules(bucketKey string, testID string) ([]*Schedule, error) {
-	resource, error := client.readResource("[]schedule", testID,
-		fmt.Sprintf("/buckets/%s/tests/%s/schedules", bucketKey, testID))
+func (client *Client) ListSchedules(bucketKey BucketKey, testID string) ([]*Schedule, error) {
+	endpoint, error := bucketEndpoint(bucketKey, "/tests/%s/schedules", testID)
+	if error != nil {
+		return nil, error
+	}
+
+	resource, error := client.readResource("[]schedule", testID, endpoint)
 	if error != nil {
 		return nil, error
 	}
@@ -64,9 +142,17 @@ func (client *Client) ListSchedules(bucketKey string, testID string) ([]*Schedul
 }
 
 // UpdateSchedule updates an existing test schedule. See https://www.runscope.com/docs/api/schedules#modify
-func (client *Client) UpdateSchedule(schedule *Schedule, bucketKey string, testID string) (*Schedule, error) {
-	resource, error := client.updateResource(schedule, "schedule", schedule.ID,
-		fmt.Sprintf("/buckets/%s/tests/%s/schedules/%s", bucketKey, testID, schedule.ID))
+func (client *Client) UpdateSchedule(schedule *Schedule, bucketKey BucketKey, testID string) (*Schedule, error) {
+	if error := schedule.validate(); error != nil {
+		return nil, error
+	}
+
+	endpoint, error := bucketEndpoint(bucketKey, "/tests/%s/schedules/%s", testID, schedule.ID)
+	if error != nil {
+		return nil, error
+	}
+
+	resource, error := client.updateResource(schedule, "schedule", schedule.ID, endpoint)
 	if error != nil {
 		return nil, error
 	}
@@ -80,9 +166,21 @@ func (client *Client) UpdateSchedule(schedule *Schedule, bucketKey string, testI
 }
 
 // DeleteSchedule delete an existing test schedule. See https://www.runscope.com/docs/api/schedules#delete
-func (client *Client) DeleteSchedule(schedule *Schedule, bucketKey string, testID string) error {
-	return client.deleteResource("schedule", schedule.ID,
-		fmt.Sprintf("/buckets/%s/tests/%s/schedules/%s", bucketKey, testID, schedule.ID))
+func (client *Client) DeleteSchedule(schedule *Schedule, bucketKey BucketKey, testID string) error {
+	endpoint, error := bucketEndpoint(bucketKey, "/tests/%s/schedules/%s", testID, schedule.ID)
+	if error != nil {
+		return error
+	}
+
+	return client.deleteResource("schedule", schedule.ID, endpoint)
+}
+
+func (schedule *Schedule) validate() error {
+	if schedule.EnvironmentID == "" {
+		return errors.New("A schedule must specify 'EnvironmentID' property")
+	}
+
+	return schedule.Interval.Validate()
 }
 
 func getScheduleFromResponse(response interface{}) (*Schedule, error) {
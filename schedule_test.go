@@ -3,6 +3,7 @@ package runscope
 import (
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestCreateSchedule(t *testing.T) {
@@ -258,3 +259,60 @@ func TestListSchedules(t *testing.T) {
 		t.Errorf("Expected schedule interval %s, actual %s", "Hourly schedule", schedules[0].Interval)
 	}
 }
+
+func TestNewEnvironmentSchedule(t *testing.T) {
+	schedule := NewEnvironmentSchedule(&Environment{ID: "env"}, "1h")
+	if schedule.EnvironmentID != "env" {
+		t.Errorf("Expected environment id %s, actual %s", "env", schedule.EnvironmentID)
+	}
+
+	if schedule.Interval != "1h" {
+		t.Errorf("Expected interval %s, actual %s", "1h", schedule.Interval)
+	}
+}
+
+func TestScheduleIntervalValidate(t *testing.T) {
+	for _, interval := range []ScheduleInterval{Every1Minute, Every5Minutes, Every15Minutes, Every30Minutes,
+		EveryHour, Every6Hours, EveryDay} {
+		if err := interval.Validate(); err != nil {
+			t.Errorf("Expected interval %s to be valid, actual error %s", interval, err)
+		}
+	}
+
+	for _, interval := range []ScheduleInterval{"", "2m", "1w", "60m"} {
+		if err := interval.Validate(); err == nil {
+			t.Errorf("Expected interval %q to be invalid", interval)
+		}
+	}
+}
+
+func TestParseScheduleInterval(t *testing.T) {
+	interval, err := ParseScheduleInterval(6 * time.Hour)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if interval != Every6Hours {
+		t.Errorf("Expected interval %s, actual %s", Every6Hours, interval)
+	}
+
+	if interval.Duration() != 6*time.Hour {
+		t.Errorf("Expected duration %s, actual %s", 6*time.Hour, interval.Duration())
+	}
+
+	if _, err = ParseScheduleInterval(2 * time.Minute); err == nil {
+		t.Error("Expected error for unsupported duration")
+	}
+}
+
+func TestValidationScheduleInterval(t *testing.T) {
+	client := clientConfigure()
+	_, err := client.CreateSchedule(&Schedule{EnvironmentID: "env", Interval: "2m"}, "foo", "ba")
+	if err == nil {
+		t.Fatal("Expected validation error for unsupported interval")
+	}
+
+	if !strings.Contains(err.Error(), "Unsupported schedule interval") {
+		t.Errorf("Expected interval validation error, actual %s", err)
+	}
+}
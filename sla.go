@@ -0,0 +1,89 @@
+package runscope
+
+import (
+	"fmt"
+	"time"
+)
+
+// SLA objectives reported in SLAViolation.Objective
+const (
+	SLAAvailability = "availability"
+	SLALatency      = "latency"
+)
+
+// SLAChecker evaluates the runs of a test against availability and latency objectives, zero objectives are not checked
+type SLAChecker struct {
+	// Availability is the lowest acceptable pass rate, between 0 and 1, e.g. 0.999
+	Availability float64
+	// Latency is the highest acceptable run duration at LatencyPercentile
+	Latency time.Duration
+	// LatencyPercentile defaults to 95
+	LatencyPercentile float64
+}
+
+// SLAVerdict is the outcome of an SLA check, it passes when there are no violations
+type SLAVerdict struct {
+	Passed     bool
+	Aggregate  *ResultAggregate
+	Violations []*SLAViolation
+}
+
+// SLAViolation is an objective that was not met. Evidence holds the runs that caused it, the failed runs for
+// availability and the runs slower than the objective for latency
+type SLAViolation struct {
+	Objective string
+	Expected  string
+	Actual    string
+	Evidence  []*TestResult
+}
+
+func (violation *SLAViolation) String() string {
+	return fmt.Sprintf("%s: expected %s, got %s", violation.Objective, violation.Expected, violation.Actual)
+}
+
+// Check evaluates runs against the objectives of the checker, the runs are aggregated with Aggregate
+func (checker *SLAChecker) Check(results []*TestResult) *SLAVerdict {
+	aggregate := Aggregate(results)
+	verdict := &SLAVerdict{Aggregate: aggregate}
+
+	if checker.Availability > 0 && aggregate.Runs > 0 && aggregate.PassRate < checker.Availability {
+		violation := &SLAViolation{
+			Objective: SLAAvailability,
+			Expected:  fmt.Sprintf("%.3f%%", checker.Availability*100),
+			Actual:    fmt.Sprintf("%.3f%%", aggregate.PassRate*100),
+		}
+
+		for _, result := range results {
+			if result.Result == TestResultFail {
+				violation.Evidence = append(violation.Evidence, result)
+			}
+		}
+
+		verdict.Violations = append(verdict.Violations, violation)
+	}
+
+	percentile := checker.LatencyPercentile
+	if percentile <= 0 {
+		percentile = 95
+	}
+
+	if latency := aggregate.Percentile(percentile); checker.Latency > 0 && latency > checker.Latency {
+		violation := &SLAViolation{
+			Objective: SLALatency,
+			Expected:  fmt.Sprintf("p%g <= %s", percentile, checker.Latency),
+			Actual:    fmt.Sprintf("p%g %s", percentile, latency),
+		}
+
+		for _, result := range results {
+			if result.StartedAt != nil && result.FinishedAt != nil &&
+				result.FinishedAt.Sub(*result.StartedAt) > checker.Latency {
+				violation.Evidence = append(violation.Evidence, result)
+			}
+		}
+
+		verdict.Violations = append(verdict.Violations, violation)
+	}
+
+	verdict.Passed = len(verdict.Violations) == 0
+	return verdict
+}
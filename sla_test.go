@@ -0,0 +1,43 @@
+package runscope
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSLACheckerCheck(t *testing.T) {
+	start := time.Date(2021, 5, 6, 10, 0, 0, 0, time.UTC)
+	var results []*TestResult
+	for i := 0; i < 19; i++ {
+		results = append(results, aggregateRun(TestResultPass, start, time.Duration(i)*time.Minute, 200*time.Millisecond))
+	}
+	slow := aggregateRun(TestResultFail, start, 20*time.Minute, 3*time.Second)
+	results = append(results, slow)
+
+	verdict := (&SLAChecker{Availability: 0.99, Latency: time.Second}).Check(results)
+	if verdict.Passed {
+		t.Fatal("Expected SLA to be violated")
+	}
+
+	if len(verdict.Violations) != 1 || verdict.Violations[0].Objective != SLAAvailability {
+		t.Fatalf("Expected availability violation only, actual %v", verdict.Violations)
+	}
+
+	if verdict.Violations[0].String() != "availability: expected 99.000%, got 95.000%" {
+		t.Errorf("Expected availability message, actual %s", verdict.Violations[0])
+	}
+
+	if len(verdict.Violations[0].Evidence) != 1 || verdict.Violations[0].Evidence[0] != slow {
+		t.Errorf("Expected failed run as evidence, actual %v", verdict.Violations[0].Evidence)
+	}
+
+	verdict = (&SLAChecker{Latency: time.Second, LatencyPercentile: 99}).Check(results)
+	if verdict.Passed || verdict.Violations[0].Objective != SLALatency {
+		t.Errorf("Expected p99 latency violation, actual %v", verdict.Violations)
+	}
+
+	verdict = (&SLAChecker{Availability: 0.9, Latency: time.Second}).Check(results)
+	if !verdict.Passed {
+		t.Errorf("Expected SLA to pass, actual %v", verdict.Violations)
+	}
+}
@@ -0,0 +1,137 @@
+package runscope
+
+import (
+	"fmt"
+)
+
+// Request step authentication types. See https://www.runscope.com/docs/api/steps#request
+const (
+	AuthTypeBasic  = "basic"
+	AuthTypeOAuth1 = "oauth1"
+)
+
+// OAuth 1.0a signature methods
+const (
+	OAuth1SignatureHMACSHA1  = "HMAC-SHA1"
+	OAuth1SignaturePlainText = "PLAINTEXT"
+)
+
+// StepAuth is the authentication a request step signs its request with, either BasicAuth or OAuth1Auth
+type StepAuth interface {
+	AuthType() string
+	Validate() error
+	authMap() map[string]string
+}
+
+// BasicAuth authenticates a request step with http basic authentication
+type BasicAuth struct {
+	Username string
+	Password string
+}
+
+// OAuth1Auth signs a request step with OAuth 1.0a. SignatureMethod defaults to HMAC-SHA1, Token and TokenSecret
+// are only needed for requests made on behalf of a user
+type OAuth1Auth struct {
+	ConsumerKey     string
+	ConsumerSecret  string
+	SignatureMethod string
+	Token           string
+	TokenSecret     string
+}
+
+// AuthType returns AuthTypeBasic
+func (auth *BasicAuth) AuthType() string { return AuthTypeBasic }
+
+// AuthType returns AuthTypeOAuth1
+func (auth *OAuth1Auth) AuthType() string { return AuthTypeOAuth1 }
+
+// Validate checks that the username is set
+func (auth *BasicAuth) Validate() error {
+	if auth.Username == "" {
+		return fmt.Errorf("Basic auth must specify 'Username'")
+	}
+
+	return nil
+}
+
+// Validate checks that the consumer credentials are set, that a token comes with its secret and that the signature
+// method is supported
+func (auth *OAuth1Auth) Validate() error {
+	if auth.ConsumerKey == "" || auth.ConsumerSecret == "" {
+		return fmt.Errorf("OAuth1 auth must specify 'ConsumerKey' and 'ConsumerSecret'")
+	}
+
+	if (auth.Token == "") != (auth.TokenSecret == "") {
+		return fmt.Errorf("OAuth1 auth must specify both 'Token' and 'TokenSecret' or neither")
+	}
+
+	switch auth.SignatureMethod {
+	case "", OAuth1SignatureHMACSHA1, OAuth1SignaturePlainText:
+		return nil
+	default:
+		return fmt.Errorf("Unknown OAuth1 signature method: %q", auth.SignatureMethod)
+	}
+}
+
+func (auth *BasicAuth) authMap() map[string]string {
+	return map[string]string{
+		"auth_type": AuthTypeBasic,
+		"username":  auth.Username,
+		"password":  auth.Password,
+	}
+}
+
+func (auth *OAuth1Auth) authMap() map[string]string {
+	signatureMethod := auth.SignatureMethod
+	if signatureMethod == "" {
+		signatureMethod = OAuth1SignatureHMACSHA1
+	}
+
+	return map[string]string{
+		"auth_type":        AuthTypeOAuth1,
+		"consumer_key":     auth.ConsumerKey,
+		"consumer_secret":  auth.ConsumerSecret,
+		"signature_method": signatureMethod,
+		"token":            auth.Token,
+		"token_secret":     auth.TokenSecret,
+	}
+}
+
+// SetAuth validates auth and sets it as the auth block of the step, passing nil removes authentication. A removed
+// auth block is sent as an empty object, so UpdateTestStep clears the credentials the step had
+func (step *TestStep) SetAuth(auth StepAuth) error {
+	if auth == nil {
+		step.Auth = map[string]string{}
+		return nil
+	}
+
+	if err := auth.Validate(); err != nil {
+		return err
+	}
+
+	step.Auth = auth.authMap()
+	return nil
+}
+
+// TypedAuth returns the auth block of the step as a BasicAuth or OAuth1Auth, or nil when the step is not
+// authenticated
+func (step *TestStep) TypedAuth() (StepAuth, error) {
+	if len(step.Auth) == 0 {
+		return nil, nil
+	}
+
+	switch step.Auth["auth_type"] {
+	case AuthTypeBasic:
+		return &BasicAuth{Username: step.Auth["username"], Password: step.Auth["password"]}, nil
+	case AuthTypeOAuth1:
+		return &OAuth1Auth{
+			ConsumerKey:     step.Auth["consumer_key"],
+			ConsumerSecret:  step.Auth["consumer_secret"],
+			SignatureMethod: step.Auth["signature_method"],
+			Token:           step.Auth["token"],
+			TokenSecret:     step.Auth["token_secret"],
+		}, nil
+	default:
+		return nil, fmt.Errorf("Unknown auth type: %q", step.Auth["auth_type"])
+	}
+}
@@ -0,0 +1,94 @@
+package runscope
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestSetAuthBasic(t *testing.T) {
+	step := NewTestStep()
+	if err := step.SetAuth(&BasicAuth{Username: "user", Password: "pass"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if step.Auth["auth_type"] != AuthTypeBasic || step.Auth["username"] != "user" || step.Auth["password"] != "pass" {
+		t.Errorf("Expected basic auth block, actual %v", step.Auth)
+	}
+
+	auth, err := step.TypedAuth()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	basic, ok := auth.(*BasicAuth)
+	if !ok || basic.Username != "user" || basic.Password != "pass" {
+		t.Errorf("Expected basic auth user, actual %#v", auth)
+	}
+}
+
+func TestSetAuthOAuth1(t *testing.T) {
+	step := NewTestStep()
+	err := step.SetAuth(&OAuth1Auth{ConsumerKey: "key", ConsumerSecret: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if step.Auth["signature_method"] != OAuth1SignatureHMACSHA1 {
+		t.Errorf("Expected signature method %s, actual %s", OAuth1SignatureHMACSHA1, step.Auth["signature_method"])
+	}
+
+	auth, err := step.TypedAuth()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if oauth, ok := auth.(*OAuth1Auth); !ok || oauth.ConsumerKey != "key" {
+		t.Errorf("Expected oauth1 consumer key, actual %#v", auth)
+	}
+
+	if err = step.SetAuth(nil); err != nil || len(step.Auth) != 0 {
+		t.Errorf("Expected auth to be removed, actual %v %v", step.Auth, err)
+	}
+
+	data, err := json.Marshal(step)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(data), `"auth":{}`) {
+		t.Errorf("Expected removed auth to be sent as an empty object, actual %s", data)
+	}
+
+	if auth, err = step.TypedAuth(); auth != nil || err != nil {
+		t.Errorf("Expected no auth, actual %v %v", auth, err)
+	}
+}
+
+func TestStepAuthValidate(t *testing.T) {
+	invalid := []StepAuth{
+		&BasicAuth{Password: "pass"},
+		&OAuth1Auth{ConsumerKey: "key"},
+		&OAuth1Auth{ConsumerKey: "key", ConsumerSecret: "secret", Token: "token"},
+		&OAuth1Auth{ConsumerKey: "key", ConsumerSecret: "secret", SignatureMethod: "RSA-SHA1"},
+	}
+
+	for _, auth := range invalid {
+		if err := NewTestStep().SetAuth(auth); err == nil {
+			t.Errorf("Expected error for %#v", auth)
+		}
+	}
+
+	step := &TestStep{StepType: StepTypeRequest, Method: "GET", URL: "https://example.com",
+		Auth: map[string]string{"auth_type": AuthTypeBasic}}
+	if err := step.validate(); err == nil {
+		t.Error("Expected error for basic auth without username")
+	}
+
+	for _, auth := range []map[string]string{{"auth_type": "digest", "username": "user"}, {"username": "user"}} {
+		step.Auth = auth
+		if err := step.validate(); err != nil {
+			t.Errorf("Expected auth %v to be passed on, actual error %s", auth, err)
+		}
+	}
+}
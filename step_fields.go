@@ -0,0 +1,109 @@
+package runscope
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// stepFields are the json names of the step fields TestStep models, any other field is kept in TestStep.Extra
+var stepFields = jsonFieldNames(reflect.TypeOf(TestStep{}))
+
+// testStepJSON has the fields of TestStep without its json methods
+type testStepJSON TestStep
+
+// MarshalJSON encodes the step along with the fields in Extra, so configuration this package doesn't model is sent
+// back unchanged on update. An empty, not nil, Auth is encoded as an empty object removing the authentication of
+// the step, see SetAuth
+func (step *TestStep) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal((*testStepJSON)(step))
+	removeAuth := step.Auth != nil && len(step.Auth) == 0
+	if err != nil || (len(step.Extra) == 0 && !removeAuth) {
+		return data, err
+	}
+
+	var known map[string]json.RawMessage
+	if err = json.Unmarshal(data, &known); err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]interface{}, len(known)+len(step.Extra))
+	for name, value := range step.Extra {
+		fields[name] = value
+	}
+
+	for name, value := range known {
+		fields[name] = value
+	}
+
+	if removeAuth {
+		fields["auth"] = map[string]string{}
+	}
+
+	return json.Marshal(fields)
+}
+
+// UnmarshalJSON decodes the step, keeping fields this package doesn't model in Extra
+func (step *TestStep) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, (*testStepJSON)(step)); err != nil {
+		return err
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	step.Extra = unknownStepFields(fields)
+	return nil
+}
+
+// captureUnknownStepFields sets Extra of steps decoded from the raw response, including nested steps
+func captureUnknownStepFields(steps []*TestStep, response interface{}) {
+	items, ok := response.([]interface{})
+	if !ok {
+		return
+	}
+
+	for i, item := range items {
+		if i >= len(steps) {
+			return
+		}
+
+		fields, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		steps[i].Extra = unknownStepFields(fields)
+		captureUnknownStepFields(steps[i].Steps, fields["steps"])
+	}
+}
+
+func unknownStepFields(fields map[string]interface{}) map[string]interface{} {
+	var unknown map[string]interface{}
+	for name, value := range fields {
+		if stepFields[name] {
+			continue
+		}
+
+		if unknown == nil {
+			unknown = map[string]interface{}{}
+		}
+		unknown[name] = value
+	}
+
+	return unknown
+}
+
+func jsonFieldNames(structType reflect.Type) map[string]bool {
+	names := map[string]bool{}
+	for i := 0; i < structType.NumField(); i++ {
+		name := strings.Split(structType.Field(i).Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" {
+			names[name] = true
+		}
+	}
+
+	return names
+}
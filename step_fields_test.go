@@ -0,0 +1,110 @@
+package runscope
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestStepUnknownFieldsFromResponse(t *testing.T) {
+	responseBody := `
+{
+  "meta": {
+    "status": "success"
+  },
+  "data": {
+    "id": "8e7afae4-23b6-492a-b4b9-75d515b5082b",
+    "name": "Smoke test",
+    "steps": [
+      {
+        "id": "52e7c0b2-6f3a-4b77-a3e3-0ab3ad7c5b8a",
+        "step_type": "condition",
+        "left_value": "{{id}}",
+        "comparison": "not_empty",
+        "skip_on_failure": true,
+        "steps": [
+          {
+            "step_type": "request",
+            "method": "GET",
+            "url": "https://example.com",
+            "follow_redirects": false,
+            "retry": {"count": 3}
+          }
+        ]
+      }
+    ]
+  },
+  "error": null
+}
+`
+	responseMap := new(response)
+	if err := json.Unmarshal([]byte(responseBody), &responseMap); err != nil {
+		t.Fatal(err)
+	}
+
+	test, err := getTestFromResponse(responseMap.Data, responseMap.raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	condition := test.Steps[0]
+	if len(condition.Extra) != 1 || condition.Extra["skip_on_failure"] != true {
+		t.Errorf("Expected extra field skip_on_failure, actual %v", condition.Extra)
+	}
+
+	request := condition.Steps[0]
+	if request.Extra["follow_redirects"] != false || request.Extra["retry"] == nil {
+		t.Errorf("Expected extra fields follow_redirects and retry, actual %v", request.Extra)
+	}
+
+	data, err := json.Marshal(condition)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var encoded map[string]interface{}
+	if err = json.Unmarshal(data, &encoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if encoded["skip_on_failure"] != true || encoded["left_value"] != "{{id}}" {
+		t.Errorf("Expected extra and known fields to be encoded, actual %v", encoded)
+	}
+
+	nested := encoded["steps"].([]interface{})[0].(map[string]interface{})
+	if nested["follow_redirects"] != false || nested["url"] != "https://example.com" {
+		t.Errorf("Expected nested extra and known fields to be encoded, actual %v", nested)
+	}
+}
+
+func TestStepUnknownFieldsRoundTrip(t *testing.T) {
+	data := []byte(`{"step_type": "request", "method": "GET", "url": "https://example.com", "multipart": []}`)
+
+	step := new(TestStep)
+	if err := json.Unmarshal(data, step); err != nil {
+		t.Fatal(err)
+	}
+
+	if step.Method != "GET" || len(step.Extra) != 1 {
+		t.Errorf("Expected method GET and one extra field, actual %s %v", step.Method, step.Extra)
+	}
+
+	step.URL = "https://example.org"
+	encoded, err := json.Marshal(step)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"method":"GET","multipart":[],"step_type":"request","url":"https://example.org"}`
+	if string(encoded) != expected {
+		t.Errorf("Expected %s, actual %s", expected, string(encoded))
+	}
+
+	encoded, err = json.Marshal(&TestStep{StepType: StepTypePause, Duration: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(encoded) != `{"step_type":"pause","duration":5}` {
+		t.Errorf("Expected step without extra fields unchanged, actual %s", string(encoded))
+	}
+}
@@ -0,0 +1,54 @@
+package runscope
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+)
+
+// AddScriptFile appends the contents of a javascript file to the scripts run after the step's request
+func (step *TestStep) AddScriptFile(path string) error {
+	script, err := readScriptFile(path)
+	if err != nil {
+		return err
+	}
+
+	step.Scripts = append(step.Scripts, script)
+	return nil
+}
+
+// AddBeforeScriptFile appends the contents of a javascript file to the scripts run before the step's request
+func (step *TestStep) AddBeforeScriptFile(path string) error {
+	script, err := readScriptFile(path)
+	if err != nil {
+		return err
+	}
+
+	step.BeforeScripts = append(step.BeforeScripts, script)
+	return nil
+}
+
+// ScriptsHash returns a sha256 hash of the step's before scripts and scripts, so local and remote scripts can be
+// compared for drift without comparing their full contents
+func (step *TestStep) ScriptsHash() string {
+	hash := sha256.New()
+	for _, script := range step.BeforeScripts {
+		fmt.Fprintf(hash, "before:%d:%s", len(script), script)
+	}
+
+	for _, script := range step.Scripts {
+		fmt.Fprintf(hash, "after:%d:%s", len(script), script)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func readScriptFile(path string) (string, error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("Error reading script file: %s, %s", path, err)
+	}
+
+	return string(contents), nil
+}
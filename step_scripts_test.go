@@ -0,0 +1,56 @@
+package runscope
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAddScriptFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "runscope")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "script.js")
+	if err = ioutil.WriteFile(path, []byte("log(\"after\");"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	step := NewTestStep()
+	if err = step.AddScriptFile(path); err != nil {
+		t.Error(err)
+	}
+
+	if err = step.AddBeforeScriptFile(path); err != nil {
+		t.Error(err)
+	}
+
+	if step.Scripts[0] != "log(\"after\");" {
+		t.Errorf("Expected script %s, actual %s", "log(\"after\");", step.Scripts[0])
+	}
+
+	if len(step.BeforeScripts) != 1 {
+		t.Errorf("Expected %d before scripts, actual %d", 1, len(step.BeforeScripts))
+	}
+
+	if err = step.AddScriptFile(filepath.Join(dir, "missing.js")); err == nil {
+		t.Error("Expected error for missing script file")
+	}
+}
+
+func TestScriptsHash(t *testing.T) {
+	step := &TestStep{BeforeScripts: []string{"a"}, Scripts: []string{"b"}}
+	same := &TestStep{BeforeScripts: []string{"a"}, Scripts: []string{"b"}}
+	swapped := &TestStep{BeforeScripts: []string{"b"}, Scripts: []string{"a"}}
+
+	if step.ScriptsHash() != same.ScriptsHash() {
+		t.Error("Expected identical scripts to have the same hash")
+	}
+
+	if step.ScriptsHash() == swapped.ScriptsHash() {
+		t.Error("Expected before scripts and scripts to hash differently")
+	}
+}
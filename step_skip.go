@@ -0,0 +1,75 @@
+package runscope
+
+import (
+	"strings"
+)
+
+// The runscope api has no way to disable a step, a skipped step is wrapped in a condition step comparing these
+// values, which is never true
+const (
+	skipLeftValue  = "skipped"
+	skipRightValue = "run"
+	skipNotePrefix = "Skipped: "
+)
+
+// SkipStep returns a condition step wrapping step so it no longer runs, e.g. to disable a failing step during an
+// incident. The wrapper keeps the ID of the step so it can replace the step with UpdateTestStep, reason is kept in
+// its note
+func SkipStep(step *TestStep, reason string) *TestStep {
+	if step.Skipped() {
+		return step
+	}
+
+	inner := step.Clone()
+	inner.ID = ""
+	return &TestStep{
+		ID:         step.ID,
+		StepType:   StepTypeCondition,
+		Note:       skipNotePrefix + reason,
+		LeftValue:  skipLeftValue,
+		Comparison: ComparisonEqual,
+		RightValue: skipRightValue,
+		Steps:      []*TestStep{inner},
+	}
+}
+
+// UnskipStep returns the step wrapped by SkipStep with the ID of the wrapper, steps that are not skipped are returned
+// unchanged
+func UnskipStep(step *TestStep) *TestStep {
+	if !step.Skipped() {
+		return step
+	}
+
+	inner := step.Steps[0].Clone()
+	inner.ID = step.ID
+	return inner
+}
+
+// Skipped reports whether the step is a wrapper created by SkipStep
+func (step *TestStep) Skipped() bool {
+	return step.StepType == StepTypeCondition &&
+		step.LeftValue == skipLeftValue &&
+		step.Comparison == ComparisonEqual &&
+		step.RightValue == skipRightValue &&
+		strings.HasPrefix(step.Note, skipNotePrefix) &&
+		len(step.Steps) == 1
+}
+
+// SkipReason returns the reason a step was skipped with, or an empty string if it is not skipped
+func (step *TestStep) SkipReason() string {
+	if !step.Skipped() {
+		return ""
+	}
+
+	return strings.TrimPrefix(step.Note, skipNotePrefix)
+}
+
+// SkipTestStep disables a step of a test in place, see SkipStep
+func (client *Client) SkipTestStep(testStep *TestStep, bucketKey BucketKey, testID string, reason string) (*TestStep, error) {
+	return client.UpdateTestStep(SkipStep(testStep, reason), bucketKey, testID)
+}
+
+// UnskipTestStep enables a step disabled with SkipTestStep again, see UnskipStep
+func (client *Client) UnskipTestStep(testStep *TestStep, bucketKey BucketKey, testID string) (*TestStep, error) {
+	return client.UpdateTestStep(UnskipStep(testStep), bucketKey, testID)
+}
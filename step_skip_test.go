@@ -0,0 +1,55 @@
+package runscope
+
+import (
+	"testing"
+)
+
+func TestSkipStep(t *testing.T) {
+	step := &TestStep{
+		ID:         "52e7c0b2-6f3a-4b77-a3e3-0ab3ad7c5b8a",
+		StepType:   StepTypeRequest,
+		Method:     "GET",
+		URL:        "https://example.com",
+		Note:       "health",
+		Assertions: []*Assertion{AssertStatus().EqualsNumber(200)},
+	}
+
+	skipped := SkipStep(step, "INC-42 upstream outage")
+	if !skipped.Skipped() || skipped.ID != step.ID || skipped.StepType != StepTypeCondition {
+		t.Errorf("Expected condition wrapper with the step's ID, actual %#v", skipped)
+	}
+
+	if skipped.SkipReason() != "INC-42 upstream outage" {
+		t.Errorf("Expected reason %s, actual %s", "INC-42 upstream outage", skipped.SkipReason())
+	}
+
+	if reason, ok := unreachableCondition(skipped); !ok {
+		t.Errorf("Expected wrapper condition to never be true, actual %s", reason)
+	}
+
+	if SkipStep(skipped, "again") != skipped {
+		t.Error("Expected skipping a skipped step to return it unchanged")
+	}
+
+	unskipped := UnskipStep(skipped)
+	if !unskipped.Equal(step) || unskipped.ID != step.ID || unskipped.Skipped() {
+		t.Errorf("Expected original step back, actual %#v", unskipped)
+	}
+
+	if step.Skipped() || step.SkipReason() != "" || UnskipStep(step) != step {
+		t.Error("Expected step that is not skipped to be unchanged")
+	}
+}
+
+func TestLintSkippedStep(t *testing.T) {
+	test := &Test{Steps: []*TestStep{SkipStep(&TestStep{
+		StepType:   StepTypeRequest,
+		Method:     "GET",
+		URL:        "https://example.com",
+		Assertions: []*Assertion{AssertStatus().EqualsNumber(200)},
+	}, "flaky")}}
+
+	if issues := Lint(test); len(issues) != 0 {
+		t.Errorf("Expected skipped step not to be reported, actual %v", issues)
+	}
+}
@@ -0,0 +1,260 @@
+package runscope
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	// StepTypeRequest makes an http request. See https://www.runscope.com/docs/api/steps#request
+	StepTypeRequest = "request"
+	// StepTypePause waits before running the next step. See https://www.runscope.com/docs/api/steps#pause
+	StepTypePause = "pause"
+	// StepTypeCondition only runs its nested steps when the condition holds. See https://www.runscope.com/docs/api/steps#condition
+	StepTypeCondition = "condition"
+	// StepTypeGhostInspector runs a Ghost Inspector browser test. See https://www.runscope.com/docs/api/steps#ghost-inspector
+	StepTypeGhostInspector = "ghost-inspector"
+	// StepTypeSubtest runs another runscope test. See https://www.runscope.com/docs/api/steps#subtest
+	StepTypeSubtest = "subtest"
+)
+
+// Step is implemented by each typed step variant, only the fields valid for the step type can be expressed
+type Step interface {
+	// Type returns the step_type this variant is sent as
+	Type() string
+	// TestStep converts the variant into its wire representation
+	TestStep() *TestStep
+}
+
+// RequestStep makes an http request and checks the response
+type RequestStep struct {
+	ID            string
+	Note          string
+	Method        string
+	URL           string
+	Headers       Parameters
+	Form          Parameters
+	Body          string
+	Auth          map[string]string
+	Assertions    []*Assertion
+	Variables     []*Variable
+	Scripts       []string
+	BeforeScripts []string
+}
+
+// PauseStep waits for Duration seconds before running the next step
+type PauseStep struct {
+	ID       string
+	Note     string
+	Duration int
+}
+
+// ConditionStep runs Steps only when comparing LeftValue with RightValue succeeds
+type ConditionStep struct {
+	ID         string
+	Note       string
+	LeftValue  string
+	Comparison string
+	RightValue string
+	Steps      []Step
+}
+
+// GhostInspectorStep runs a Ghost Inspector browser test
+type GhostInspectorStep struct {
+	ID          string
+	Note        string
+	GhostTestID string
+	Assertions  []*Assertion
+	Variables   []*Variable
+}
+
+// SubtestStep runs another runscope test, optionally from another bucket or environment
+type SubtestStep struct {
+	ID            string
+	Note          string
+	TestUUID      string
+	BucketKey     BucketKey
+	EnvironmentID string
+	Assertions    []*Assertion
+	Variables     []*Variable
+}
+
+// Type returns StepTypeRequest
+func (step *RequestStep) Type() string { return StepTypeRequest }
+
+// Type returns StepTypePause
+func (step *PauseStep) Type() string { return StepTypePause }
+
+// Type returns StepTypeCondition
+func (step *ConditionStep) Type() string { return StepTypeCondition }
+
+// Type returns StepTypeGhostInspector
+func (step *GhostInspectorStep) Type() string { return StepTypeGhostInspector }
+
+// Type returns StepTypeSubtest
+func (step *SubtestStep) Type() string { return StepTypeSubtest }
+
+// TestStep converts the request step into its wire representation
+func (step *RequestStep) TestStep() *TestStep {
+	return &TestStep{
+		StepType:      StepTypeRequest,
+		ID:            step.ID,
+		Note:          step.Note,
+		Method:        step.Method,
+		URL:           step.URL,
+		Headers:       step.Headers,
+		Form:          step.Form,
+		Body:          step.Body,
+		Auth:          step.Auth,
+		Assertions:    step.Assertions,
+		Variables:     step.Variables,
+		Scripts:       step.Scripts,
+		BeforeScripts: step.BeforeScripts,
+	}
+}
+
+// TestStep converts the pause step into its wire representation
+func (step *PauseStep) TestStep() *TestStep {
+	return &TestStep{StepType: StepTypePause, ID: step.ID, Note: step.Note, Duration: step.Duration}
+}
+
+// TestStep converts the condition step, and its nested steps, into its wire representation
+func (step *ConditionStep) TestStep() *TestStep {
+	testStep := &TestStep{
+		StepType:   StepTypeCondition,
+		ID:         step.ID,
+		Note:       step.Note,
+		LeftValue:  step.LeftValue,
+		Comparison: step.Comparison,
+		RightValue: step.RightValue,
+	}
+
+	for _, nested := range step.Steps {
+		testStep.Steps = append(testStep.Steps, nested.TestStep())
+	}
+
+	return testStep
+}
+
+// TestStep converts the ghost inspector step into its wire representation
+func (step *GhostInspectorStep) TestStep() *TestStep {
+	return &TestStep{
+		StepType:    StepTypeGhostInspector,
+		ID:          step.ID,
+		Note:        step.Note,
+		GhostTestID: step.GhostTestID,
+		Assertions:  step.Assertions,
+		Variables:   step.Variables,
+	}
+}
+
+// TestStep converts the subtest step into its wire representation
+func (step *SubtestStep) TestStep() *TestStep {
+	return &TestStep{
+		StepType:      StepTypeSubtest,
+		ID:            step.ID,
+		Note:          step.Note,
+		TestUUID:      step.TestUUID,
+		BucketKey:     step.BucketKey,
+		EnvironmentID: step.EnvironmentID,
+		Assertions:    step.Assertions,
+		Variables:     step.Variables,
+	}
+}
+
+// MarshalJSON encodes the step in the format expected by the runscope api
+func (step *RequestStep) MarshalJSON() ([]byte, error) { return json.Marshal(step.TestStep()) }
+
+// MarshalJSON encodes the step in the format expected by the runscope api
+func (step *PauseStep) MarshalJSON() ([]byte, error) { return json.Marshal(step.TestStep()) }
+
+// MarshalJSON encodes the step in the format expected by the runscope api
+func (step *ConditionStep) MarshalJSON() ([]byte, error) { return json.Marshal(step.TestStep()) }
+
+// MarshalJSON encodes the step in the format expected by the runscope api
+func (step *GhostInspectorStep) MarshalJSON() ([]byte, error) { return json.Marshal(step.TestStep()) }
+
+// MarshalJSON encodes the step in the format expected by the runscope api
+func (step *SubtestStep) MarshalJSON() ([]byte, error) { return json.Marshal(step.TestStep()) }
+
+// Typed converts the wire representation into the variant matching its step_type
+func (step *TestStep) Typed() (Step, error) {
+	switch step.StepType {
+	case StepTypeRequest:
+		return &RequestStep{
+			ID:            step.ID,
+			Note:          step.Note,
+			Method:        step.Method,
+			URL:           step.URL,
+			Headers:       step.Headers,
+			Form:          step.Form,
+			Body:          step.Body,
+			Auth:          step.Auth,
+			Assertions:    step.Assertions,
+			Variables:     step.Variables,
+			Scripts:       step.Scripts,
+			BeforeScripts: step.BeforeScripts,
+		}, nil
+	case StepTypePause:
+		return &PauseStep{ID: step.ID, Note: step.Note, Duration: step.Duration}, nil
+	case StepTypeCondition:
+		condition := &ConditionStep{
+			ID:         step.ID,
+			Note:       step.Note,
+			LeftValue:  step.LeftValue,
+			Comparison: step.Comparison,
+			RightValue: step.RightValue,
+		}
+
+		for _, nested := range step.Steps {
+			typed, err := nested.Typed()
+			if err != nil {
+				return nil, err
+			}
+
+			condition.Steps = append(condition.Steps, typed)
+		}
+
+		return condition, nil
+	case StepTypeGhostInspector:
+		return &GhostInspectorStep{
+			ID:          step.ID,
+			Note:        step.Note,
+			GhostTestID: step.GhostTestID,
+			Assertions:  step.Assertions,
+			Variables:   step.Variables,
+		}, nil
+	case StepTypeSubtest:
+		return &SubtestStep{
+			ID:            step.ID,
+			Note:          step.Note,
+			TestUUID:      step.TestUUID,
+			BucketKey:     step.BucketKey,
+			EnvironmentID: step.EnvironmentID,
+			Assertions:    step.Assertions,
+			Variables:     step.Variables,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("Unknown step type: %q", step.StepType)
+}
+
+// UnmarshalStep decodes a step from json into the variant matching its step_type
+func UnmarshalStep(data []byte) (Step, error) {
+	testStep := new(TestStep)
+	if err := json.Unmarshal(data, testStep); err != nil {
+		return nil, err
+	}
+
+	return testStep.Typed()
+}
+
+// CreateStep creates a new runscope test step from a typed step variant. See https://www.runscope.com/docs/api/steps#add
+func (client *Client) CreateStep(step Step, bucketKey BucketKey, testID string) (Step, error) {
+	testStep, err := client.CreateTestStep(step.TestStep(), bucketKey, testID)
+	if err != nil {
+		return nil, err
+	}
+
+	return testStep.Typed()
+}
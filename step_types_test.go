@@ -0,0 +1,85 @@
+package runscope
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCreateStep(t *testing.T) {
+	testPreCheck(t)
+	client := clientConfigure()
+	bucket, err := client.CreateBucket(&Bucket{Name: "test", Team: &Team{ID: teamID}})
+	defer client.DeleteBucket(bucket.Key)
+	if err != nil {
+		t.Error(err)
+	}
+
+	test, err := client.CreateTest(&Test{Name: "tf_test", Description: "This is a tf test", Bucket: bucket})
+	defer client.DeleteTest(test)
+	if err != nil {
+		t.Error(err)
+	}
+
+	step, err := client.CreateStep(&PauseStep{Duration: 5}, bucket.Key, test.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pause, ok := step.(*PauseStep)
+	if !ok {
+		t.Fatalf("Expected pause step, actual %T", step)
+	}
+
+	if pause.Duration != 5 {
+		t.Errorf("Expected duration %d, actual %d", 5, pause.Duration)
+	}
+}
+
+func TestStepJSONRoundTrip(t *testing.T) {
+	condition := &ConditionStep{
+		LeftValue:  "{{status}}",
+		Comparison: "equal",
+		RightValue: "ok",
+		Steps: []Step{
+			&RequestStep{Method: "GET", URL: "http://example.com"},
+			&PauseStep{Duration: 2},
+		},
+	}
+
+	data, err := json.Marshal(condition)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	step, err := UnmarshalStep(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, ok := step.(*ConditionStep)
+	if !ok {
+		t.Fatalf("Expected condition step, actual %T", step)
+	}
+
+	if decoded.LeftValue != condition.LeftValue {
+		t.Errorf("Expected left value %s, actual %s", condition.LeftValue, decoded.LeftValue)
+	}
+
+	if len(decoded.Steps) != 2 {
+		t.Fatalf("Expected %d nested steps, actual %d", 2, len(decoded.Steps))
+	}
+
+	if request, ok := decoded.Steps[0].(*RequestStep); !ok || request.URL != "http://example.com" {
+		t.Errorf("Expected request step, actual %#v", decoded.Steps[0])
+	}
+
+	if decoded.Steps[1].Type() != StepTypePause {
+		t.Errorf("Expected step type %s, actual %s", StepTypePause, decoded.Steps[1].Type())
+	}
+}
+
+func TestUnmarshalStepUnknownType(t *testing.T) {
+	if _, err := UnmarshalStep([]byte(`{"step_type": "unknown"}`)); err == nil {
+		t.Error("Expected error for unknown step type")
+	}
+}
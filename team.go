@@ -2,15 +2,32 @@ package runscope
 
 import (
 	"fmt"
+	"strings"
 	"time"
 )
 
+// Roles of a person in a team, see People.Role
+const (
+	// RoleOwner manages billing and every setting of the team
+	RoleOwner = "owner"
+	// RoleAdmin manages the people, integrations and buckets of the team
+	RoleAdmin = "admin"
+	// RoleMember creates and edits tests in the buckets of the team
+	RoleMember = "member"
+	// RoleReadOnly views tests and results without changing them
+	RoleReadOnly = "read_only"
+)
+
+var roles = map[string]bool{RoleOwner: true, RoleAdmin: true, RoleMember: true, RoleReadOnly: true}
+
 // Integration represents an integration with a third-party. See https://www.runscope.com/docs/api/integrations
 type Integration struct {
 	ID              string `json:"id"`
 	UUID            string `json:"uuid"`
 	IntegrationType string `json:"type"`
 	Description     string `json:"description,omitempty"`
+	// Config holds every field returned by the api, see Details for a typed view
+	Config map[string]interface{} `json:"-"`
 }
 
 // People represents a person belonging to a team. See https://www.runscope.com/docs/api/teams
@@ -21,7 +38,69 @@ type People struct {
 	Email       string    `json:"email"`
 	CreatedAt   time.Time `json:"created_at"`
 	LastLoginAt time.Time `json:"last_login_at"`
-	GroupName   string    `json:"group_name"`
+	// Role is the role of the person in the team, one of the Role constants
+	Role string `json:"role"`
+	// GroupName and GroupID are the group the person belongs to, which sets their access to buckets
+	GroupName string `json:"group_name"`
+	GroupID   string `json:"group_id"`
+}
+
+// Invite is an invitation for a person to join a team
+type Invite struct {
+	Email string `json:"email"`
+	// Role is the role the person gets in the team, one of the Role constants, the api defaults to RoleMember
+	Role string `json:"role,omitempty"`
+	// GroupID is the group the person is added to, the default group of the team when empty
+	GroupID string `json:"group_id,omitempty"`
+}
+
+// Validate checks the invite has an email and a known role
+func (invite *Invite) Validate() error {
+	if !strings.Contains(invite.Email, "@") {
+		return fmt.Errorf("An invite must specify a valid 'Email', actual %q", invite.Email)
+	}
+
+	if invite.Role != "" && !roles[invite.Role] {
+		return fmt.Errorf("Invite for %s has unknown role %q, expected one of %s, %s, %s or %s",
+			invite.Email, invite.Role, RoleOwner, RoleAdmin, RoleMember, RoleReadOnly)
+	}
+
+	return nil
+}
+
+// Person is a single member of a team, the same record as listed by ListPeople
+type Person = People
+
+// TeamSummary is a team the access token belongs to along with the number of its remote agents and integrations
+type TeamSummary struct {
+	Team             *Team
+	AgentCount       int
+	IntegrationCount int
+}
+
+// ListTeams lists the teams the access token belongs to, their IDs are needed to create buckets and to list people
+func (client *Client) ListTeams() ([]*TeamSummary, error) {
+	account, error := client.ReadAccount()
+	if error != nil {
+		return nil, error
+	}
+
+	var teams []*TeamSummary
+	for _, team := range account.Teams {
+		agents, error := client.countTeamResources(team.ID, "agents")
+		if error != nil {
+			return nil, error
+		}
+
+		integrations, error := client.countTeamResources(team.ID, "integrations")
+		if error != nil {
+			return nil, error
+		}
+
+		teams = append(teams, &TeamSummary{Team: team, AgentCount: agents, IntegrationCount: integrations})
+	}
+
+	return teams, nil
 }
 
 // ListIntegrations list all configured integrations for your team. See https://www.runscope.com/docs/api/integrations
@@ -56,6 +135,61 @@ func (client *Client) ListPeople(teamID string) ([]*People, error) {
 	return people, nil
 }
 
+// InvitePerson invites a person to a team by email, they become a member once they accept the invitation
+func (client *Client) InvitePerson(teamID string, invite *Invite) (*Person, error) {
+	if error := invite.Validate(); error != nil {
+		return nil, error
+	}
+
+	resource, error := client.createResource(invite, "person", invite.Email, fmt.Sprintf("/teams/%s/people", teamID))
+	if error != nil {
+		return nil, error
+	}
+
+	person := new(Person)
+	if error = decode(person, resource.Data); error != nil {
+		return nil, error
+	}
+
+	return person, nil
+}
+
+// RemovePerson removes a person from a team by UUID, which revokes their access to the buckets of the team
+func (client *Client) RemovePerson(teamID string, uuid string) error {
+	return client.deleteResource("person", uuid, fmt.Sprintf("/teams/%s/people/%s", teamID, uuid))
+}
+
+func (client *Client) countTeamResources(teamID string, resourceType string) (int, error) {
+	resource, error := client.readResource(resourceType, teamID, fmt.Sprintf("/teams/%s/%s", teamID, resourceType))
+	if error != nil {
+		return 0, error
+	}
+
+	items, ok := resource.Data.([]interface{})
+	if !ok {
+		return 0, fmt.Errorf("Error reading %s of team %s: unexpected response %v", resourceType, teamID, resource.Data)
+	}
+
+	return len(items), nil
+}
+
+// ReadPerson reads a member of a team by UUID, e.g. to resolve the owner of a test or the recipient of its
+// notifications. The api has no detail endpoint, so the people of the team are listed
+func (client *Client) ReadPerson(teamID string, uuid string) (*Person, error) {
+	people, error := client.ListPeople(teamID)
+	if error != nil {
+		return nil, error
+	}
+
+	for _, person := range people {
+		if person.UUID == uuid || person.ID == uuid {
+			return person, nil
+		}
+	}
+
+	return nil, fmt.Errorf("Error reading person: %s not found in team %s", uuid, teamID)
+}
+
 func choose(items []*Integration, test func(*Integration) bool) (result []*Integration) {
 	for _, item := range items {
 		if test(item) {
@@ -69,6 +203,14 @@ func choose(items []*Integration, test func(*Integration) bool) (result []*Integ
 func getIntegrationFromResponse(response interface{}) ([]*Integration, error) {
 	var integrations []*Integration
 	err := decode(&integrations, response)
+	if items, ok := response.([]interface{}); ok {
+		for i, item := range items {
+			if config, ok := item.(map[string]interface{}); ok && i < len(integrations) {
+				integrations[i].Config = config
+			}
+		}
+	}
+
 	return integrations, err
 }
 
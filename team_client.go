@@ -0,0 +1,67 @@
+package runscope
+
+// TeamClient performs operations within a single team without passing the team into every call
+type TeamClient struct {
+	client *Client
+	team   *Team
+}
+
+// Team returns a TeamClient scoped to the team identified by uuid
+func (client *Client) Team(uuid string) *TeamClient {
+	return &TeamClient{client: client, team: &Team{ID: uuid}}
+}
+
+// ID returns the UUID of the team this client is scoped to
+func (teamClient *TeamClient) ID() string {
+	return teamClient.team.ID
+}
+
+// People lists the people on the team
+func (teamClient *TeamClient) People() ([]*People, error) {
+	return teamClient.client.ListPeople(teamClient.team.ID)
+}
+
+// Person reads a person on the team by UUID
+func (teamClient *TeamClient) Person(uuid string) (*Person, error) {
+	return teamClient.client.ReadPerson(teamClient.team.ID, uuid)
+}
+
+// Invite invites a person to the team
+func (teamClient *TeamClient) Invite(invite *Invite) (*Person, error) {
+	return teamClient.client.InvitePerson(teamClient.team.ID, invite)
+}
+
+// Remove removes a person from the team by UUID
+func (teamClient *TeamClient) Remove(uuid string) error {
+	return teamClient.client.RemovePerson(teamClient.team.ID, uuid)
+}
+
+// Agents lists the remote agents of the team
+func (teamClient *TeamClient) Agents() ([]*Agent, error) {
+	return teamClient.client.ListAgents(teamClient.team.ID)
+}
+
+// Integrations lists the integrations of the team
+func (teamClient *TeamClient) Integrations() ([]*Integration, error) {
+	return teamClient.client.ListIntegrations(teamClient.team.ID)
+}
+
+// FindIntegration finds the single integration of the team with the given type and description pattern
+func (teamClient *TeamClient) FindIntegration(integrationType string, description string) (*EnvironmentIntegration, error) {
+	return teamClient.client.FindIntegration(teamClient.team.ID, integrationType, description)
+}
+
+// Buckets lists the buckets owned by the team
+func (teamClient *TeamClient) Buckets() ([]*Bucket, error) {
+	return teamClient.client.ListBuckets(&ListBucketsInput{TeamID: teamClient.team.ID})
+}
+
+// CreateBucket creates a new bucket owned by the team
+func (teamClient *TeamClient) CreateBucket(name string) (*Bucket, error) {
+	return teamClient.client.CreateBucket(&Bucket{Name: name, Team: teamClient.team})
+}
+
+// Environments lists the shared and test environments of every bucket of the team
+func (teamClient *TeamClient) Environments() ([]*EnvironmentReference, error) {
+	return teamClient.client.ListTeamEnvironments(teamClient.team.ID)
+}
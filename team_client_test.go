@@ -0,0 +1,41 @@
+package runscope
+
+import (
+	"testing"
+)
+
+func TestTeamClient(t *testing.T) {
+	testPreCheck(t)
+	client := clientConfigure()
+	teamClient := client.Team(teamID)
+
+	bucket, err := teamClient.CreateBucket("test")
+	defer client.DeleteBucket(bucket.Key)
+	if err != nil {
+		t.Error(err)
+	}
+
+	buckets, err := teamClient.Buckets()
+	if err != nil {
+		t.Error(err)
+	}
+
+	found := false
+	for _, listed := range buckets {
+		if listed.Key == bucket.Key {
+			found = true
+		}
+	}
+
+	if !found {
+		t.Errorf("Expected bucket %s to be listed for team %s", bucket.Key, teamClient.ID())
+	}
+
+	if _, err = teamClient.People(); err != nil {
+		t.Error(err)
+	}
+
+	if _, err = teamClient.Agents(); err != nil {
+		t.Error(err)
+	}
+}
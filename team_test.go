@@ -1,6 +1,7 @@
 package runscope
 
 import (
+	"encoding/json"
 	"testing"
 )
 
@@ -33,3 +34,113 @@ func TestListIntegration(t *testing.T) {
 		t.Errorf("Expected UUID got %s", integrations[0].UUID)
 	}
 }
+
+func TestListTeams(t *testing.T) {
+	testPreCheck(t)
+	client := clientConfigure()
+	teams, err := client.ListTeams()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found := false
+	for _, team := range teams {
+		if team.Team.ID == teamID {
+			found = true
+		}
+	}
+
+	if !found {
+		t.Errorf("Expected team %s to be listed", teamID)
+	}
+}
+
+func TestListPeopleFromResponse(t *testing.T) {
+	responseBody := `
+{
+  "meta": {
+    "status": "success"
+  },
+  "data": [
+    {
+      "created_at": 1478817066,
+      "email": "grace@example.com",
+      "group_name": "Engineering",
+      "group_id": "5f3a7b8e-1c1d-4a5e-9b0a-3d6c7e8f9a0b",
+      "id": "4ee15ecc-7fe1-43cb-aa12-ef50420f2cf9",
+      "last_login_at": 1494023235,
+      "name": "Grace Hopper",
+      "role": "admin",
+      "uuid": "4ee15ecc-7fe1-43cb-aa12-ef50420f2cf9"
+    }
+  ],
+  "error": null
+}
+`
+	responseMap := new(response)
+	if err := json.Unmarshal([]byte(responseBody), &responseMap); err != nil {
+		t.Error(err)
+	}
+
+	people, err := getPeopleFromResponse(responseMap.Data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if people[0].Role != "admin" || people[0].GroupName != "Engineering" {
+		t.Errorf("Expected admin in Engineering, actual %s in %s", people[0].Role, people[0].GroupName)
+	}
+}
+
+func TestReadPerson(t *testing.T) {
+	testPreCheck(t)
+	client := clientConfigure()
+	people, err := client.ListPeople(teamID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	person, err := client.ReadPerson(teamID, people[0].UUID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if person.Email != people[0].Email {
+		t.Errorf("Expected email %s, actual %s", people[0].Email, person.Email)
+	}
+}
+
+func TestInviteAndRemovePerson(t *testing.T) {
+	testPreCheck(t)
+	client := clientConfigure()
+	person, err := client.InvitePerson(teamID, &Invite{Email: "go-runscope-test@example.com", Role: RoleMember})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if person.Email != "go-runscope-test@example.com" {
+		t.Errorf("Expected email %s, actual %s", "go-runscope-test@example.com", person.Email)
+	}
+
+	if err = client.RemovePerson(teamID, person.UUID); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestInviteValidate(t *testing.T) {
+	if err := (&Invite{Email: "grace@example.com", Role: RoleAdmin}).Validate(); err != nil {
+		t.Error(err)
+	}
+
+	if err := (&Invite{Email: "grace@example.com"}).Validate(); err != nil {
+		t.Error(err)
+	}
+
+	if err := (&Invite{Email: "grace@example.com", Role: "adminn"}).Validate(); err == nil {
+		t.Error("Expected an error for an unknown role")
+	}
+
+	if err := (&Invite{Role: RoleMember}).Validate(); err == nil {
+		t.Error("Expected an error for a missing email")
+	}
+}
@@ -4,9 +4,42 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"strings"
 	"time"
 )
 
+const (
+	// TestRunStatusCompleted is the status of a test run that has finished, see TestRun.Passed for its outcome
+	TestRunStatusCompleted = "completed"
+	// TestRunStatusWorking is the status of a test run that has not finished yet
+	TestRunStatusWorking = "working"
+)
+
+// Initiators of a test run, see TestRun.Initiator
+const (
+	// RunInitiatorSchedule is a run started by a schedule of the test
+	RunInitiatorSchedule = "schedule"
+	// RunInitiatorTrigger is a run started by the trigger url of the test or its bucket, e.g. from CI
+	RunInitiatorTrigger = "trigger"
+	// RunInitiatorUI is a run started manually from the Runscope web interface
+	RunInitiatorUI = "ui"
+	// RunInitiatorAPI is a run started through the api
+	RunInitiatorAPI = "api"
+	// RunInitiatorUnknown is a run whose source is not recognised
+	RunInitiatorUnknown = "unknown"
+)
+
+// Metrics timeframes and the region and environment value that aggregates all of them
+const (
+	MetricsTimeframeHour  = "hour"
+	MetricsTimeframeDay   = "day"
+	MetricsTimeframeWeek  = "week"
+	MetricsTimeframeMonth = "month"
+	MetricsAll            = "all"
+)
+
+// ReadMetricsInput selects the region, timeframe and environment metrics are read for, empty values default to all
+// regions and environments over a month
 type ReadMetricsInput struct {
 	Region          string
 	Timeframe       string
@@ -19,6 +52,7 @@ type Test struct {
 	Bucket               *Bucket        `json:"-"`
 	Name                 string         `json:"name,omitempty"`
 	Description          string         `json:"description,omitempty"`
+	TriggerURL           string         `json:"trigger_url,omitempty"`
 	CreatedAt            *time.Time     `json:"created_at,omitempty"`
 	CreatedBy            *Contact       `json:"created_by,omitempty"`
 	DefaultEnvironmentID string         `json:"default_environment_id,omitempty"`
@@ -80,6 +114,7 @@ type Script struct {
 	Value string `json:"value"`
 }
 
+// TestMetric holds response time metrics for a test. See https://www.runscope.com/docs/api/metrics
 type TestMetric struct {
 	ResponseTimes        []ResponseTime  `json:"response_times"`
 	EnvironemntUUID      string          `json:"environment_uuid,omitempty"`
@@ -89,12 +124,40 @@ type TestMetric struct {
 	ChangeFromLastPeriod TimePeriodMetic `json:"change_from_last_period"`
 }
 
+// ResponseTime is the average response time and success ratio of the runs within one interval of the timeframe
 type ResponseTime struct {
 	SuccessRatio          float64 `json:"success_ratio,omitempty"`
 	Timestamp             int64   `json:"timestamp,omitempty"`
 	AverageResponseTimeMs float64 `json:"avg_response_time_ms,omitempty"`
+	// measured is set when the api reported an average response time, it reports none for intervals without runs
+	measured bool
+}
+
+// UnmarshalJSON records whether the api reported an average response time for the interval, see HasRuns
+func (responseTime *ResponseTime) UnmarshalJSON(data []byte) error {
+	type responseTimeJSON ResponseTime
+	value := struct {
+		*responseTimeJSON
+		AverageResponseTimeMs *float64 `json:"avg_response_time_ms"`
+	}{responseTimeJSON: (*responseTimeJSON)(responseTime)}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	responseTime.measured = value.AverageResponseTimeMs != nil
+	if responseTime.measured {
+		responseTime.AverageResponseTimeMs = *value.AverageResponseTimeMs
+	}
+
+	return nil
 }
 
+// HasRuns reports whether the test ran within the interval, an interval where every run failed has runs too
+func (responseTime *ResponseTime) HasRuns() bool {
+	return responseTime.measured || responseTime.AverageResponseTimeMs != 0
+}
+
+// TimePeriodMetic holds response time percentiles over a whole timeframe
 type TimePeriodMetic struct {
 	ResponseTime50thPercentile float64 `json:"response_time_50th_percentile,omitempty"`
 	ResponseTime95thPercentile float64 `json:"response_time_95th_percentile,omitempty"`
@@ -131,13 +194,17 @@ func NewTest() *Test {
 
 // CreateTest creates a new runscope test. See https://www.runscope.com/docs/api/tests#create
 func (client *Client) CreateTest(test *Test) (*Test, error) {
-	newResource, error := client.createResource(test, "test", test.Name,
-		fmt.Sprintf("/buckets/%s/tests", test.Bucket.Key))
+	endpoint, error := bucketEndpoint(test.Bucket.Key, "/tests")
+	if error != nil {
+		return nil, error
+	}
+
+	newResource, error := client.createResource(test, "test", test.Name, endpoint)
 	if error != nil {
 		return nil, error
 	}
 
-	newTest, error := getTestFromResponse(newResource.Data)
+	newTest, error := getTestFromResponse(newResource.Data, newResource.raw)
 	if error != nil {
 		return nil, error
 	}
@@ -148,12 +215,17 @@ func (client *Client) CreateTest(test *Test) (*Test, error) {
 
 // ReadTest list details about an existing test. See https://www.runscope.com/docs/api/tests#detail
 func (client *Client) ReadTest(test *Test) (*Test, error) {
-	resource, error := client.readResource("test", test.ID, fmt.Sprintf("/buckets/%s/tests/%s", test.Bucket.Key, test.ID))
+	endpoint, error := bucketEndpoint(test.Bucket.Key, "/tests/%s", test.ID)
 	if error != nil {
 		return nil, error
 	}
 
-	readTest, error := getTestFromResponse(resource.Data)
+	resource, error := client.readResource("test", test.ID, endpoint)
+	if error != nil {
+		return nil, error
+	}
+
+	readTest, error := getTestFromResponse(resource.Data, resource.raw)
 	if error != nil {
 		return nil, error
 	}
@@ -164,12 +236,17 @@ func (client *Client) ReadTest(test *Test) (*Test, error) {
 
 // UpdateTest update an existing test. See https://www.runscope.com/docs/api/tests#modifying
 func (client *Client) UpdateTest(test *Test) (*Test, error) {
-	resource, error := client.updateResource(test, "test", test.ID, fmt.Sprintf("/buckets/%s/tests/%s", test.Bucket.Key, test.ID))
+	endpoint, error := bucketEndpoint(test.Bucket.Key, "/tests/%s", test.ID)
+	if error != nil {
+		return nil, error
+	}
+
+	resource, error := client.updateResource(test, "test", test.ID, endpoint)
 	if error != nil {
 		return nil, error
 	}
 
-	readTest, error := getTestFromResponse(resource.Data)
+	readTest, error := getTestFromResponse(resource.Data, resource.raw)
 	if error != nil {
 		return nil, error
 	}
@@ -180,7 +257,109 @@ func (client *Client) UpdateTest(test *Test) (*Test, error) {
 
 // DeleteTest delete an existing test. See https://www.runscope.com/docs/api/tests#delete
 func (client *Client) DeleteTest(test *Test) error {
-	return client.deleteResource("test", test.ID, fmt.Sprintf("/buckets/%s/tests/%s", test.Bucket.Key, test.ID))
+	endpoint, error := bucketEndpoint(test.Bucket.Key, "/tests/%s", test.ID)
+	if error != nil {
+		return error
+	}
+
+	return client.deleteResource("test", test.ID, endpoint)
+}
+
+// LatestResult returns the summary of the most recent run of a test, or nil if the test has never run
+func (client *Client) LatestResult(test *Test) (*TestRun, error) {
+	readTest, err := client.ReadTest(test)
+	if err != nil {
+		return nil, err
+	}
+
+	return readTest.LastRun, nil
+}
+
+// Finished reports whether the run has completed
+func (run *TestRun) Finished() bool {
+	return run.Status == TestRunStatusCompleted
+}
+
+// Passed reports whether the run completed without errors and with every assertion, script and variable extraction
+// succeeding
+func (run *TestRun) Passed() bool {
+	return run.Finished() &&
+		run.ErrorCount == 0 &&
+		run.AssertionSuccess == run.AssertionCount &&
+		run.ScriptSuccess == run.ScriptCount &&
+		run.ExtractorSuccess == run.ExtractorCount &&
+		run.SubstitutionSuccess == run.SubstitutionCount
+}
+
+// Initiator returns what started the run, one of the RunInitiator constants, derived from Source which the api
+// reports in several spellings
+func (run *TestRun) Initiator() string {
+	switch strings.ToLower(run.Source) {
+	case "schedule", "scheduled", "scheduler":
+		return RunInitiatorSchedule
+	case "trigger", "triggered", "trigger_url", "batch_trigger":
+		return RunInitiatorTrigger
+	case "ui", "editor", "manual", "dashboard", "web":
+		return RunInitiatorUI
+	case "api":
+		return RunInitiatorAPI
+	}
+
+	return RunInitiatorUnknown
+}
+
+// Scheduled reports whether the run was started by a schedule, as opposed to a manual or triggered verification run
+func (run *TestRun) Scheduled() bool {
+	return run.Initiator() == RunInitiatorSchedule
+}
+
+// Result returns the outcome of the run as one of TestResultPass, TestResultFail or TestResultWorking
+func (run *TestRun) Result() string {
+	if !run.Finished() {
+		return TestResultWorking
+	}
+
+	if run.Passed() {
+		return TestResultPass
+	}
+
+	return TestResultFail
+}
+
+// TestDetail is a test together with its steps, schedules and environments
+type TestDetail struct {
+	Test         *Test
+	Steps        []*TestStep
+	Schedules    []*Schedule
+	Environments []*Environment
+}
+
+// ReadTestFull reads a test, its steps, schedules and environments concurrently
+func (client *Client) ReadTestFull(test *Test) (*TestDetail, error) {
+	detail := &TestDetail{}
+	pool := client.NewPool(&PoolOptions{Concurrency: 4})
+	pool.Submit(func() (err error) {
+		detail.Test, err = client.ReadTest(test)
+		return
+	})
+	pool.Submit(func() (err error) {
+		detail.Steps, err = client.ListTestSteps(test.Bucket.Key, test.ID)
+		return
+	})
+	pool.Submit(func() (err error) {
+		detail.Schedules, err = client.ListSchedules(test.Bucket.Key, test.ID)
+		return
+	})
+	pool.Submit(func() (err error) {
+		detail.Environments, err = client.ListTestEnvironment(test.Bucket, test)
+		return
+	})
+
+	if pool.Wait() != nil {
+		return nil, pool.Errors()[0]
+	}
+
+	return detail, nil
 }
 
 // ReadTestMetrics retrieves metrics for a test. See https://www.runscope.com/docs/api/metrics
@@ -190,19 +369,22 @@ func (client *Client) ReadTestMetrics(test *Test, input *ReadMetricsInput) (*Tes
 	timeframe := input.Timeframe
 	environmentUUID := input.EnvironemntUUID
 	if region == "" {
-		region = "all"
+		region = MetricsAll
 	}
 	if timeframe == "" {
-		timeframe = "month"
+		timeframe = MetricsTimeframeMonth
 	}
 	if environmentUUID == "" {
-		environmentUUID = "all"
+		environmentUUID = MetricsAll
 	}
 
 	DebugF(2, "	reading %s %s", "metrics", test.ID)
 
-	endpoint := fmt.Sprintf("/buckets/%s/tests/%s/metrics?region=%s&timeframe=%s&environment_uuid=%s",
-		test.Bucket.Key, test.ID, region, timeframe, environmentUUID)
+	endpoint, err := bucketEndpoint(test.Bucket.Key, "/tests/%s/metrics?region=%s&timeframe=%s&environment_uuid=%s",
+		test.ID, region, timeframe, environmentUUID)
+	if err != nil {
+		return nil, err
+	}
 
 	DebugF(2, "	request: GET %s", endpoint)
 	req, err := client.newRequest("GET", endpoint, nil)
@@ -220,6 +402,16 @@ func (client *Client) ReadTestMetrics(test *Test, input *ReadMetricsInput) (*Tes
 	bodyString := string(bodyBytes)
 	DebugF(2, "	response: %d %s", resp.StatusCode, bodyString)
 
+	if resp.StatusCode >= 300 {
+		errorResp := new(errorResponse)
+		if err = json.Unmarshal(bodyBytes, &errorResp); err != nil {
+			return nil, fmt.Errorf("Status: %s Error reading metrics: %s", resp.Status, test.ID)
+		}
+
+		return nil, fmt.Errorf("Status: %s Error reading metrics: %s, reason: %q",
+			resp.Status, test.ID, errorResp.ErrorMessage)
+	}
+
 	readTestMetrics := &TestMetric{}
 	err = json.Unmarshal(bodyBytes, readTestMetrics)
 
@@ -230,6 +422,65 @@ func (client *Client) ReadTestMetrics(test *Test, input *ReadMetricsInput) (*Tes
 	return readTestMetrics, nil
 }
 
+// TestMetricsSummary aggregates the metrics of a test over a timeframe, for feeding SLO dashboards
+type TestMetricsSummary struct {
+	Region                     string
+	Timeframe                  string
+	EnvironmentUUID            string
+	AverageResponseTimeMs      float64
+	ResponseTime50thPercentile float64
+	ResponseTime95thPercentile float64
+	ResponseTime99thPercentile float64
+	SuccessRatio               float64
+	TotalTestRuns              float64
+}
+
+// TestMetrics reads the metrics of a test and summarises them into response time aggregates and a success ratio
+func (client *Client) TestMetrics(test *Test, opts *ReadMetricsInput) (*TestMetricsSummary, error) {
+	if opts == nil {
+		opts = &ReadMetricsInput{}
+	}
+
+	metric, err := client.ReadTestMetrics(test, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return metric.Summary(), nil
+}
+
+// Summary averages the response times and success ratios of the intervals that had runs, see ResponseTime.HasRuns.
+// The api doesn't report how many runs an interval had, so every interval weighs the same
+func (metric *TestMetric) Summary() *TestMetricsSummary {
+	summary := &TestMetricsSummary{
+		Region:                     metric.Region,
+		Timeframe:                  metric.Timeframe,
+		EnvironmentUUID:            metric.EnvironemntUUID,
+		ResponseTime50thPercentile: metric.ThisTimePeriod.ResponseTime50thPercentile,
+		ResponseTime95thPercentile: metric.ThisTimePeriod.ResponseTime95thPercentile,
+		ResponseTime99thPercentile: metric.ThisTimePeriod.ResponseTime99thPercentile,
+		TotalTestRuns:              metric.ThisTimePeriod.TotalTestRuns,
+	}
+
+	intervals := 0
+	for _, responseTime := range metric.ResponseTimes {
+		if !responseTime.HasRuns() {
+			continue
+		}
+
+		intervals++
+		summary.AverageResponseTimeMs += responseTime.AverageResponseTimeMs
+		summary.SuccessRatio += responseTime.SuccessRatio
+	}
+
+	if intervals > 0 {
+		summary.AverageResponseTimeMs /= float64(intervals)
+		summary.SuccessRatio /= float64(intervals)
+	}
+
+	return summary
+}
+
 func (test *Test) String() string {
 	value, err := json.Marshal(test)
 	if err != nil {
@@ -239,14 +490,64 @@ func (test *Test) String() string {
 	return string(value)
 }
 
-func getTestFromResponse(response interface{}) (*Test, error) {
+func getTestFromResponse(response interface{}, raw json.RawMessage) (*Test, error) {
 	test := new(Test)
-	err := decode(test, response)
-	return test, err
+	if err := decode(test, response); err != nil {
+		return test, err
+	}
+
+	if raw != nil {
+		return test, decodeTestSteps(test, raw)
+	}
+
+	if fields, ok := response.(map[string]interface{}); ok {
+		captureUnknownStepFields(test.Steps, fields["steps"])
+	}
+	return test, nil
 }
 
-func getTestsFromResponse(response interface{}) ([]*Test, error) {
+func getTestsFromResponse(response interface{}, raw json.RawMessage) ([]*Test, error) {
 	var tests []*Test
-	err := decode(&tests, response)
-	return tests, err
+	if err := decode(&tests, response); err != nil {
+		return tests, err
+	}
+
+	if raw != nil {
+		var items []json.RawMessage
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return tests, err
+		}
+
+		for i, item := range items {
+			if i < len(tests) {
+				if err := decodeTestSteps(tests[i], item); err != nil {
+					return tests, err
+				}
+			}
+		}
+		return tests, nil
+	}
+
+	if items, ok := response.([]interface{}); ok {
+		for i, item := range items {
+			if fields, ok := item.(map[string]interface{}); ok && i < len(tests) {
+				captureUnknownStepFields(tests[i].Steps, fields["steps"])
+			}
+		}
+	}
+	return tests, nil
+}
+
+// decodeTestSteps decodes the steps of a test from its raw json, the decoded maps the rest of the test is read from
+// lose the order of the headers and form parameters of its steps
+func decodeTestSteps(test *Test, raw json.RawMessage) error {
+	fields := struct {
+		Steps []*TestStep `json:"steps"`
+	}{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return err
+	}
+
+	test.Steps = fields.Steps
+	return nil
 }
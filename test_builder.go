@@ -0,0 +1,287 @@
+package runscope
+
+import (
+	"fmt"
+)
+
+// TestBuilder builds a test along with its steps, environments and schedules fluently, e.g.
+//
+//	NewTestBuilder().
+//		Name("health").
+//		Step(GET("https://api/{{env}}/health").AssertStatus(200)).
+//		Schedule(Every5Minutes, env)
+type TestBuilder struct {
+	name         string
+	description  string
+	bucket       *Bucket
+	steps        []Step
+	environments []*Environment
+	schedules    []*Schedule
+}
+
+// RequestBuilder builds a request step fluently, start one with GET, POST, PUT, PATCH, DELETE or Request
+type RequestBuilder struct {
+	step *RequestStep
+}
+
+// NewTestBuilder starts building a test
+func NewTestBuilder() *TestBuilder {
+	return &TestBuilder{}
+}
+
+// Name sets the name of the test
+func (builder *TestBuilder) Name(name string) *TestBuilder {
+	builder.name = name
+	return builder
+}
+
+// Description sets the description of the test
+func (builder *TestBuilder) Description(description string) *TestBuilder {
+	builder.description = description
+	return builder
+}
+
+// Bucket sets the bucket the test is created in
+func (builder *TestBuilder) Bucket(bucket *Bucket) *TestBuilder {
+	builder.bucket = bucket
+	return builder
+}
+
+// Step appends a step to the test, any typed step or a RequestBuilder can be passed
+func (builder *TestBuilder) Step(step Step) *TestBuilder {
+	builder.steps = append(builder.steps, step)
+	return builder
+}
+
+// Environment adds a test environment that is created along with the test, the first one added becomes the default
+// environment of the test
+func (builder *TestBuilder) Environment(environment *Environment) *TestBuilder {
+	builder.environments = append(builder.environments, environment)
+	return builder
+}
+
+// Schedule runs the test in environment at interval. The environment is either one added with Environment or an
+// existing environment, e.g. a shared environment of the bucket
+func (builder *TestBuilder) Schedule(interval ScheduleInterval, environment *Environment) *TestBuilder {
+	schedule := &Schedule{Interval: interval, environment: environment}
+	if environment != nil {
+		schedule.EnvironmentID = environment.ID
+	}
+
+	builder.schedules = append(builder.schedules, schedule)
+	return builder
+}
+
+// Build validates the test and returns it ready to be passed to CreateTestDetail
+func (builder *TestBuilder) Build() (*TestDetail, error) {
+	if builder.name == "" {
+		return nil, fmt.Errorf("A test must specify 'Name' property")
+	}
+
+	test := NewTest()
+	test.Name = builder.name
+	test.Description = builder.description
+	test.Bucket = builder.bucket
+	test.Environments = builder.environments
+
+	for _, step := range builder.steps {
+		testStep := step.TestStep()
+		if err := testStep.validate(); err != nil {
+			return nil, err
+		}
+
+		test.Steps = append(test.Steps, testStep)
+	}
+
+	for _, schedule := range builder.schedules {
+		if err := schedule.Interval.Validate(); err != nil {
+			return nil, err
+		}
+
+		if schedule.environment == nil {
+			return nil, fmt.Errorf("A schedule must specify an environment")
+		}
+
+		if schedule.EnvironmentID == "" && !builder.hasEnvironment(schedule.environment) {
+			return nil, fmt.Errorf("Error building test: %s, schedule environment %s is neither added to the test "+
+				"nor an existing environment", builder.name, schedule.environment.Name)
+		}
+	}
+
+	return &TestDetail{
+		Test:         test,
+		Steps:        test.Steps,
+		Schedules:    builder.schedules,
+		Environments: builder.environments,
+	}, nil
+}
+
+func (builder *TestBuilder) hasEnvironment(environment *Environment) bool {
+	for _, added := range builder.environments {
+		if added == environment {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CreateTestDetail creates a test together with its steps, environments and schedules, e.g. one built with
+// TestBuilder. Environments without an ID are created as test environments, the first of them becomes the default
+// environment. If any part fails the partially created test is deleted
+func (client *Client) CreateTestDetail(detail *TestDetail) (*Test, error) {
+	newTest, err := client.CreateTest(&Test{
+		Name:        detail.Test.Name,
+		Description: detail.Test.Description,
+		Bucket:      detail.Test.Bucket,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = client.createTestDetailContents(detail, newTest); err != nil {
+		client.DeleteTest(newTest)
+		return nil, err
+	}
+
+	return client.ReadTest(newTest)
+}
+
+func (client *Client) createTestDetailContents(detail *TestDetail, dst *Test) error {
+	environmentIDs := map[*Environment]string{}
+	defaultEnvironmentID := ""
+	for _, environment := range detail.Environments {
+		if environment.ID != "" {
+			environmentIDs[environment] = environment.ID
+			continue
+		}
+
+		newEnvironment, err := client.CreateTestEnvironment(copyEnvironment(environment), dst)
+		if err != nil {
+			return err
+		}
+
+		environmentIDs[environment] = newEnvironment.ID
+		if defaultEnvironmentID == "" {
+			defaultEnvironmentID = newEnvironment.ID
+		}
+	}
+
+	for _, step := range detail.Steps {
+		if _, err := client.CreateTestStep(copyTestStep(step), dst.Bucket.Key, dst.ID); err != nil {
+			return err
+		}
+	}
+
+	for _, schedule := range detail.Schedules {
+		environmentID := schedule.EnvironmentID
+		if id, ok := environmentIDs[schedule.environment]; ok {
+			environmentID = id
+		}
+
+		newSchedule := &Schedule{EnvironmentID: environmentID, Interval: schedule.Interval, Note: schedule.Note}
+		if _, err := client.CreateSchedule(newSchedule, dst.Bucket.Key, dst.ID); err != nil {
+			return err
+		}
+	}
+
+	if defaultEnvironmentID != "" {
+		// the test returned by CreateTest has no steps yet, updating it would remove the created steps
+		current, err := client.ReadTest(dst)
+		if err != nil {
+			return err
+		}
+
+		current.DefaultEnvironmentID = defaultEnvironmentID
+		if _, err := client.UpdateTest(current); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Request starts building a request step with method to url
+func Request(method string, url string) *RequestBuilder {
+	return &RequestBuilder{step: &RequestStep{Method: method, URL: url}}
+}
+
+// GET starts building a GET request step
+func GET(url string) *RequestBuilder { return Request("GET", url) }
+
+// POST starts building a POST request step
+func POST(url string) *RequestBuilder { return Request("POST", url) }
+
+// PUT starts building a PUT request step
+func PUT(url string) *RequestBuilder { return Request("PUT", url) }
+
+// PATCH starts building a PATCH request step
+func PATCH(url string) *RequestBuilder { return Request("PATCH", url) }
+
+// DELETE starts building a DELETE request step
+func DELETE(url string) *RequestBuilder { return Request("DELETE", url) }
+
+// Note sets the note shown for the step
+func (builder *RequestBuilder) Note(note string) *RequestBuilder {
+	builder.step.Note = note
+	return builder
+}
+
+// Header adds a request header
+func (builder *RequestBuilder) Header(name string, value string) *RequestBuilder {
+	builder.step.Headers.Add(name, value)
+	return builder
+}
+
+// FormField adds a form parameter, sent url encoded in the order added
+func (builder *RequestBuilder) FormField(name string, value string) *RequestBuilder {
+	builder.step.Form.Add(name, value)
+	return builder
+}
+
+// Body sets the request body
+func (builder *RequestBuilder) Body(body string) *RequestBuilder {
+	builder.step.Body = body
+	return builder
+}
+
+// Auth authenticates the request, see BasicAuth and OAuth1Auth
+func (builder *RequestBuilder) Auth(auth StepAuth) *RequestBuilder {
+	builder.step.Auth = auth.authMap()
+	return builder
+}
+
+// Assert adds an assertion, see AssertionBuilder
+func (builder *RequestBuilder) Assert(assertion *Assertion) *RequestBuilder {
+	builder.step.Assertions = append(builder.step.Assertions, assertion)
+	return builder
+}
+
+// AssertStatus asserts the response has status code
+func (builder *RequestBuilder) AssertStatus(code int) *RequestBuilder {
+	return builder.Assert(AssertStatus().EqualsNumber(code))
+}
+
+// Extract adds a variable extracted from the response, see Extract
+func (builder *RequestBuilder) Extract(variable *Variable) *RequestBuilder {
+	builder.step.Variables = append(builder.step.Variables, variable)
+	return builder
+}
+
+// Script adds a script run after the request
+func (builder *RequestBuilder) Script(script string) *RequestBuilder {
+	builder.step.Scripts = append(builder.step.Scripts, script)
+	return builder
+}
+
+// BeforeScript adds a script run before the request
+func (builder *RequestBuilder) BeforeScript(script string) *RequestBuilder {
+	builder.step.BeforeScripts = append(builder.step.BeforeScripts, script)
+	return builder
+}
+
+// Type returns StepTypeRequest
+func (builder *RequestBuilder) Type() string { return StepTypeRequest }
+
+// TestStep converts the built request step into its wire representation
+func (builder *RequestBuilder) TestStep() *TestStep { return builder.step.TestStep() }
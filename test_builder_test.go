@@ -0,0 +1,138 @@
+package runscope
+
+import (
+	"testing"
+)
+
+func TestTestBuilder(t *testing.T) {
+	environment := &Environment{Name: "staging", InitialVariables: map[string]string{"env": "staging"}}
+	shared := &Environment{ID: "1eeb3695-5d0f-467c-9d51-8b773dce29ba", Name: "shared"}
+
+	detail, err := NewTestBuilder().
+		Name("health").
+		Description("Checks the health endpoint").
+		Environment(environment).
+		Step(GET("https://api/{{env}}/health").
+			Header("Accept", "application/json").
+			AssertStatus(200).
+			Assert(AssertJSON("status").Equals("ok")).
+			Extract(Extract("version").FromJSON("version"))).
+		Step(&PauseStep{Duration: 5}).
+		Step(POST("https://api/{{env}}/echo").Body(`{"version": "{{version}}"}`).AssertStatus(201)).
+		Schedule(Every5Minutes, environment).
+		Schedule(EveryHour, shared).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if detail.Test.Name != "health" || detail.Test.Description != "Checks the health endpoint" {
+		t.Errorf("Expected name and description, actual %s %s", detail.Test.Name, detail.Test.Description)
+	}
+
+	if len(detail.Steps) != 3 {
+		t.Fatalf("Expected %d steps, actual %d", 3, len(detail.Steps))
+	}
+
+	request := detail.Steps[0]
+	if request.StepType != StepTypeRequest || request.Method != "GET" || request.URL != "https://api/{{env}}/health" {
+		t.Errorf("Expected GET request step, actual %#v", request)
+	}
+
+	if len(request.Assertions) != 2 || request.Assertions[0].Value != 200 || len(request.Variables) != 1 {
+		t.Errorf("Expected 2 assertions and 1 variable, actual %v %v", request.Assertions, request.Variables)
+	}
+
+	if detail.Steps[1].StepType != StepTypePause || detail.Steps[2].Method != "POST" {
+		t.Errorf("Expected pause and POST steps, actual %s %s", detail.Steps[1].StepType, detail.Steps[2].Method)
+	}
+
+	if len(detail.Schedules) != 2 || detail.Schedules[0].environment != environment ||
+		detail.Schedules[1].EnvironmentID != shared.ID {
+		t.Errorf("Expected schedules in staging and shared environments, actual %v", detail.Schedules)
+	}
+}
+
+func TestTestBuilderInvalid(t *testing.T) {
+	if _, err := NewTestBuilder().Build(); err == nil {
+		t.Error("Expected error for missing name")
+	}
+
+	if _, err := NewTestBuilder().Name("health").Step(Request("", "https://api")).Build(); err == nil {
+		t.Error("Expected error for request step without method")
+	}
+
+	if _, err := NewTestBuilder().Name("health").Schedule("2m", &Environment{ID: "env"}).Build(); err == nil {
+		t.Error("Expected error for invalid interval")
+	}
+
+	if _, err := NewTestBuilder().Name("health").Schedule(Every5Minutes, &Environment{Name: "missing"}).Build(); err == nil {
+		t.Error("Expected error for schedule environment not added to the test")
+	}
+}
+
+func TestCreateTestDetail(t *testing.T) {
+	testPreCheck(t)
+	client := clientConfigure()
+	bucket, err := client.CreateBucket(&Bucket{Name: "test", Team: &Team{ID: teamID}})
+	defer client.DeleteBucket(bucket.Key)
+	if err != nil {
+		t.Error(err)
+	}
+
+	environment := &Environment{Name: "staging", InitialVariables: map[string]string{"env": "staging"}}
+	detail, err := NewTestBuilder().
+		Name("health").
+		Bucket(bucket).
+		Environment(environment).
+		Step(GET("https://example.com").AssertStatus(200)).
+		Schedule(Every5Minutes, environment).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	test, err := client.CreateTestDetail(detail)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.DeleteTest(test)
+
+	if len(test.Steps) != 1 {
+		t.Errorf("Expected %d steps, actual %d", 1, len(test.Steps))
+	}
+
+	schedules, err := client.ListSchedules(bucket.Key, test.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(schedules) != 1 || schedules[0].EnvironmentID != test.DefaultEnvironmentID {
+		t.Errorf("Expected one schedule in the default environment, actual %v", schedules)
+	}
+}
+
+func TestCreateTestDetailKeepsSteps(t *testing.T) {
+	server := newImportServer()
+	defer server.Close()
+
+	environment := &Environment{Name: "staging"}
+	detail, err := NewTestBuilder().
+		Name("health").
+		Bucket(&Bucket{Key: "z3n32gktzx94"}).
+		Environment(environment).
+		Step(GET("https://example.com").AssertStatus(200)).
+		Schedule(Every5Minutes, environment).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = NewClient(server.URL, "token").CreateTestDetail(detail); err != nil {
+		t.Fatal(err)
+	}
+
+	if server.updated == nil || server.updated.DefaultEnvironmentID != "env-1" || len(server.updated.Steps) != 1 {
+		t.Errorf("Expected the default environment to be set keeping the step, actual %v", server.updated)
+	}
+}
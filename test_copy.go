@@ -0,0 +1,225 @@
+package runscope
+
+import (
+	"fmt"
+)
+
+// CopyTest recreates a test, including its steps, test environments and schedules, in the destination bucket. The
+// copy gets fresh IDs, schedules and the default environment are remapped to the copied environments, or to the
+// shared environments of the destination bucket with the same name as the shared ones they used. Subtest steps keep
+// invoking the same tests. If any part of the copy fails the partially created test is deleted.
+func (client *Client) CopyTest(test *Test, dstBucket *Bucket) (*Test, error) {
+	schedules, err := client.ListSchedules(test.Bucket.Key, test.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.copyTest(test, dstBucket, "", schedules)
+}
+
+// DuplicateTest copies a test, including its steps and test environments, within its bucket under a new name.
+// Schedules are not copied so the duplicate does not run until it is scheduled.
+func (client *Client) DuplicateTest(test *Test, newName string) (*Test, error) {
+	return client.copyTest(test, test.Bucket, newName, nil)
+}
+
+func (client *Client) copyTest(test *Test, dstBucket *Bucket, name string, schedules []*Schedule) (*Test, error) {
+	source, err := client.ReadTest(test)
+	if err != nil {
+		return nil, err
+	}
+
+	environments, err := client.ListTestEnvironment(test.Bucket, test)
+	if err != nil {
+		return nil, err
+	}
+
+	if name == "" {
+		name = source.Name
+	}
+
+	newTest, err := client.CreateTest(&Test{Name: name, Description: source.Description, Bucket: dstBucket})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = client.copyTestContents(source, newTest, environments, schedules); err != nil {
+		client.DeleteTest(newTest)
+		return nil, err
+	}
+
+	return client.ReadTest(newTest)
+}
+
+func (client *Client) copyTestContents(
+	source *Test, dst *Test, environments []*Environment, schedules []*Schedule) error {
+
+	environmentIDs := map[string]string{}
+	for _, environment := range environments {
+		newEnvironment, err := client.CreateTestEnvironment(copyEnvironment(environment), dst)
+		if err != nil {
+			return err
+		}
+
+		environmentIDs[environment.ID] = newEnvironment.ID
+	}
+
+	for _, step := range source.Steps {
+		if _, err := client.CreateTestStep(copyTestStep(step), dst.Bucket.Key, dst.ID); err != nil {
+			return err
+		}
+	}
+
+	if err := client.mapSharedEnvironments(source, dst, schedules, environmentIDs); err != nil {
+		return err
+	}
+
+	for _, schedule := range schedules {
+		environmentID, ok := environmentIDs[schedule.EnvironmentID]
+		if !ok {
+			return fmt.Errorf("Error copying schedule: %s, environment %s is neither a copied test environment nor "+
+				"a shared environment of bucket %s", schedule.ID, schedule.EnvironmentID, dst.Bucket.Key)
+		}
+
+		newSchedule := &Schedule{EnvironmentID: environmentID, Interval: schedule.Interval, Note: schedule.Note}
+		if _, err := client.CreateSchedule(newSchedule, dst.Bucket.Key, dst.ID); err != nil {
+			return err
+		}
+	}
+
+	if environmentID, ok := environmentIDs[source.DefaultEnvironmentID]; ok {
+		// the test returned by CreateTest has no steps yet, updating it would remove the copied steps
+		current, err := client.ReadTest(dst)
+		if err != nil {
+			return err
+		}
+
+		current.DefaultEnvironmentID = environmentID
+		if _, err := client.UpdateTest(current); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// mapSharedEnvironments adds the shared environments of the destination bucket to environmentIDs, by the ID of the
+// shared environment of the source bucket with the same name, when the schedules or the default environment of the
+// source use shared environments. Names shared by several environments of the destination bucket are not mapped
+func (client *Client) mapSharedEnvironments(
+	source *Test, dst *Test, schedules []*Schedule, environmentIDs map[string]string) error {
+
+	used := map[string]bool{}
+	for _, schedule := range schedules {
+		if _, ok := environmentIDs[schedule.EnvironmentID]; !ok {
+			used[schedule.EnvironmentID] = true
+		}
+	}
+
+	if _, ok := environmentIDs[source.DefaultEnvironmentID]; !ok && source.DefaultEnvironmentID != "" {
+		used[source.DefaultEnvironmentID] = true
+	}
+
+	if len(used) == 0 || source.Bucket == nil {
+		return nil
+	}
+
+	if source.Bucket.Key == dst.Bucket.Key {
+		for id := range used {
+			environmentIDs[id] = id
+		}
+		return nil
+	}
+
+	sourceEnvironments, err := client.ListSharedEnvironment(source.Bucket)
+	if err != nil {
+		return err
+	}
+
+	dstEnvironments, err := client.ListSharedEnvironment(dst.Bucket)
+	if err != nil {
+		return err
+	}
+
+	byName := map[string]string{}
+	for _, environment := range dstEnvironments {
+		if _, ok := byName[environment.Name]; ok {
+			byName[environment.Name] = ""
+			continue
+		}
+		byName[environment.Name] = environment.ID
+	}
+
+	for _, environment := range sourceEnvironments {
+		if id := byName[environment.Name]; used[environment.ID] && id != "" {
+			environmentIDs[environment.ID] = id
+		}
+	}
+
+	return nil
+}
+
+func copyTestStep(step *TestStep) *TestStep {
+	newStep := *step
+	newStep.ID = ""
+	return &newStep
+}
+
+func copyEnvironment(environment *Environment) *Environment {
+	newEnvironment := *environment
+	newEnvironment.ID = ""
+	newEnvironment.TestID = ""
+	newEnvironment.ExportedAt = nil
+	return &newEnvironment
+}
+
+// MoveTest moves a test to the destination bucket by copying it, verifying the copy matches the original and then
+// deleting the original. If verification or the delete fails the copy is deleted and the original left in place. Run
+// history is not moved, it remains only with the deleted original.
+func (client *Client) MoveTest(test *Test, dstBucket *Bucket) (*Test, error) {
+	source, err := client.ReadTest(test)
+	if err != nil {
+		return nil, err
+	}
+
+	moved, err := client.CopyTest(test, dstBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = verifyTestCopy(source, moved); err != nil {
+		client.DeleteTest(moved)
+		return nil, err
+	}
+
+	if err = client.DeleteTest(test); err != nil {
+		if rollbackErr := client.DeleteTest(moved); rollbackErr != nil {
+			return nil, fmt.Errorf("Error moving test: %s, %s, rollback failed leaving copy %s: %s",
+				test.ID, err, moved.ID, rollbackErr)
+		}
+
+		return nil, err
+	}
+
+	return moved, nil
+}
+
+func verifyTestCopy(source *Test, copy *Test) error {
+	if source.Name != copy.Name {
+		return fmt.Errorf("Error verifying copy of test: %s, expected name %q got %q", source.ID, source.Name, copy.Name)
+	}
+
+	if len(source.Steps) != len(copy.Steps) {
+		return fmt.Errorf("Error verifying copy of test: %s, expected %d steps got %d",
+			source.ID, len(source.Steps), len(copy.Steps))
+	}
+
+	for i, step := range source.Steps {
+		copied := copy.Steps[i]
+		if step.StepType != copied.StepType || step.Method != copied.Method || step.URL != copied.URL {
+			return fmt.Errorf("Error verifying copy of test: %s, step %d does not match", source.ID, i)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,225 @@
+package runscope
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestCopyTest(t *testing.T) {
+	testPreCheck(t)
+	client := clientConfigure()
+	bucket, err := client.CreateBucket(&Bucket{Name: "test", Team: &Team{ID: teamID}})
+	defer client.DeleteBucket(bucket.Key)
+	if err != nil {
+		t.Error(err)
+	}
+
+	dstBucket, err := client.CreateBucket(&Bucket{Name: "test-copy", Team: &Team{ID: teamID}})
+	defer client.DeleteBucket(dstBucket.Key)
+	if err != nil {
+		t.Error(err)
+	}
+
+	test, err := client.CreateTest(&Test{Name: "tf_test", Description: "This is a tf test", Bucket: bucket})
+	if err != nil {
+		t.Error(err)
+	}
+
+	step := NewTestStep()
+	step.StepType = "request"
+	step.URL = "http://example.com"
+	step.Method = "GET"
+	if _, err = client.CreateTestStep(step, bucket.Key, test.ID); err != nil {
+		t.Error(err)
+	}
+
+	copied, err := client.CopyTest(test, dstBucket)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if copied.ID == test.ID {
+		t.Error("Expected copied test to have a new id")
+	}
+
+	if copied.Name != test.Name {
+		t.Errorf("Expected name %s, actual %s", test.Name, copied.Name)
+	}
+
+	if len(copied.Steps) != 1 {
+		t.Errorf("Expected %d steps, actual %d", 1, len(copied.Steps))
+	}
+}
+
+func TestDuplicateTest(t *testing.T) {
+	testPreCheck(t)
+	client := clientConfigure()
+	bucket, err := client.CreateBucket(&Bucket{Name: "test", Team: &Team{ID: teamID}})
+	defer client.DeleteBucket(bucket.Key)
+	if err != nil {
+		t.Error(err)
+	}
+
+	test, err := client.CreateTest(&Test{Name: "tf_test", Description: "This is a tf test", Bucket: bucket})
+	if err != nil {
+		t.Error(err)
+	}
+
+	duplicate, err := client.DuplicateTest(test, "tf_test copy")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if duplicate.ID == test.ID {
+		t.Error("Expected duplicate test to have a new id")
+	}
+
+	if duplicate.Name != "tf_test copy" {
+		t.Errorf("Expected name %s, actual %s", "tf_test copy", duplicate.Name)
+	}
+
+	if duplicate.Bucket.Key != bucket.Key {
+		t.Errorf("Expected bucket %s, actual %s", bucket.Key, duplicate.Bucket.Key)
+	}
+}
+
+func TestMoveTest(t *testing.T) {
+	testPreCheck(t)
+	client := clientConfigure()
+	bucket, err := client.CreateBucket(&Bucket{Name: "test", Team: &Team{ID: teamID}})
+	defer client.DeleteBucket(bucket.Key)
+	if err != nil {
+		t.Error(err)
+	}
+
+	dstBucket, err := client.CreateBucket(&Bucket{Name: "test-move", Team: &Team{ID: teamID}})
+	defer client.DeleteBucket(dstBucket.Key)
+	if err != nil {
+		t.Error(err)
+	}
+
+	test, err := client.CreateTest(&Test{Name: "tf_test", Description: "This is a tf test", Bucket: bucket})
+	if err != nil {
+		t.Error(err)
+	}
+
+	moved, err := client.MoveTest(test, dstBucket)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if moved.Bucket.Key != dstBucket.Key {
+		t.Errorf("Expected bucket %s, actual %s", dstBucket.Key, moved.Bucket.Key)
+	}
+
+	if _, err = client.ReadTest(test); err == nil {
+		t.Error("Expected original test to be deleted")
+	}
+}
+
+func TestVerifyTestCopy(t *testing.T) {
+	source := &Test{Name: "a", Steps: []*TestStep{{StepType: "request", Method: "GET", URL: "http://example.com"}}}
+	copy := &Test{Name: "a", Steps: []*TestStep{{StepType: "request", Method: "GET", URL: "http://example.com"}}}
+	if err := verifyTestCopy(source, copy); err != nil {
+		t.Error(err)
+	}
+
+	copy.Steps[0].Method = "POST"
+	if err := verifyTestCopy(source, copy); err == nil {
+		t.Error("Expected error for mismatched step")
+	}
+
+	copy.Steps = nil
+	if err := verifyTestCopy(source, copy); err == nil {
+		t.Error("Expected error for missing steps")
+	}
+}
+
+func TestCopyTestStepClearsIDs(t *testing.T) {
+	step := &TestStep{ID: "step", TestUUID: "test", URL: "http://example.com"}
+	newStep := copyTestStep(step)
+
+	if newStep.ID != "" {
+		t.Errorf("Expected id to be cleared, actual %s", newStep.ID)
+	}
+
+	if newStep.TestUUID != "test" {
+		t.Errorf("Expected subtest target %s to be kept, actual %s", "test", newStep.TestUUID)
+	}
+
+	if newStep.URL != step.URL {
+		t.Errorf("Expected url %s, actual %s", step.URL, newStep.URL)
+	}
+
+	if step.ID != "step" {
+		t.Error("Expected original step to be unchanged")
+	}
+}
+
+func TestCopyTestSubtestAndSharedEnvironment(t *testing.T) {
+	var mu sync.Mutex
+	var steps []*TestStep
+	var schedules []*Schedule
+	var updated *Test
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		body, _ := ioutil.ReadAll(r.Body)
+		switch r.Method + " " + r.URL.Path {
+		case "GET /buckets/z3n32gktzx94/tests/1":
+			fmt.Fprint(w, `{"data": {"id": "1", "name": "Checkout", "default_environment_id": "10", "steps": [
+				{"id": "s1", "step_type": "subtest", "test_uuid": "2", "bucket_key": "z3n32gktzx94"}]}}`)
+		case "GET /buckets/z3n32gktzx94/tests/1/schedules":
+			fmt.Fprint(w, `{"data": [{"id": "20", "environment_id": "10", "interval": "5m"}]}`)
+		case "GET /buckets/z3n32gktzx94/environments":
+			fmt.Fprint(w, `{"data": [{"id": "10", "name": "production"}]}`)
+		case "GET /buckets/y8ny9bc8fhbk/environments":
+			fmt.Fprint(w, `{"data": [{"id": "11", "name": "staging"}, {"id": "12", "name": "production"}]}`)
+		case "POST /buckets/y8ny9bc8fhbk/tests":
+			fmt.Fprint(w, `{"data": {"id": "3", "name": "Checkout", "steps": []}}`)
+		case "POST /buckets/y8ny9bc8fhbk/tests/3/steps":
+			step := &TestStep{}
+			json.Unmarshal(body, step)
+			steps = append(steps, step)
+			fmt.Fprint(w, `{"data": [{"id": "s2"}]}`)
+		case "POST /buckets/y8ny9bc8fhbk/tests/3/schedules":
+			schedule := &Schedule{}
+			json.Unmarshal(body, schedule)
+			schedules = append(schedules, schedule)
+			fmt.Fprint(w, `{"data": {"id": "21"}}`)
+		case "GET /buckets/y8ny9bc8fhbk/tests/3":
+			data, _ := json.Marshal(map[string]interface{}{"id": "3", "name": "Checkout", "steps": steps})
+			fmt.Fprintf(w, `{"data": %s}`, data)
+		case "PUT /buckets/y8ny9bc8fhbk/tests/3":
+			updated = &Test{}
+			json.Unmarshal(body, updated)
+			fmt.Fprintf(w, `{"data": %s}`, body)
+		default:
+			fmt.Fprint(w, `{"data": []}`)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "token")
+	source := &Test{ID: "1", Bucket: &Bucket{Key: "z3n32gktzx94"}}
+	if _, err := client.CopyTest(source, &Bucket{Key: "y8ny9bc8fhbk"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(steps) != 1 || steps[0].TestUUID != "2" || steps[0].BucketKey != "z3n32gktzx94" {
+		t.Errorf("Expected the subtest step to invoke test 2 of bucket z3n32gktzx94, actual %v", steps)
+	}
+
+	if len(schedules) != 1 || schedules[0].EnvironmentID != "12" {
+		t.Errorf("Expected a schedule in shared environment 12, actual %v", schedules)
+	}
+
+	if updated == nil || updated.DefaultEnvironmentID != "12" || len(updated.Steps) != 1 {
+		t.Errorf("Expected the update to set default environment 12 and keep the copied step, actual %v", updated)
+	}
+}
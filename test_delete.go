@@ -0,0 +1,112 @@
+package runscope
+
+import (
+	"fmt"
+	"path"
+	"sync"
+	"time"
+)
+
+// DeleteTestsFilter selects the tests DeleteTests removes, a test must match every criterion that is set
+type DeleteTestsFilter struct {
+	// Name matches the test name as a shell pattern, see path.Match
+	Name string
+	// CreatedBefore matches tests created before this time
+	CreatedBefore time.Time
+}
+
+// DeleteTestsOptions controls how DeleteTests deletes the matching tests
+type DeleteTestsOptions struct {
+	// DryRun only lists the matching tests without deleting them
+	DryRun bool
+	// Concurrency is the number of tests deleted at the same time, defaults to 1
+	Concurrency int
+}
+
+// DeleteTestsSummary reports the tests matched by DeleteTests and the outcome of deleting each of them
+type DeleteTestsSummary struct {
+	Matched []*Test
+	Deleted []*Test
+	Failed  map[string]error
+}
+
+// DeleteTests deletes the tests in a bucket matching filter, e.g. tests generated for branches that no longer
+// exist. With DryRun set the returned summary only lists the tests that would be deleted. An error is returned when
+// any deletion fails, the summary then reports which tests were deleted and which failed
+func (client *Client) DeleteTests(bucket *Bucket, filter *DeleteTestsFilter, opts *DeleteTestsOptions) (*DeleteTestsSummary, error) {
+	if filter == nil || (filter.Name == "" && filter.CreatedBefore.IsZero()) {
+		return nil, fmt.Errorf("Error deleting tests: filter must set a name pattern or a creation time")
+	}
+
+	if opts == nil {
+		opts = &DeleteTestsOptions{}
+	}
+
+	tests, err := client.ListAllTests(&ListTestsInput{BucketKey: bucket.Key})
+	if err != nil {
+		return nil, err
+	}
+
+	matched, err := filterTestsForDeletion(tests, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &DeleteTestsSummary{Matched: matched, Failed: map[string]error{}}
+	if opts.DryRun {
+		return summary, nil
+	}
+
+	var mu sync.Mutex
+	pool := client.NewPool(&PoolOptions{Concurrency: opts.Concurrency})
+	for _, test := range matched {
+		test := test
+		test.Bucket = bucket
+		pool.Submit(func() error {
+			err := client.DeleteTest(test)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				summary.Failed[test.ID] = err
+				return err
+			}
+
+			// an earlier attempt may have been rate limited
+			delete(summary.Failed, test.ID)
+			summary.Deleted = append(summary.Deleted, test)
+			return nil
+		})
+	}
+
+	pool.Wait()
+	if len(summary.Failed) > 0 {
+		return summary, fmt.Errorf("Error deleting tests: %d of %d deletions failed", len(summary.Failed), len(matched))
+	}
+
+	return summary, nil
+}
+
+func filterTestsForDeletion(tests []*Test, filter *DeleteTestsFilter) ([]*Test, error) {
+	var matched []*Test
+	for _, test := range tests {
+		if filter.Name != "" {
+			ok, err := path.Match(filter.Name, test.Name)
+			if err != nil {
+				return nil, fmt.Errorf("Error deleting tests: %s, invalid pattern: %s", filter.Name, err)
+			}
+
+			if !ok {
+				continue
+			}
+		}
+
+		if !filter.CreatedBefore.IsZero() && (test.CreatedAt == nil || !test.CreatedAt.Before(filter.CreatedBefore)) {
+			continue
+		}
+
+		matched = append(matched, test)
+	}
+
+	return matched, nil
+}
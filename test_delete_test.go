@@ -0,0 +1,82 @@
+package runscope
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDeleteTests(t *testing.T) {
+	testPreCheck(t)
+	client := clientConfigure()
+	bucket, err := client.CreateBucket(&Bucket{Name: "test", Team: &Team{ID: teamID}})
+	defer client.DeleteBucket(bucket.Key)
+	if err != nil {
+		t.Error(err)
+	}
+
+	for _, name := range []string{"branch-a", "branch-b", "main"} {
+		if _, err = client.CreateTest(&Test{Name: name, Bucket: bucket}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	summary, err := client.DeleteTests(bucket, &DeleteTestsFilter{Name: "branch-*"}, &DeleteTestsOptions{DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(summary.Matched) != 2 || len(summary.Deleted) != 0 {
+		t.Errorf("Expected dry run to match 2 and delete 0 tests, actual %d %d", len(summary.Matched), len(summary.Deleted))
+	}
+
+	summary, err = client.DeleteTests(bucket, &DeleteTestsFilter{Name: "branch-*"}, &DeleteTestsOptions{Concurrency: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(summary.Deleted) != 2 {
+		t.Errorf("Expected 2 deleted tests, actual %d", len(summary.Deleted))
+	}
+
+	tests, err := client.ListAllTests(&ListTestsInput{BucketKey: bucket.Key})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(tests) != 1 || tests[0].Name != "main" {
+		t.Errorf("Expected only test main to remain, actual %v", tests)
+	}
+}
+
+func TestFilterTestsForDeletion(t *testing.T) {
+	old := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	recent := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	tests := []*Test{
+		{Name: "branch-a", CreatedAt: &old},
+		{Name: "branch-b", CreatedAt: &recent},
+		{Name: "main", CreatedAt: &old},
+		{Name: "branch-c"},
+	}
+
+	matched, err := filterTestsForDeletion(tests, &DeleteTestsFilter{Name: "branch-*"})
+	if err != nil || len(matched) != 3 {
+		t.Errorf("Expected 3 tests matching name, actual %d %v", len(matched), err)
+	}
+
+	cutoff := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	matched, err = filterTestsForDeletion(tests, &DeleteTestsFilter{Name: "branch-*", CreatedBefore: cutoff})
+	if err != nil || len(matched) != 1 || matched[0].Name != "branch-a" {
+		t.Errorf("Expected only branch-a, actual %v %v", matched, err)
+	}
+
+	if _, err = filterTestsForDeletion(tests, &DeleteTestsFilter{Name: "["}); err == nil {
+		t.Error("Expected error for invalid pattern")
+	}
+}
+
+func TestDeleteTestsRequiresFilter(t *testing.T) {
+	client := &Client{}
+	if _, err := client.DeleteTests(&Bucket{}, &DeleteTestsFilter{}, nil); err == nil {
+		t.Error("Expected error for empty filter")
+	}
+}
@@ -0,0 +1,337 @@
+package runscope
+
+import (
+	"fmt"
+)
+
+// Clone returns a deep copy of the test, its steps, environments and last run. The bucket is shared with the
+// original as it is a reference to where the test lives rather than part of the test
+func (test *Test) Clone() *Test {
+	if test == nil {
+		return nil
+	}
+
+	clone := *test
+	if test.CreatedAt != nil {
+		createdAt := *test.CreatedAt
+		clone.CreatedAt = &createdAt
+	}
+
+	if test.ExportedAt != nil {
+		exportedAt := *test.ExportedAt
+		clone.ExportedAt = &exportedAt
+	}
+
+	if test.CreatedBy != nil {
+		createdBy := *test.CreatedBy
+		clone.CreatedBy = &createdBy
+	}
+
+	if test.LastRun != nil {
+		lastRun := *test.LastRun
+		lastRun.Messages = cloneStrings(test.LastRun.Messages)
+		lastRun.TemplateUUIDs = cloneStrings(test.LastRun.TemplateUUIDs)
+		clone.LastRun = &lastRun
+	}
+
+	clone.Steps = cloneTestSteps(test.Steps)
+
+	if test.Environments != nil {
+		clone.Environments = make([]*Environment, len(test.Environments))
+		for i, environment := range test.Environments {
+			clone.Environments[i] = environment.clone()
+		}
+	}
+
+	return &clone
+}
+
+// Clone returns a deep copy of the step including its nested steps, assertions and variables
+func (step *TestStep) Clone() *TestStep {
+	if step == nil {
+		return nil
+	}
+
+	clone := *step
+	clone.Headers = step.Headers.clone()
+	clone.Form = step.Form.clone()
+	clone.Auth = cloneStringMap(step.Auth)
+	clone.Scripts = cloneStrings(step.Scripts)
+	clone.BeforeScripts = cloneStrings(step.BeforeScripts)
+	clone.Steps = cloneTestSteps(step.Steps)
+
+	if step.Args != nil {
+		clone.Args = cloneValue(step.Args).(map[string]interface{})
+	}
+
+	if step.Extra != nil {
+		clone.Extra = cloneValue(step.Extra).(map[string]interface{})
+	}
+
+	if step.Assertions != nil {
+		clone.Assertions = make([]*Assertion, len(step.Assertions))
+		for i, assertion := range step.Assertions {
+			copied := *assertion
+			copied.Value = cloneValue(assertion.Value)
+			clone.Assertions[i] = &copied
+		}
+	}
+
+	if step.Variables != nil {
+		clone.Variables = make([]*Variable, len(step.Variables))
+		for i, variable := range step.Variables {
+			copied := *variable
+			clone.Variables[i] = &copied
+		}
+	}
+
+	return &clone
+}
+
+// Equal reports whether two tests have the same definition: name, description and steps. Fields assigned by the
+// server such as IDs, timestamps, the trigger url and the last run are ignored, as are environments and schedules
+// which are not changed by UpdateTest
+func (test *Test) Equal(other *Test) bool {
+	if test == nil || other == nil {
+		return test == other
+	}
+
+	return test.Name == other.Name &&
+		test.Description == other.Description &&
+		testStepsEqual(test.Steps, other.Steps)
+}
+
+// Equal reports whether two steps have the same definition, ignoring the IDs assigned by the server and fields in
+// Extra. Numeric assertion values are equal regardless of their type, so 200 equals the 200.0 decoded from a response,
+// and headers and form parameters are equal regardless of their order, which doesn't change the request sent
+func (step *TestStep) Equal(other *TestStep) bool {
+	if step == nil || other == nil {
+		return step == other
+	}
+
+	if step.StepType != other.StepType ||
+		step.Method != other.Method ||
+		step.URL != other.URL ||
+		step.Body != other.Body ||
+		step.Note != other.Note ||
+		step.EnvironmentID != other.EnvironmentID ||
+		step.Duration != other.Duration ||
+		step.LeftValue != other.LeftValue ||
+		step.Comparison != other.Comparison ||
+		step.RightValue != other.RightValue ||
+		step.TestUUID != other.TestUUID ||
+		step.BucketKey != other.BucketKey ||
+		step.GhostTestID != other.GhostTestID {
+		return false
+	}
+
+	if !step.Headers.equal(other.Headers) ||
+		!step.Form.equal(other.Form) ||
+		!stringMapsEqual(step.Auth, other.Auth) ||
+		!stringsEqual(step.Scripts, other.Scripts) ||
+		!stringsEqual(step.BeforeScripts, other.BeforeScripts) ||
+		fmt.Sprint(step.Args) != fmt.Sprint(other.Args) {
+		return false
+	}
+
+	if len(step.Assertions) != len(other.Assertions) || len(step.Variables) != len(other.Variables) {
+		return false
+	}
+
+	for i, assertion := range step.Assertions {
+		otherAssertion := other.Assertions[i]
+		if assertion.Source != otherAssertion.Source ||
+			assertion.Property != otherAssertion.Property ||
+			assertion.Comparison != otherAssertion.Comparison ||
+			fmt.Sprint(assertion.Value) != fmt.Sprint(otherAssertion.Value) {
+			return false
+		}
+	}
+
+	for i, variable := range step.Variables {
+		if *variable != *other.Variables[i] {
+			return false
+		}
+	}
+
+	return testStepsEqual(step.Steps, other.Steps)
+}
+
+func (environment *Environment) clone() *Environment {
+	clone := *environment
+	clone.InitialVariables = cloneStringMap(environment.InitialVariables)
+	clone.Regions = cloneStrings(environment.Regions)
+	clone.WebHooks = cloneStrings(environment.WebHooks)
+	clone.Headers = cloneHeaders(environment.Headers)
+
+	if environment.ExportedAt != nil {
+		exportedAt := *environment.ExportedAt
+		clone.ExportedAt = &exportedAt
+	}
+
+	if environment.EmailSettings != nil {
+		emailSettings := *environment.EmailSettings
+		if emailSettings.Recipients != nil {
+			emailSettings.Recipients = make([]*Contact, len(environment.EmailSettings.Recipients))
+			for i, recipient := range environment.EmailSettings.Recipients {
+				copied := *recipient
+				emailSettings.Recipients[i] = &copied
+			}
+		}
+		clone.EmailSettings = &emailSettings
+	}
+
+	if environment.Integrations != nil {
+		clone.Integrations = make([]*EnvironmentIntegration, len(environment.Integrations))
+		for i, integration := range environment.Integrations {
+			copied := *integration
+			clone.Integrations[i] = &copied
+		}
+	}
+
+	if environment.RemoteAgents != nil {
+		clone.RemoteAgents = make([]*LocalMachine, len(environment.RemoteAgents))
+		for i, agent := range environment.RemoteAgents {
+			copied := *agent
+			clone.RemoteAgents[i] = &copied
+		}
+	}
+
+	return &clone
+}
+
+func cloneTestSteps(steps []*TestStep) []*TestStep {
+	if steps == nil {
+		return nil
+	}
+
+	clone := make([]*TestStep, len(steps))
+	for i, step := range steps {
+		clone[i] = step.Clone()
+	}
+
+	return clone
+}
+
+func cloneStrings(values []string) []string {
+	if values == nil {
+		return nil
+	}
+
+	return append([]string{}, values...)
+}
+
+func cloneStringMap(values map[string]string) map[string]string {
+	if values == nil {
+		return nil
+	}
+
+	clone := make(map[string]string, len(values))
+	for name, value := range values {
+		clone[name] = value
+	}
+
+	return clone
+}
+
+func cloneHeaders(headers map[string][]string) map[string][]string {
+	if headers == nil {
+		return nil
+	}
+
+	clone := make(map[string][]string, len(headers))
+	for name, values := range headers {
+		clone[name] = cloneStrings(values)
+	}
+
+	return clone
+}
+
+// cloneValue deep copies the maps and slices json values are decoded into
+func cloneValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		clone := make(map[string]interface{}, len(value))
+		for name, nested := range value {
+			clone[name] = cloneValue(nested)
+		}
+		return clone
+	case []interface{}:
+		clone := make([]interface{}, len(value))
+		for i, nested := range value {
+			clone[i] = cloneValue(nested)
+		}
+		return clone
+	default:
+		return value
+	}
+}
+
+func testStepsEqual(steps []*TestStep, other []*TestStep) bool {
+	if len(steps) != len(other) {
+		return false
+	}
+
+	for i, step := range steps {
+		if !step.Equal(other[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func stringsEqual(values []string, other []string) bool {
+	if len(values) != len(other) {
+		return false
+	}
+
+	for i, value := range values {
+		if value != other[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func stringMapsEqual(values map[string]string, other map[string]string) bool {
+	if len(values) != len(other) {
+		return false
+	}
+
+	for name, value := range values {
+		if otherValue, ok := other[name]; !ok || otherValue != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (parameters Parameters) clone() Parameters {
+	if parameters == nil {
+		return nil
+	}
+
+	clone := make(Parameters, len(parameters))
+	for i, parameter := range parameters {
+		clone[i] = &Parameter{Name: parameter.Name, Values: cloneStrings(parameter.Values)}
+	}
+
+	return clone
+}
+
+func (parameters Parameters) equal(other Parameters) bool {
+	if len(parameters) != len(other) {
+		return false
+	}
+
+	for _, parameter := range parameters {
+		if !other.Has(parameter.Name) || !stringsEqual(parameter.Values, other.Get(parameter.Name)) {
+			return false
+		}
+	}
+
+	return true
+}
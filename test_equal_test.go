@@ -0,0 +1,115 @@
+package runscope
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func equalTestFixture() *Test {
+	createdAt := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	return &Test{
+		ID:          "8e7afae4-23b6-492a-b4b9-75d515b5082b",
+		Name:        "Smoke test",
+		Description: "Checks the api is up",
+		CreatedAt:   &createdAt,
+		Steps: []*TestStep{
+			{
+				ID:         "52e7c0b2-6f3a-4b77-a3e3-0ab3ad7c5b8a",
+				StepType:   StepTypeRequest,
+				Method:     "GET",
+				URL:        "https://example.com",
+				Headers:    Parameters{{Name: "Accept", Values: []string{"application/json"}}},
+				Assertions: []*Assertion{AssertStatus().EqualsNumber(200)},
+				Variables:  []*Variable{Extract("id").FromJSON("id")},
+			},
+			{
+				StepType:   StepTypeCondition,
+				LeftValue:  "{{id}}",
+				Comparison: ComparisonNotEmpty,
+				Steps:      []*TestStep{{StepType: StepTypePause, Duration: 5}},
+			},
+		},
+		Environments: []*Environment{{Name: "default", InitialVariables: map[string]string{"baseUrl": "https://example.com"}}},
+	}
+}
+
+func TestTestClone(t *testing.T) {
+	test := equalTestFixture()
+	clone := test.Clone()
+
+	if !test.Equal(clone) {
+		t.Fatal("Expected clone to equal the original")
+	}
+
+	clone.Steps[0].Headers.Get("Accept")[0] = "text/plain"
+	clone.Steps[0].Assertions[0].Value = 404
+	clone.Steps[1].Steps[0].Duration = 10
+	clone.Environments[0].InitialVariables["baseUrl"] = "https://other.example.com"
+	*clone.CreatedAt = time.Now()
+
+	if test.Steps[0].Headers.Get("Accept")[0] != "application/json" ||
+		test.Steps[0].Assertions[0].Value != 200 ||
+		test.Steps[1].Steps[0].Duration != 5 ||
+		test.Environments[0].InitialVariables["baseUrl"] != "https://example.com" ||
+		test.CreatedAt.Year() != 2021 {
+		t.Error("Expected changes to the clone to leave the original unchanged")
+	}
+}
+
+func TestTestEqual(t *testing.T) {
+	test := equalTestFixture()
+
+	// a test read back from the api has server assigned fields and json decoded numbers
+	data, err := json.Marshal(test)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	read := new(Test)
+	if err = json.Unmarshal(data, read); err != nil {
+		t.Fatal(err)
+	}
+	read.ID = "other"
+	read.Steps[0].ID = "other"
+	read.LastRun = &TestRun{Status: TestRunStatusCompleted}
+
+	if !test.Equal(read) {
+		t.Error("Expected test to equal its decoded copy")
+	}
+
+	changed := test.Clone()
+	changed.Steps[1].Steps[0].Duration = 10
+	if test.Equal(changed) {
+		t.Error("Expected nested step change to be detected")
+	}
+
+	changed = test.Clone()
+	changed.Steps[0].Variables[0].Property = "uuid"
+	if test.Equal(changed) {
+		t.Error("Expected variable change to be detected")
+	}
+
+	changed = test.Clone()
+	changed.Steps[0].Headers = nil
+	if test.Equal(changed) {
+		t.Error("Expected header change to be detected")
+	}
+
+	subtest := &TestStep{StepType: StepTypeSubtest, TestUUID: "checkout", BucketKey: "z3n32gktzx94"}
+	other := subtest.Clone()
+	other.TestUUID = "signup"
+	if subtest.Equal(other) {
+		t.Error("Expected subtest target change to be detected")
+	}
+
+	other = subtest.Clone()
+	other.BucketKey = "y8ny9bc8fhbk"
+	if subtest.Equal(other) {
+		t.Error("Expected subtest bucket change to be detected")
+	}
+
+	if test.Equal(nil) || !(*Test)(nil).Equal(nil) {
+		t.Error("Expected nil tests to only equal nil")
+	}
+}
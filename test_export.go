@@ -0,0 +1,94 @@
+package runscope
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+// testExportVersion is the version of the export format understood by runscope's test import
+const testExportVersion = "1.0"
+
+type testExport struct {
+	Name                 string         `json:"name"`
+	Description          string         `json:"description"`
+	Version              string         `json:"version"`
+	ExportedAt           int64          `json:"exported_at,omitempty"`
+	DefaultEnvironmentID string         `json:"default_environment_id,omitempty"`
+	Steps                []*TestStep    `json:"steps"`
+	Environments         []*Environment `json:"environments"`
+}
+
+// Export encodes the test in the json format accepted by the test import of the runscope web ui, see ImportTestJSON
+// for the reverse. The export is stamped with the test's ExportedAt, set it to record when the export was made, the
+// same test always encodes to the same output
+func (test *Test) Export() ([]byte, error) {
+	export := &testExport{
+		Name:                 test.Name,
+		Description:          test.Description,
+		Version:              testExportVersion,
+		DefaultEnvironmentID: test.DefaultEnvironmentID,
+		Steps:                []*TestStep{},
+		Environments:         []*Environment{},
+	}
+
+	if test.ExportedAt != nil {
+		export.ExportedAt = test.ExportedAt.Unix()
+	}
+
+	for _, step := range test.Steps {
+		newStep := *step
+		newStep.ID = ""
+		export.Steps = append(export.Steps, &newStep)
+	}
+
+	for _, environment := range test.Environments {
+		newEnvironment := *environment
+		newEnvironment.ExportedAt = nil
+		newEnvironment.TestID = ""
+		export.Environments = append(export.Environments, &newEnvironment)
+	}
+
+	return json.MarshalIndent(export, "", "  ")
+}
+
+// ImportTestJSON decodes a test exported by Test.Export or the runscope web ui
+func ImportTestJSON(data []byte) (*Test, error) {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("Error reading test export: %s", err)
+	}
+
+	if _, ok := raw["steps"]; !ok {
+		return nil, fmt.Errorf("Error reading test export: missing 'steps'")
+	}
+
+	test := NewTest()
+	if err := decode(test, raw); err != nil {
+		return nil, fmt.Errorf("Error reading test export: %s", err)
+	}
+
+	if err := decodeTestSteps(test, data); err != nil {
+		return nil, fmt.Errorf("Error reading test export: %s", err)
+	}
+
+	return test, nil
+}
+
+// ImportTest creates a test exported by Test.Export or the runscope web ui in a bucket, along with its steps and
+// environments
+func (client *Client) ImportTest(reader io.Reader, bucket *Bucket) (*Test, error) {
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading test export: %s", err)
+	}
+
+	test, err := ImportTestJSON(data)
+	if err != nil {
+		return nil, err
+	}
+
+	test.Bucket = bucket
+	return client.importTest(test)
+}
@@ -0,0 +1,104 @@
+package runscope
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExportRoundTrip(t *testing.T) {
+	exportedAt := time.Unix(1494023021, 0)
+	test := &Test{
+		ID:          "a10c97e6-2024-41ca-990d-5e0b5f751734",
+		Name:        "Sample Name",
+		Description: "Sample description",
+		ExportedAt:  &exportedAt,
+		Steps: []*TestStep{{
+			ID:         "step",
+			StepType:   StepTypeRequest,
+			Method:     "GET",
+			URL:        "https://example.com",
+			Assertions: []*Assertion{AssertStatus().EqualsNumber(200)},
+		}},
+		Environments: []*Environment{{
+			ID:               "env",
+			Name:             "Production",
+			InitialVariables: map[string]string{"baseUrl": "https://example.com"},
+		}},
+	}
+
+	data, err := test.Export()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var raw map[string]interface{}
+	if err = json.Unmarshal(data, &raw); err != nil {
+		t.Fatal(err)
+	}
+
+	if raw["version"] != "1.0" {
+		t.Errorf("Expected version %s, actual %v", "1.0", raw["version"])
+	}
+
+	if raw["exported_at"] != float64(1494023021) {
+		t.Errorf("Expected exported_at %d, actual %v", 1494023021, raw["exported_at"])
+	}
+
+	again, err := test.Export()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(data, again) {
+		t.Errorf("Expected the same export twice, actual %s and %s", data, again)
+	}
+
+	imported, err := ImportTestJSON(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if imported.Name != test.Name || imported.Description != test.Description {
+		t.Errorf("Expected %s %s, actual %s %s", test.Name, test.Description, imported.Name, imported.Description)
+	}
+
+	if len(imported.Steps) != 1 || imported.Steps[0].URL != "https://example.com" {
+		t.Errorf("Expected steps to round trip, actual %v", imported.Steps)
+	}
+
+	if imported.Steps[0].ID != "" {
+		t.Errorf("Expected step id to be stripped, actual %s", imported.Steps[0].ID)
+	}
+
+	if imported.Environments[0].InitialVariables["baseUrl"] != "https://example.com" {
+		t.Errorf("Expected environment variables to round trip, actual %v", imported.Environments[0].InitialVariables)
+	}
+
+	if imported.ExportedAt == nil || !imported.ExportedAt.Equal(exportedAt) {
+		t.Errorf("Expected exported at %s, actual %v", exportedAt, imported.ExportedAt)
+	}
+}
+
+func TestImportTestJSONMissingSteps(t *testing.T) {
+	if _, err := ImportTestJSON([]byte(`{"name": "foo"}`)); err == nil {
+		t.Error("Expected error for export without steps")
+	}
+}
+
+func TestImportTestDefaultEnvironmentOfExport(t *testing.T) {
+	server := newImportServer()
+	defer server.Close()
+
+	export := `{"name": "Petstore", "default_environment_id": "b", "steps": [],
+		"environments": [{"id": "a", "name": "production"}, {"id": "b", "name": "staging"}]}`
+	if _, err := NewClient(server.URL, "token").ImportTest(strings.NewReader(export), &Bucket{Key: "z3n32gktzx94"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if server.updated == nil || server.updated.DefaultEnvironmentID != "env-2" {
+		t.Errorf("Expected default environment %s, actual %v", "env-2", server.updated)
+	}
+}
@@ -0,0 +1,65 @@
+package runscope
+
+import (
+	"fmt"
+	"path"
+)
+
+// FindTestOptions controls how FindTestByName matches test names
+type FindTestOptions struct {
+	// Glob matches the name as a shell pattern, see path.Match
+	Glob bool
+	// Multiple allows more than one test to match instead of returning an error
+	Multiple bool
+}
+
+// FindTestByName finds the tests in a bucket whose name matches name. Passing nil options matches the name exactly
+// and returns an error unless exactly one test matches
+func (client *Client) FindTestByName(bucket *Bucket, name string, options *FindTestOptions) ([]*Test, error) {
+	if options == nil {
+		options = &FindTestOptions{}
+	}
+
+	tests, err := client.ListAllTests(&ListTestsInput{BucketKey: bucket.Key})
+	if err != nil {
+		return nil, err
+	}
+
+	matches, err := matchTestsByName(tests, name, options)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, test := range matches {
+		test.Bucket = bucket
+	}
+
+	return matches, nil
+}
+
+func matchTestsByName(tests []*Test, name string, options *FindTestOptions) ([]*Test, error) {
+	var matches []*Test
+	for _, test := range tests {
+		matched := test.Name == name
+		if options.Glob {
+			var err error
+			if matched, err = path.Match(name, test.Name); err != nil {
+				return nil, fmt.Errorf("Error finding test: %s, invalid pattern: %s", name, err)
+			}
+		}
+
+		if matched {
+			matches = append(matches, test)
+		}
+	}
+
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("Error finding test: %s, no test matches", name)
+	}
+
+	if len(matches) > 1 && !options.Multiple {
+		return nil, fmt.Errorf("Error finding test: %s, %d tests match", name, len(matches))
+	}
+
+	return matches, nil
+}
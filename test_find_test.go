@@ -0,0 +1,56 @@
+package runscope
+
+import (
+	"testing"
+)
+
+func TestFindTestByName(t *testing.T) {
+	testPreCheck(t)
+	client := clientConfigure()
+	bucket, err := client.CreateBucket(&Bucket{Name: "test", Team: &Team{ID: teamID}})
+	defer client.DeleteBucket(bucket.Key)
+	if err != nil {
+		t.Error(err)
+	}
+
+	test, err := client.CreateTest(&Test{Name: "tf_test", Description: "This is a tf test", Bucket: bucket})
+	defer client.DeleteTest(test)
+	if err != nil {
+		t.Error(err)
+	}
+
+	found, err := client.FindTestByName(bucket, "tf_test", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if found[0].ID != test.ID {
+		t.Errorf("Expected test %s, actual %s", test.ID, found[0].ID)
+	}
+}
+
+func TestMatchTestsByName(t *testing.T) {
+	tests := []*Test{{Name: "smoke api"}, {Name: "smoke web"}, {Name: "load"}}
+
+	matches, err := matchTestsByName(tests, "load", &FindTestOptions{})
+	if err != nil || len(matches) != 1 {
+		t.Errorf("Expected one exact match, actual %v %v", matches, err)
+	}
+
+	if _, err = matchTestsByName(tests, "smoke*", &FindTestOptions{Glob: true}); err == nil {
+		t.Error("Expected error for multiple matches")
+	}
+
+	matches, err = matchTestsByName(tests, "smoke*", &FindTestOptions{Glob: true, Multiple: true})
+	if err != nil || len(matches) != 2 {
+		t.Errorf("Expected two glob matches, actual %v %v", matches, err)
+	}
+
+	if _, err = matchTestsByName(tests, "missing", &FindTestOptions{}); err == nil {
+		t.Error("Expected error when no test matches")
+	}
+
+	if _, err = matchTestsByName(tests, "[", &FindTestOptions{Glob: true}); err == nil {
+		t.Error("Expected error for invalid pattern")
+	}
+}
@@ -0,0 +1,442 @@
+package runscope
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// GoTestOptions of Test.GoTest
+type GoTestOptions struct {
+	// Package of the generated file, defaults to "smoke"
+	Package string
+	// Variables are the initial variables of the test, e.g. Environment.InitialVariables. Variables that are not
+	// set here or extracted by a step are read from the environment variables of the go test process
+	Variables map[string]string
+}
+
+// GoTest generates a standalone go test file, depending on the standard library only, that runs the steps of the
+// test with net/http: requests with their headers, form, body and basic auth, variables, assertions, pauses and
+// conditions. Subtest and Ghost Inspector steps are skipped and scripts are not translated, the generated code notes
+// where
+func (test *Test) GoTest(options *GoTestOptions) ([]byte, error) {
+	if options == nil {
+		options = &GoTestOptions{}
+	}
+
+	packageName := options.Package
+	if packageName == "" {
+		packageName = "smoke"
+	}
+
+	var buffer bytes.Buffer
+	fmt.Fprintf(&buffer, "// Code generated by go-runscope from the test %q. DO NOT EDIT.\n\n", test.Name)
+	fmt.Fprintf(&buffer, "package %s\n\n", packageName)
+	buffer.WriteString(goTestImports)
+	fmt.Fprintf(&buffer, "func %s(t *testing.T) {\n", goTestName(test.Name))
+
+	fmt.Fprintln(&buffer, "vars := map[string]string{")
+	var names []string
+	for name := range options.Variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(&buffer, "%q: %q,\n", name, options.Variables[name])
+	}
+	fmt.Fprintln(&buffer, "}")
+	fmt.Fprintln(&buffer, "_ = vars")
+
+	if err := writeGoTestSteps(&buffer, test.Steps, ""); err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintln(&buffer, "}")
+	buffer.WriteString(goTestRuntime)
+
+	source, err := format.Source(buffer.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("Error generating go test: %s, %s", test.Name, err)
+	}
+
+	return source, nil
+}
+
+func writeGoTestSteps(buffer *bytes.Buffer, steps []*TestStep, prefix string) error {
+	for i, step := range steps {
+		name := fmt.Sprintf("%s%d", prefix, i+1)
+		if i > 0 || prefix == "" {
+			fmt.Fprintln(buffer)
+		}
+		if step.Note != "" {
+			fmt.Fprintf(buffer, "// Step %s: %s\n", name, strings.Replace(step.Note, "\n", " ", -1))
+		} else {
+			fmt.Fprintf(buffer, "// Step %s\n", name)
+		}
+
+		if len(step.Scripts) > 0 || len(step.BeforeScripts) > 0 {
+			fmt.Fprintln(buffer, "// The scripts of this step are not translated")
+		}
+
+		switch step.StepType {
+		case StepTypeRequest:
+			writeGoTestRequest(buffer, step, name)
+		case StepTypePause:
+			fmt.Fprintf(buffer, "time.Sleep(%d * time.Second)\n", step.Duration)
+		case StepTypeCondition:
+			fmt.Fprintf(buffer, "if runscopeCompare(t, runscopeExpand(vars, %q), %q, runscopeExpand(vars, %q)) {\n",
+				step.LeftValue, step.Comparison, step.RightValue)
+			if err := writeGoTestSteps(buffer, step.Steps, name+"."); err != nil {
+				return err
+			}
+			fmt.Fprintln(buffer, "}")
+		case StepTypeSubtest, StepTypeGhostInspector:
+			fmt.Fprintf(buffer, "t.Logf(\"step %%s: %%s steps only run on Runscope, skipped\", %q, %q)\n",
+				name, step.StepType)
+		default:
+			return fmt.Errorf("Error generating go test: step %s has unknown type %q", name, step.StepType)
+		}
+	}
+
+	return nil
+}
+
+func writeGoTestRequest(buffer *bytes.Buffer, step *TestStep, name string) {
+	fmt.Fprintln(buffer, "{")
+	fmt.Fprintf(buffer, "response := runscopeDo(t, vars, &runscopeRequest{\nStep: %q,\nMethod: %q,\nURL: %q,\n",
+		name, step.Method, step.URL)
+	writeGoTestParameters(buffer, "Headers", step.Headers)
+	writeGoTestParameters(buffer, "Form", step.Form)
+	if step.Body != "" {
+		fmt.Fprintf(buffer, "Body: %q,\n", step.Body)
+	}
+
+	if step.Auth["auth_type"] == "basic" {
+		fmt.Fprintf(buffer, "Username: %q,\nPassword: %q,\n", step.Auth["username"], step.Auth["password"])
+	}
+	fmt.Fprintln(buffer, "})")
+
+	for _, variable := range step.Variables {
+		fmt.Fprintf(buffer, "runscopeExtract(t, vars, response, %q, %q, %q)\n",
+			variable.Name, variable.Source, variable.Property)
+	}
+
+	for _, assertion := range step.Assertions {
+		fmt.Fprintf(buffer, "runscopeAssert(t, vars, response, %q, %q, %q, %q)\n",
+			assertion.Source, assertion.Property, assertion.Comparison, goTestValue(assertion.Value))
+	}
+	fmt.Fprintln(buffer, "}")
+}
+
+func writeGoTestParameters(buffer *bytes.Buffer, field string, parameters Parameters) {
+	if len(parameters) == 0 {
+		return
+	}
+
+	fmt.Fprintf(buffer, "%s: [][2]string{\n", field)
+	for _, parameter := range parameters {
+		for _, value := range parameter.Values {
+			fmt.Fprintf(buffer, "{%q, %q},\n", parameter.Name, value)
+		}
+	}
+	fmt.Fprintln(buffer, "},")
+}
+
+// goTestValue formats the value of an assertion as it is compared
+func goTestValue(value interface{}) string {
+	switch typed := value.(type) {
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatFloat(typed, 'f', -1, 64)
+	default:
+		return fmt.Sprint(typed)
+	}
+}
+
+// goTestName returns the name of the test function for a test, e.g. TestUserLogin for "user login"
+func goTestName(name string) string {
+	var words []string
+	for _, word := range strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		words = append(words, string(runes))
+	}
+
+	if len(words) == 0 || !unicode.IsLetter([]rune(words[0])[0]) {
+		words = append([]string{"Runscope"}, words...)
+	}
+
+	return "Test" + strings.Join(words, "")
+}
+
+const goTestImports = `import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+`
+
+// goTestRuntime implements the steps of generated tests, the generated file depends on nothing but the standard
+// library
+const goTestRuntime = `
+type runscopeRequest struct {
+	Step     string
+	Method   string
+	URL      string
+	Headers  [][2]string
+	Form     [][2]string
+	Body     string
+	Username string
+	Password string
+}
+
+type runscopeResponse struct {
+	Step     string
+	Status   int
+	Header   http.Header
+	Body     []byte
+	Duration time.Duration
+}
+
+var runscopeVariable = regexp.MustCompile("{{\\s*([^{}\\s]+)\\s*}}")
+
+var runscopeClient = &http.Client{Timeout: time.Minute}
+
+// runscopeExpand replaces the {{name}} references of value by the variables, or the environment variables of the
+// process, references to neither are kept
+func runscopeExpand(vars map[string]string, value string) string {
+	return runscopeVariable.ReplaceAllStringFunc(value, func(reference string) string {
+		name := runscopeVariable.FindStringSubmatch(reference)[1]
+		if value, ok := vars[name]; ok {
+			return value
+		}
+
+		if value, ok := os.LookupEnv(name); ok {
+			return value
+		}
+
+		return reference
+	})
+}
+
+func runscopeDo(t *testing.T, vars map[string]string, step *runscopeRequest) *runscopeResponse {
+	t.Helper()
+	body := runscopeExpand(vars, step.Body)
+	if len(step.Form) > 0 {
+		form := url.Values{}
+		for _, field := range step.Form {
+			form.Add(runscopeExpand(vars, field[0]), runscopeExpand(vars, field[1]))
+		}
+		body = form.Encode()
+	}
+
+	request, err := http.NewRequest(step.Method, runscopeExpand(vars, step.URL), strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("step %s: %s", step.Step, err)
+	}
+
+	if len(step.Form) > 0 {
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	for _, header := range step.Headers {
+		request.Header.Add(runscopeExpand(vars, header[0]), runscopeExpand(vars, header[1]))
+	}
+
+	if step.Username != "" || step.Password != "" {
+		request.SetBasicAuth(runscopeExpand(vars, step.Username), runscopeExpand(vars, step.Password))
+	}
+
+	started := time.Now()
+	response, err := runscopeClient.Do(request)
+	if err != nil {
+		t.Fatalf("step %s: %s", step.Step, err)
+	}
+	defer response.Body.Close()
+
+	responseBody, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		t.Fatalf("step %s: %s", step.Step, err)
+	}
+
+	return &runscopeResponse{Step: step.Step, Status: response.StatusCode, Header: response.Header,
+		Body: responseBody, Duration: time.Since(started)}
+}
+
+// runscopeLookup returns the value of the response a variable or an assertion reads, json values keep their type
+func runscopeLookup(response *runscopeResponse, source string, property string) (interface{}, error) {
+	switch source {
+	case "response_status":
+		return strconv.Itoa(response.Status), nil
+	case "response_headers":
+		return response.Header.Get(property), nil
+	case "response_time_ms":
+		return strconv.FormatInt(int64(response.Duration/time.Millisecond), 10), nil
+	case "response_size_bytes":
+		return strconv.Itoa(len(response.Body)), nil
+	case "response_text":
+		if property == "" {
+			return string(response.Body), nil
+		}
+
+		expression, err := regexp.Compile(property)
+		if err != nil {
+			return nil, err
+		}
+
+		if match := expression.FindSubmatch(response.Body); len(match) > 1 {
+			return string(match[1]), nil
+		}
+		return "", nil
+	case "response_json":
+		decoder := json.NewDecoder(bytes.NewReader(response.Body))
+		decoder.UseNumber()
+		var value interface{}
+		if err := decoder.Decode(&value); err != nil {
+			return nil, err
+		}
+
+		return runscopeJSONPath(value, property)
+	}
+
+	return nil, fmt.Errorf("source %s is not supported", source)
+}
+
+// runscopeJSONPath resolves a path such as data.items[0].id
+func runscopeJSONPath(value interface{}, path string) (interface{}, error) {
+	for _, segment := range strings.FieldsFunc(path, func(r rune) bool { return r == '.' || r == '[' }) {
+		if index, err := strconv.Atoi(strings.TrimSuffix(segment, "]")); err == nil && strings.HasSuffix(segment, "]") {
+			items, ok := value.([]interface{})
+			if !ok || index >= len(items) {
+				return nil, fmt.Errorf("%s: no item %d", path, index)
+			}
+			value = items[index]
+			continue
+		}
+
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s: no key %s", path, segment)
+		}
+		value = object[segment]
+	}
+
+	return value, nil
+}
+
+func runscopeString(value interface{}) string {
+	switch typed := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return typed
+	case json.Number:
+		return typed.String()
+	}
+
+	encoded, _ := json.Marshal(value)
+	return string(encoded)
+}
+
+func runscopeExtract(t *testing.T, vars map[string]string, response *runscopeResponse, name string, source string, property string) {
+	t.Helper()
+	value, err := runscopeLookup(response, source, property)
+	if err != nil {
+		t.Errorf("step %s: variable %s: %s", response.Step, name, err)
+		return
+	}
+
+	vars[name] = runscopeString(value)
+}
+
+func runscopeAssert(t *testing.T, vars map[string]string, response *runscopeResponse, source string, property string, comparison string, expected string) {
+	t.Helper()
+	value, err := runscopeLookup(response, source, property)
+	if err != nil {
+		t.Errorf("step %s: assertion on %s %s: %s", response.Step, source, property, err)
+		return
+	}
+
+	expected = runscopeExpand(vars, expected)
+	if !runscopeCompare(t, value, comparison, expected) {
+		t.Errorf("step %s: expected %s %s %s %q, actual %q", response.Step, source, property, comparison, expected,
+			runscopeString(value))
+	}
+}
+
+func runscopeCompare(t *testing.T, value interface{}, comparison string, expected string) bool {
+	t.Helper()
+	actual := runscopeString(value)
+	switch comparison {
+	case "equal":
+		return actual == expected
+	case "not_equal":
+		return actual != expected
+	case "empty":
+		return actual == "" || actual == "[]" || actual == "{}"
+	case "not_empty":
+		return actual != "" && actual != "[]" && actual != "{}"
+	case "contains":
+		return strings.Contains(actual, expected)
+	case "does_not_contain":
+		return !strings.Contains(actual, expected)
+	case "is_a_number":
+		_, err := strconv.ParseFloat(actual, 64)
+		return err == nil
+	case "is_null":
+		return value == nil
+	case "has_key":
+		object, ok := value.(map[string]interface{})
+		if ok {
+			_, ok = object[expected]
+		}
+		return ok
+	case "has_value":
+		items, _ := value.([]interface{})
+		for _, item := range items {
+			if runscopeString(item) == expected {
+				return true
+			}
+		}
+		return false
+	case "equal_number", "is_less_than", "is_less_than_or_equal", "is_greater_than", "is_greater_than_or_equal":
+		left, leftErr := strconv.ParseFloat(actual, 64)
+		right, rightErr := strconv.ParseFloat(expected, 64)
+		if leftErr != nil || rightErr != nil {
+			return false
+		}
+
+		switch comparison {
+		case "equal_number":
+			return left == right
+		case "is_less_than":
+			return left < right
+		case "is_less_than_or_equal":
+			return left <= right
+		case "is_greater_than":
+			return left > right
+		}
+		return left >= right
+	}
+
+	t.Errorf("comparison %s is not supported", comparison)
+	return false
+}
+`
@@ -0,0 +1,61 @@
+package runscope
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGoTest(t *testing.T) {
+	test := &Test{Name: "user login", Steps: []*TestStep{
+		{StepType: StepTypeRequest, Method: "GET", URL: "{{baseUrl}}/me",
+			Headers:    Parameters{{Name: "Authorization", Values: []string{"Bearer {{token}}"}}},
+			Variables:  []*Variable{Extract("id").FromJSON("data.id")},
+			Assertions: []*Assertion{AssertStatus().Equals(200.0), AssertResponseTime().IsLessThan(500.0)}},
+		{StepType: StepTypeCondition, LeftValue: "{{id}}", Comparison: ComparisonNotEmpty, Steps: []*TestStep{
+			{StepType: StepTypePause, Duration: 2},
+		}},
+		{StepType: StepTypeSubtest, Scripts: []string{"log(1);"}},
+	}}
+
+	source, err := test.GoTest(&GoTestOptions{Variables: map[string]string{"baseUrl": "https://api.example.com"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		"package smoke",
+		"func TestUserLogin(t *testing.T) {",
+		`"baseUrl": "https://api.example.com",`,
+		`{"Authorization", "Bearer {{token}}"},`,
+		`runscopeExtract(t, vars, response, "id", "response_json", "data.id")`,
+		`runscopeAssert(t, vars, response, "response_status", "", "equal", "200")`,
+		`runscopeAssert(t, vars, response, "response_time_ms", "", "is_less_than", "500")`,
+		`case "response_time_ms":`,
+		"time.Sleep(2 * time.Second)",
+		"// The scripts of this step are not translated",
+	} {
+		if !strings.Contains(string(source), expected) {
+			t.Errorf("Expected generated test to contain %s, actual %s", expected, source)
+		}
+	}
+}
+
+func TestGoTestUnknownStep(t *testing.T) {
+	test := &Test{Name: "Health", Steps: []*TestStep{{StepType: "webhook"}}}
+	if _, err := test.GoTest(nil); err == nil {
+		t.Error("Expected an error for an unknown step type")
+	}
+}
+
+func TestGoTestName(t *testing.T) {
+	for name, expected := range map[string]string{
+		"user login":     "TestUserLogin",
+		"GET /v1/health": "TestGETV1Health",
+		"2fa flow":       "TestRunscope2faFlow",
+		"":               "TestRunscope",
+	} {
+		if actual := goTestName(name); actual != expected {
+			t.Errorf("Expected test name %s for %q, actual %s", expected, name, actual)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package runscope
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// TestDependencyGraph records which tests of a bucket invoke which other tests through subtest steps
+type TestDependencyGraph struct {
+	// Tests are the tests of the bucket by ID, subtests may also invoke tests of other buckets which are not included
+	Tests        map[string]*Test
+	dependencies map[string][]string
+	dependents   map[string][]string
+}
+
+// TestDependencyGraph reads the steps of every test in the bucket concurrently and builds the graph of subtest
+// invocations, including subtest steps nested in conditions
+func (client *Client) TestDependencyGraph(bucket *Bucket) (*TestDependencyGraph, error) {
+	tests, err := client.ListAllTests(&ListTestsInput{BucketKey: bucket.Key})
+	if err != nil {
+		return nil, err
+	}
+
+	pool := client.NewPool(&PoolOptions{Concurrency: fanOutConcurrency})
+	for _, test := range tests {
+		test := test
+		pool.Submit(func() error {
+			steps, err := client.ListTestSteps(bucket.Key, test.ID)
+			if err != nil {
+				return err
+			}
+
+			test.Bucket = bucket
+			test.Steps = steps
+			return nil
+		})
+	}
+
+	if pool.Wait() != nil {
+		return nil, pool.Errors()[0]
+	}
+
+	return newTestDependencyGraph(tests), nil
+}
+
+func newTestDependencyGraph(tests []*Test) *TestDependencyGraph {
+	graph := &TestDependencyGraph{
+		Tests:        make(map[string]*Test, len(tests)),
+		dependencies: map[string][]string{},
+		dependents:   map[string][]string{},
+	}
+
+	for _, test := range tests {
+		graph.Tests[test.ID] = test
+	}
+
+	for _, test := range tests {
+		invoked := map[string]bool{}
+		collectSubtests(test.Steps, invoked)
+		for id := range invoked {
+			graph.dependencies[test.ID] = append(graph.dependencies[test.ID], id)
+			graph.dependents[id] = append(graph.dependents[id], test.ID)
+		}
+	}
+
+	for _, ids := range graph.dependencies {
+		sort.Strings(ids)
+	}
+
+	for _, ids := range graph.dependents {
+		sort.Strings(ids)
+	}
+
+	return graph
+}
+
+func collectSubtests(steps []*TestStep, invoked map[string]bool) {
+	for _, step := range steps {
+		if step.StepType == StepTypeSubtest && step.TestUUID != "" {
+			invoked[step.TestUUID] = true
+		}
+
+		collectSubtests(step.Steps, invoked)
+	}
+}
+
+// Dependencies returns the IDs of the tests invoked as subtests by the test
+func (graph *TestDependencyGraph) Dependencies(testID string) []string {
+	return graph.dependencies[testID]
+}
+
+// Dependents returns the IDs of the tests that invoke the test as a subtest, the test is safe to delete or move
+// only when this is empty
+func (graph *TestDependencyGraph) Dependents(testID string) []string {
+	return graph.dependents[testID]
+}
+
+// Cycles returns each cycle of tests invoking each other, as the IDs along the cycle
+func (graph *TestDependencyGraph) Cycles() [][]string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := map[string]int{}
+	var stack []string
+	var cycles [][]string
+
+	var visit func(id string)
+	visit = func(id string) {
+		state[id] = visiting
+		stack = append(stack, id)
+		for _, dependency := range graph.dependencies[id] {
+			switch state[dependency] {
+			case unvisited:
+				visit(dependency)
+			case visiting:
+				for i := len(stack) - 1; i >= 0; i-- {
+					if stack[i] == dependency {
+						cycles = append(cycles, append([]string{}, stack[i:]...))
+						break
+					}
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[id] = visited
+	}
+
+	for _, id := range graph.sortedIDs() {
+		if state[id] == unvisited {
+			visit(id)
+		}
+	}
+
+	return cycles
+}
+
+// TopologicalOrder returns the IDs of the tests of the bucket ordered so every test comes after the tests it invokes.
+// Deleting in reverse order never removes a test still used as a subtest. An error is returned if tests invoke each
+// other in a cycle
+func (graph *TestDependencyGraph) TopologicalOrder() ([]string, error) {
+	if cycles := graph.Cycles(); len(cycles) > 0 {
+		return nil, fmt.Errorf("Error ordering tests: subtest cycle %s", strings.Join(cycles[0], " -> "))
+	}
+
+	remaining := map[string]int{}
+	for _, id := range graph.sortedIDs() {
+		for _, dependency := range graph.dependencies[id] {
+			if _, ok := graph.Tests[dependency]; ok {
+				remaining[id]++
+			}
+		}
+	}
+
+	var ready []string
+	for _, id := range graph.sortedIDs() {
+		if remaining[id] == 0 {
+			ready = append(ready, id)
+		}
+	}
+
+	order := make([]string, 0, len(graph.Tests))
+	for len(ready) > 0 {
+		id := ready[0]
+		ready = ready[1:]
+		order = append(order, id)
+
+		for _, dependent := range graph.dependents[id] {
+			remaining[dependent]--
+			if remaining[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+
+	return order, nil
+}
+
+func (graph *TestDependencyGraph) sortedIDs() []string {
+	ids := make([]string, 0, len(graph.Tests))
+	for id := range graph.Tests {
+		ids = append(ids, id)
+	}
+
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,63 @@
+package runscope
+
+import (
+	"reflect"
+	"testing"
+)
+
+func subtestStep(testID string) *TestStep {
+	return &TestStep{StepType: StepTypeSubtest, TestUUID: testID}
+}
+
+func TestTestDependencyGraph(t *testing.T) {
+	tests := []*Test{
+		{ID: "login"},
+		{ID: "checkout", Steps: []*TestStep{subtestStep("login"), subtestStep("cart")}},
+		{ID: "cart", Steps: []*TestStep{
+			{StepType: StepTypeCondition, Steps: []*TestStep{subtestStep("login")}},
+		}},
+		{ID: "smoke", Steps: []*TestStep{subtestStep("checkout"), subtestStep("other-bucket")}},
+	}
+
+	graph := newTestDependencyGraph(tests)
+
+	if dependencies := graph.Dependencies("checkout"); !reflect.DeepEqual(dependencies, []string{"cart", "login"}) {
+		t.Errorf("Expected checkout to invoke cart and login, actual %v", dependencies)
+	}
+
+	if dependents := graph.Dependents("login"); !reflect.DeepEqual(dependents, []string{"cart", "checkout"}) {
+		t.Errorf("Expected login to be invoked by cart and checkout, actual %v", dependents)
+	}
+
+	if cycles := graph.Cycles(); len(cycles) != 0 {
+		t.Errorf("Expected no cycles, actual %v", cycles)
+	}
+
+	order, err := graph.TopologicalOrder()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"login", "cart", "checkout", "smoke"}
+	if !reflect.DeepEqual(order, expected) {
+		t.Errorf("Expected order %v, actual %v", expected, order)
+	}
+}
+
+func TestTestDependencyGraphCycle(t *testing.T) {
+	graph := newTestDependencyGraph([]*Test{
+		{ID: "a", Steps: []*TestStep{subtestStep("b")}},
+		{ID: "b", Steps: []*TestStep{subtestStep("c")}},
+		{ID: "c", Steps: []*TestStep{subtestStep("a")}},
+		{ID: "d", Steps: []*TestStep{subtestStep("a")}},
+	})
+
+	cycles := graph.Cycles()
+	if len(cycles) != 1 || !reflect.DeepEqual(cycles[0], []string{"a", "b", "c"}) {
+		t.Errorf("Expected cycle a -> b -> c, actual %v", cycles)
+	}
+
+	if _, err := graph.TopologicalOrder(); err == nil {
+		t.Error("Expected error ordering tests with a cycle")
+	}
+}
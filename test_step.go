@@ -1,6 +1,7 @@
 package runscope
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 )
@@ -15,13 +16,24 @@ type TestStep struct {
 	ID            string                 `json:"id,omitempty"`
 	Body          string                 `json:"body,omitempty"`
 	Note          string                 `json:"note,omitempty"`
-	Headers       map[string][]string    `json:"headers,omitempty"`
+	Headers       Parameters             `json:"headers,omitempty"`
+	Form          Parameters             `json:"form,omitempty"`
 	RequestID     string                 `json:"request_id,omitempty"`
 	Assertions    []*Assertion           `json:"assertions,omitempty"`
 	Scripts       []string               `json:"scripts,omitempty"`
 	BeforeScripts []string               `json:"before_scripts,omitempty"`
 	Method        string                 `json:"method,omitempty"`
 	TestUUID      string                 `json:"test_uuid,omitempty"`
+	EnvironmentID string                 `json:"environment_uuid,omitempty"`
+	BucketKey     BucketKey              `json:"bucket_key,omitempty"`
+	Duration      int                    `json:"duration,omitempty"`
+	LeftValue     string                 `json:"left_value,omitempty"`
+	Comparison    string                 `json:"comparison,omitempty"`
+	RightValue    string                 `json:"right_value,omitempty"`
+	Steps         []*TestStep            `json:"steps,omitempty"`
+	GhostTestID   string                 `json:"test_id,omitempty"`
+	// Extra holds fields returned by the api that TestStep doesn't model, they are sent back as-is on update
+	Extra map[string]interface{} `json:"-"`
 }
 
 // NewTestStep creates a new test step struct
@@ -30,22 +42,29 @@ func NewTestStep() *TestStep {
 }
 
 // CreateTestStep creates a new runscope test step. See https://www.runscope.com/docs/api/steps#add
-func (client *Client) CreateTestStep(testStep *TestStep, bucketKey string, testID string) (*TestStep, error) {
+func (client *Client) CreateTestStep(testStep *TestStep, bucketKey BucketKey, testID string) (*TestStep, error) {
 	if error := testStep.validate(); error != nil {
 		return nil, error
 	}
 
+	endpoint, error := bucketEndpoint(bucketKey, "/tests/%s/steps", testID)
+	if error != nil {
+		return nil, error
+	}
+
 	client.Lock()
 	defer client.Unlock()
-	newResource, error := client.createResource(testStep, "test step", testStep.ID,
-		fmt.Sprintf("/buckets/%s/tests/%s/steps", bucketKey, testID))
+	newResource, error := client.createResource(testStep, "test step", testStep.ID, endpoint)
 	if error != nil {
 		return nil, error
 	}
 
-	steps := newResource.Data.([]interface{})
-	step := steps[len(steps)-1].(map[string]interface{})
-	newTestStep, error := getTestStepFromResponse(step)
+	var steps []json.RawMessage
+	if error = json.Unmarshal(newResource.raw, &steps); error != nil || len(steps) == 0 {
+		return nil, fmt.Errorf("Error creating test step: unexpected response %s", newResource.raw)
+	}
+
+	newTestStep, error := getTestStepFromResponse(nil, steps[len(steps)-1])
 	if error != nil {
 		return nil, error
 	}
@@ -53,15 +72,39 @@ func (client *Client) CreateTestStep(testStep *TestStep, bucketKey string, testI
 	return newTestStep, nil
 }
 
+// ListTestSteps lists all steps of a test in order. See https://www.runscope.com/docs/api/steps#list
+func (client *Client) ListTestSteps(bucketKey BucketKey, testID string) ([]*TestStep, error) {
+	endpoint, error := bucketEndpoint(bucketKey, "/tests/%s/steps", testID)
+	if error != nil {
+		return nil, error
+	}
+
+	resource, error := client.readResource("[]test step", testID, endpoint)
+	if error != nil {
+		return nil, error
+	}
+
+	testSteps, error := getTestStepsFromResponse(resource.Data, resource.raw)
+	if error != nil {
+		return nil, error
+	}
+
+	return testSteps, nil
+}
+
 // ReadTestStep list details about an existing test step. https://www.runscope.com/docs/api/steps#detail
-func (client *Client) ReadTestStep(testStep *TestStep, bucketKey string, testID string) (*TestStep, error) {
-	resource, error := client.readResource("test step", testStep.ID,
-		fmt.Sprintf("/buckets/%s/tests/%s/steps/%s", bucketKey, testID, testStep.ID))
+func (client *Client) ReadTestStep(testStep *TestStep, bucketKey BucketKey, testID string) (*TestStep, error) {
+	endpoint, error := bucketEndpoint(bucketKey, "/tests/%s/steps/%s", testID, testStep.ID)
+	if error != nil {
+		return nil, error
+	}
+
+	resource, error := client.readResource("test step", testStep.ID, endpoint)
 	if error != nil {
 		return nil, error
 	}
 
-	readTestStep, error := getTestStepFromResponse(resource.Data)
+	readTestStep, error := getTestStepFromResponse(resource.Data, resource.raw)
 	if error != nil {
 		return nil, error
 	}
@@ -70,14 +113,18 @@ func (client *Client) ReadTestStep(testStep *TestStep, bucketKey string, testID
 }
 
 // UpdateTestStep updates an existing test step. https://www.runscope.com/docs/api/steps#modify
-func (client *Client) UpdateTestStep(testStep *TestStep, bucketKey string, testID string) (*TestStep, error) {
-	resource, error := client.updateResource(testStep, "test step", testStep.ID,
-		fmt.Sprintf("/buckets/%s/tests/%s/steps/%s", bucketKey, testID, testStep.ID))
+func (client *Client) UpdateTestStep(testStep *TestStep, bucketKey BucketKey, testID string) (*TestStep, error) {
+	endpoint, error := bucketEndpoint(bucketKey, "/tests/%s/steps/%s", testID, testStep.ID)
+	if error != nil {
+		return nil, error
+	}
+
+	resource, error := client.updateResource(testStep, "test step", testStep.ID, endpoint)
 	if error != nil {
 		return nil, error
 	}
 
-	readTestStep, error := getTestStepFromResponse(resource.Data)
+	readTestStep, error := getTestStepFromResponse(resource.Data, resource.raw)
 	if error != nil {
 		return nil, error
 	}
@@ -86,17 +133,39 @@ func (client *Client) UpdateTestStep(testStep *TestStep, bucketKey string, testI
 }
 
 // DeleteTestStep delete an existing test step. https://www.runscope.com/docs/api/steps#delete
-func (client *Client) DeleteTestStep(testStep *TestStep, bucketKey string, testID string) error {
-	return client.deleteResource("test step", testStep.ID,
-		fmt.Sprintf("/buckets/%s/tests/%s/steps/%s", bucketKey, testID, testStep.ID))
+func (client *Client) DeleteTestStep(testStep *TestStep, bucketKey BucketKey, testID string) error {
+	endpoint, error := bucketEndpoint(bucketKey, "/tests/%s/steps/%s", testID, testStep.ID)
+	if error != nil {
+		return error
+	}
+
+	return client.deleteResource("test step", testStep.ID, endpoint)
 }
 
-func getTestStepFromResponse(response interface{}) (*TestStep, error) {
+// getTestStepFromResponse decodes a step from the raw json of the response when there is one, keeping the order of
+// its headers and form parameters, decoded maps order them by name
+func getTestStepFromResponse(response interface{}, raw json.RawMessage) (*TestStep, error) {
 	testStep := new(TestStep)
+	if raw != nil {
+		return testStep, json.Unmarshal(raw, testStep)
+	}
+
 	err := decode(testStep, response)
+	captureUnknownStepFields([]*TestStep{testStep}, []interface{}{response})
 	return testStep, err
 }
 
+func getTestStepsFromResponse(response interface{}, raw json.RawMessage) ([]*TestStep, error) {
+	var testSteps []*TestStep
+	if raw != nil {
+		return testSteps, json.Unmarshal(raw, &testSteps)
+	}
+
+	err := decode(&testSteps, response)
+	captureUnknownStepFields(testSteps, response)
+	return testSteps, err
+}
+
 func (step *TestStep) validate() error {
 	if step.StepType == "request" {
 		if err := step.validateRequestType(); err != nil {
@@ -104,6 +173,29 @@ func (step *TestStep) validate() error {
 		}
 	}
 
+	for _, assertion := range step.Assertions {
+		if err := assertion.Validate(); err != nil {
+			return err
+		}
+	}
+
+	for _, variable := range step.Variables {
+		if err := variable.Validate(); err != nil {
+			return err
+		}
+	}
+
+	// auth types other than basic and oauth1 aren't modeled by StepAuth and are passed on unchecked
+	switch step.Auth["auth_type"] {
+	case AuthTypeBasic, AuthTypeOAuth1:
+		auth, err := step.TypedAuth()
+		if err != nil {
+			return err
+		}
+
+		return auth.Validate()
+	}
+
 	return nil
 }
 
@@ -134,6 +134,48 @@ func TestReadTestStep(t *testing.T) {
 	}
 }
 
+func TestListTestSteps(t *testing.T) {
+	testPreCheck(t)
+	client := clientConfigure()
+	bucket, err := client.CreateBucket(&Bucket{Name: "test", Team: &Team{ID: teamID}})
+	defer client.DeleteBucket(bucket.Key)
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	test := &Test{Name: "tf_test", Description: "This is a tf test", Bucket: bucket}
+	test, err = client.CreateTest(test)
+	defer client.DeleteTest(test)
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	for _, method := range []string{"GET", "POST"} {
+		step := NewTestStep()
+		step.StepType = "request"
+		step.URL = "http://example.com"
+		step.Method = method
+		if _, err = client.CreateTestStep(step, bucket.Key, test.ID); err != nil {
+			t.Error(err)
+		}
+	}
+
+	steps, err := client.ListTestSteps(bucket.Key, test.ID)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if len(steps) != 2 {
+		t.Fatalf("Expected %d steps, actual %d", 2, len(steps))
+	}
+
+	if steps[1].Method != "POST" {
+		t.Errorf("Expected step method %s, actual %s", "POST", steps[1].Method)
+	}
+}
+
 func TestUpdateTestStep(t *testing.T) {
 	testPreCheck(t)
 	client := clientConfigure()
@@ -66,6 +66,50 @@ func TestReadTest(t *testing.T) {
 	}
 }
 
+func TestReadTestFull(t *testing.T) {
+	testPreCheck(t)
+	client := clientConfigure()
+	bucket, err := client.CreateBucket(&Bucket{Name: "newTest", Team: &Team{ID: teamID}})
+	defer client.DeleteBucket(bucket.Key)
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	newTest := &Test{Name: "tf_test", Description: "This is a tf newTest", Bucket: bucket}
+	newTest, err = client.CreateTest(newTest)
+	defer client.DeleteTest(newTest)
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	step := NewTestStep()
+	step.StepType = "request"
+	step.URL = "http://example.com"
+	step.Method = "GET"
+	if _, err = client.CreateTestStep(step, bucket.Key, newTest.ID); err != nil {
+		t.Error(err)
+	}
+
+	detail, err := client.ReadTestFull(newTest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if detail.Test.Name != newTest.Name {
+		t.Errorf("Expected name %s, actual %s", newTest.Name, detail.Test.Name)
+	}
+
+	if len(detail.Steps) != 1 {
+		t.Errorf("Expected %d steps, actual %d", 1, len(detail.Steps))
+	}
+
+	if len(detail.Environments) == 0 {
+		t.Error("Expected the default test environment")
+	}
+}
+
 func TestReadTestMetrics(t *testing.T) {
 	testPreCheck(t)
 	client := clientConfigure()
@@ -102,6 +146,107 @@ func TestReadTestMetrics(t *testing.T) {
 	}
 }
 
+func TestLatestResult(t *testing.T) {
+	testPreCheck(t)
+	client := clientConfigure()
+	bucket, err := client.CreateBucket(&Bucket{Name: "newTest", Team: &Team{ID: teamID}})
+	defer client.DeleteBucket(bucket.Key)
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	newTest := &Test{Name: "tf_test", Description: "This is a tf newTest", Bucket: bucket}
+	newTest, err = client.CreateTest(newTest)
+	defer client.DeleteTest(newTest)
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	run, err := client.LatestResult(newTest)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if run != nil {
+		t.Errorf("Expected no run for a new test, actual %s", run.Status)
+	}
+}
+
+func TestTestRunResult(t *testing.T) {
+	passed := &TestRun{Status: TestRunStatusCompleted, AssertionCount: 2, AssertionSuccess: 2, ScriptCount: 1, ScriptSuccess: 1}
+	if passed.Result() != TestResultPass {
+		t.Errorf("Expected result %s, actual %s", TestResultPass, passed.Result())
+	}
+
+	failed := &TestRun{Status: TestRunStatusCompleted, AssertionCount: 2, AssertionSuccess: 1}
+	if failed.Result() != TestResultFail {
+		t.Errorf("Expected result %s, actual %s", TestResultFail, failed.Result())
+	}
+
+	errored := &TestRun{Status: TestRunStatusCompleted, ErrorCount: 1}
+	if errored.Passed() {
+		t.Error("Expected run with errors not to pass")
+	}
+
+	working := &TestRun{Status: TestRunStatusWorking}
+	if working.Result() != TestResultWorking {
+		t.Errorf("Expected result %s, actual %s", TestResultWorking, working.Result())
+	}
+}
+
+func TestTestMetricSummary(t *testing.T) {
+	metric := &TestMetric{
+		Region:    MetricsAll,
+		Timeframe: MetricsTimeframeDay,
+		ResponseTimes: []ResponseTime{
+			{SuccessRatio: 1, AverageResponseTimeMs: 100},
+			{},
+			{SuccessRatio: 0.5, AverageResponseTimeMs: 300},
+		},
+		ThisTimePeriod: TimePeriodMetic{ResponseTime95thPercentile: 280, TotalTestRuns: 12},
+	}
+
+	summary := metric.Summary()
+	if summary.AverageResponseTimeMs != 200 {
+		t.Errorf("Expected average %f, actual %f", 200.0, summary.AverageResponseTimeMs)
+	}
+
+	if summary.SuccessRatio != 0.75 {
+		t.Errorf("Expected success ratio %f, actual %f", 0.75, summary.SuccessRatio)
+	}
+
+	if summary.ResponseTime95thPercentile != 280 || summary.TotalTestRuns != 12 {
+		t.Errorf("Expected period metrics to be copied, actual %#v", summary)
+	}
+}
+
+func TestTestMetricSummaryFailedInterval(t *testing.T) {
+	metric := &TestMetric{}
+	data := `{"response_times": [
+		{"success_ratio": 1, "timestamp": 1494023000, "avg_response_time_ms": 100},
+		{"success_ratio": null, "timestamp": 1494026600, "avg_response_time_ms": null},
+		{"success_ratio": 0, "timestamp": 1494030200, "avg_response_time_ms": 0}
+	]}`
+	if err := json.Unmarshal([]byte(data), metric); err != nil {
+		t.Fatal(err)
+	}
+
+	if metric.ResponseTimes[1].HasRuns() || !metric.ResponseTimes[2].HasRuns() {
+		t.Errorf("Expected only the null interval to have no runs, actual %#v", metric.ResponseTimes)
+	}
+
+	summary := metric.Summary()
+	if summary.SuccessRatio != 0.5 {
+		t.Errorf("Expected success ratio %f, actual %f", 0.5, summary.SuccessRatio)
+	}
+
+	if summary.AverageResponseTimeMs != 50 {
+		t.Errorf("Expected average %f, actual %f", 50.0, summary.AverageResponseTimeMs)
+	}
+}
+
 func TestUpdateTest(t *testing.T) {
 	testPreCheck(t)
 	client := clientConfigure()
@@ -300,7 +445,7 @@ func TestReadFromResponse(t *testing.T) {
 		t.Error(err)
 	}
 
-	test, err := getTestFromResponse(responseMap.Data)
+	test, err := getTestFromResponse(responseMap.Data, responseMap.raw)
 	if err != nil {
 		t.Error(err)
 	}
@@ -349,10 +494,18 @@ func TestReadFromResponse(t *testing.T) {
 	}
 
 	expectedTime = time.Unix(1494623241, 385894060)
+	if !test.LastRun.Passed() {
+		t.Errorf("Expected last run to pass, actual %s", test.LastRun.Result())
+	}
+
 	if !test.LastRun.FinishedAt.Equal(expectedTime) {
 		t.Errorf("Expected last run finished at time %s, actual %s", expectedTime.String(), test.LastRun.FinishedAt)
 	}
 
+	if !test.LastRun.Scheduled() {
+		t.Errorf("Expected last run initiator %s, actual %s", RunInitiatorSchedule, test.LastRun.Initiator())
+	}
+
 	if len(test.Steps) != 1 {
 		t.Errorf("Expected %d steps, actual %d", 1, len(test.Steps))
 	}
@@ -391,7 +544,7 @@ func TestReadFromResponse(t *testing.T) {
 		t.Errorf("Expected %d headers, actual %d", 1, len(step.Headers))
 	}
 
-	header := step.Headers["Authorization"]
+	header := step.Headers.Get("Authorization")
 	if len(header) != 1 {
 		t.Errorf("Expected %d authorization values, actual %d", 1, len(header))
 	}
@@ -509,3 +662,68 @@ func TestListsTestsMoreThan10(t *testing.T) {
 		t.Errorf("Expected %d tests, actual no found %d", 15, len(tests))
 	}
 }
+
+func TestListTestsPage(t *testing.T) {
+	testPreCheck(t)
+	client := clientConfigure()
+	bucket, err := client.CreateBucket(&Bucket{Name: "newTest", Team: &Team{ID: teamID}})
+	defer client.DeleteBucket(bucket.Key)
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		newTest := &Test{Name: fmt.Sprintf("tf_test1-%d", i), Description: "This is a tf newTest", Bucket: bucket}
+		newTest, err = client.CreateTest(newTest)
+		if err != nil {
+			t.Error(err)
+		}
+
+		defer client.DeleteTest(&Test{Bucket: bucket, ID: newTest.ID})
+	}
+
+	page, err := client.ListTestsPage(&ListTestsInput{BucketKey: bucket.Key, Count: 2, Offset: 1})
+	if err != nil {
+		t.Error(err)
+	}
+
+	if len(page.Tests) != 2 {
+		t.Errorf("Expected %d tests, actual %d", 2, len(page.Tests))
+	}
+
+	if page.Offset != 1 {
+		t.Errorf("Expected offset %d, actual %d", 1, page.Offset)
+	}
+}
+
+func TestTestsPageHasMore(t *testing.T) {
+	full := &TestsPage{Tests: make([]*Test, 10), Count: 10}
+	if !full.HasMore() {
+		t.Error("Expected a full page without a total to have more")
+	}
+
+	partial := &TestsPage{Tests: make([]*Test, 5), Count: 10}
+	if partial.HasMore() {
+		t.Error("Expected a partial page to be the last")
+	}
+
+	last := &TestsPage{Tests: make([]*Test, 10), Count: 10, Offset: 10, Total: 20}
+	if last.HasMore() {
+		t.Error("Expected the page reaching the total to be the last")
+	}
+}
+
+func TestTestRunInitiator(t *testing.T) {
+	for source, expected := range map[string]string{
+		"scheduled": RunInitiatorSchedule,
+		"trigger":   RunInitiatorTrigger,
+		"Editor":    RunInitiatorUI,
+		"api":       RunInitiatorAPI,
+		"":          RunInitiatorUnknown,
+	} {
+		if initiator := (&TestRun{Source: source}).Initiator(); initiator != expected {
+			t.Errorf("Expected initiator %s for source %q, actual %s", expected, source, initiator)
+		}
+	}
+}
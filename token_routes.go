@@ -0,0 +1,99 @@
+package runscope
+
+import (
+	"strings"
+	"sync"
+)
+
+// tokenRoutes maps teams and buckets to the access token requests for them are authorized with
+type tokenRoutes struct {
+	mu      sync.RWMutex
+	teams   map[string]string
+	buckets map[BucketKey]string
+}
+
+// RouteTeam authorizes requests for the team with this UUID, e.g. its people, agents and integrations, with token
+// instead of Client.AccessToken
+func (client *Client) RouteTeam(teamID string, token string) {
+	client.tokens.mu.Lock()
+	defer client.tokens.mu.Unlock()
+	if client.tokens.teams == nil {
+		client.tokens.teams = map[string]string{}
+	}
+
+	client.tokens.teams[teamID] = token
+}
+
+// RouteBucket authorizes requests for the bucket with this key, its tests, environments and results, with token
+// instead of Client.AccessToken
+func (client *Client) RouteBucket(key BucketKey, token string) {
+	client.tokens.mu.Lock()
+	defer client.tokens.mu.Unlock()
+	if client.tokens.buckets == nil {
+		client.tokens.buckets = map[BucketKey]string{}
+	}
+
+	client.tokens.buckets[key] = token
+}
+
+// AddToken registers an access token of another Runscope organization. The teams of the token's account and the
+// buckets it can access are routed to it, so a single Client manages resources across organizations. Buckets
+// created later with the token's teams are routed on creation, other buckets must be routed with RouteBucket
+func (client *Client) AddToken(token string) error {
+	scoped := client.withToken(token)
+	account, err := scoped.ReadAccount()
+	if err != nil {
+		return err
+	}
+
+	buckets, err := scoped.ListBuckets(nil)
+	if err != nil {
+		return err
+	}
+
+	for _, team := range account.Teams {
+		client.RouteTeam(team.ID, token)
+	}
+
+	for _, bucket := range buckets {
+		client.RouteBucket(bucket.Key, token)
+	}
+
+	return nil
+}
+
+// accessToken returns the token routed to the bucket or team the endpoint belongs to, Client.AccessToken otherwise
+func (client *Client) accessToken(endpoint string) string {
+	client.tokens.mu.RLock()
+	defer client.tokens.mu.RUnlock()
+
+	segments := strings.Split(strings.SplitN(endpoint, "?", 2)[0], "/")
+	if len(segments) > 2 && segments[0] == "" {
+		switch segments[1] {
+		case "buckets":
+			if token, ok := client.tokens.buckets[BucketKey(segments[2])]; ok {
+				return token
+			}
+		case "teams":
+			if token, ok := client.tokens.teams[segments[2]]; ok {
+				return token
+			}
+		}
+	}
+
+	return client.AccessToken
+}
+
+// teamToken returns the token routed to the team, Client.AccessToken otherwise
+func (client *Client) teamToken(teamID string) string {
+	return client.accessToken("/teams/" + teamID)
+}
+
+// withToken returns a client sharing the api url, http client and result cache, authorized with token only
+func (client *Client) withToken(token string) *Client {
+	if token == client.AccessToken {
+		return client
+	}
+
+	return &Client{APIURL: client.APIURL, AccessToken: token, HTTP: client.HTTP, ResultCache: client.ResultCache}
+}
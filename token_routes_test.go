@@ -0,0 +1,76 @@
+package runscope
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAccessTokenRoutes(t *testing.T) {
+	client := NewClient(APIURL, "default")
+	client.RouteTeam("870ed937-bc6e-4d8b-a9a5-d7f9f2412fa3", "team")
+	client.RouteBucket("z3n32gktzx94", "bucket")
+
+	for endpoint, expected := range map[string]string{
+		"/account":                             "default",
+		"/buckets":                             "default",
+		"/buckets/z3n32gktzx94/tests?count=10": "bucket",
+		"/buckets/6t0sd3euxlwa/tests":          "default",
+		"/teams/870ed937-bc6e-4d8b-a9a5-d7f9f2412fa3/people": "team",
+		"/teams/1eeb3695-5d0f-467c-9d51-8b773dce29ba/people": "default",
+	} {
+		if token := client.accessToken(endpoint); token != expected {
+			t.Errorf("Expected token %s for %s, actual %s", expected, endpoint, token)
+		}
+	}
+}
+
+func TestAddToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := r.Header.Get("Authorization")
+		switch {
+		case r.URL.Path == "/account" && token == "Bearer other":
+			fmt.Fprint(w, `{"data": {"name": "Platform", "teams": [{"name": "Acme", "id": "acme-team"}]}}`)
+		case r.URL.Path == "/buckets" && token == "Bearer other":
+			fmt.Fprint(w, `{"data": [{"name": "acme", "key": "acmebucket01", "team": {"name": "Acme", "id": "acme-team"}}]}`)
+		case r.URL.Path == "/buckets/acmebucket01/tests" && token == "Bearer other":
+			fmt.Fprint(w, `{"data": [{"id": "1", "name": "Health"}]}`)
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error": {"status": 401, "error": "unauthorized"}}`)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "default")
+	if err := client.AddToken("other"); err != nil {
+		t.Fatal(err)
+	}
+
+	if token := client.teamToken("acme-team"); token != "other" {
+		t.Errorf("Expected team token %s, actual %s", "other", token)
+	}
+
+	tests, err := client.ListTests(&ListTestsInput{BucketKey: "acmebucket01"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(tests) != 1 || tests[0].Name != "Health" {
+		t.Errorf("Expected test Health read with the routed token, actual %v", tests)
+	}
+
+	buckets, err := client.ListBuckets(&ListBucketsInput{TeamID: "acme-team"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(buckets) != 1 || buckets[0].Key != "acmebucket01" {
+		t.Errorf("Expected bucket acmebucket01 listed with the team token, actual %v", buckets)
+	}
+
+	if _, err := client.ReadAccount(); err == nil {
+		t.Error("Expected account read with the default token to fail")
+	}
+}
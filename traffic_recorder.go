@@ -0,0 +1,146 @@
+package runscope
+
+import (
+	"bytes"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// TrafficRecorder is an http.RoundTripper recording the requests a program makes through it, e.g. an integration
+// test, and the status and content type of their responses, to turn them into a test monitoring the same calls
+type TrafficRecorder struct {
+	// Transport makes the recorded requests, http.DefaultTransport when nil
+	Transport http.RoundTripper
+	mu        sync.Mutex
+	exchanges []*recordedExchange
+}
+
+// RecordingOptions of TrafficRecorder.Test
+type RecordingOptions struct {
+	// Name of the test, defaults to "Recorded traffic"
+	Name string
+	// BaseURL is replaced by {{baseUrl}} in the urls of the steps, e.g. the url of the server the recorded test
+	// ran against, so the test can monitor another deployment
+	BaseURL string
+}
+
+type recordedExchange struct {
+	method      string
+	url         string
+	headers     http.Header
+	body        []byte
+	status      int
+	contentType string
+}
+
+var recordedVariableName = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+// NewTrafficRecorder creates a recorder making requests with transport, http.DefaultTransport when nil
+func NewTrafficRecorder(transport http.RoundTripper) *TrafficRecorder {
+	return &TrafficRecorder{Transport: transport}
+}
+
+// RoundTrip makes the request with the recorder's transport and records it, requests that fail without response
+// are not recorded
+func (recorder *TrafficRecorder) RoundTrip(request *http.Request) (*http.Response, error) {
+	exchange := &recordedExchange{method: request.Method, url: request.URL.String(), headers: request.Header.Clone()}
+	if request.Body != nil && request.Body != http.NoBody {
+		body, err := ioutil.ReadAll(request.Body)
+		request.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		exchange.body = body
+		request = request.Clone(request.Context())
+		request.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	transport := recorder.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	response, err := transport.RoundTrip(request)
+	if err != nil {
+		return nil, err
+	}
+
+	exchange.status = response.StatusCode
+	exchange.contentType = response.Header.Get("Content-Type")
+
+	recorder.mu.Lock()
+	recorder.exchanges = append(recorder.exchanges, exchange)
+	recorder.mu.Unlock()
+
+	return response, nil
+}
+
+// Reset forgets the recorded requests
+func (recorder *TrafficRecorder) Reset() {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	recorder.exchanges = nil
+}
+
+// Test converts the recorded requests into a test with one request step per request in recorded order. Steps keep
+// the recorded headers and body, and assert the recorded status and the media type of the response. Credential
+// headers are replaced by a reference to a variable named after the header, e.g. {{authorization}}, to set in the
+// environment of the test
+func (recorder *TrafficRecorder) Test(options *RecordingOptions) *Test {
+	if options == nil {
+		options = &RecordingOptions{}
+	}
+
+	test := NewTest()
+	test.Name = options.Name
+	if test.Name == "" {
+		test.Name = "Recorded traffic"
+	}
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	for _, exchange := range recorder.exchanges {
+		test.Steps = append(test.Steps, exchange.step(options.BaseURL))
+	}
+
+	return test
+}
+
+func (exchange *recordedExchange) step(baseURL string) *TestStep {
+	step := NewTestStep()
+	step.StepType = StepTypeRequest
+	step.Method = exchange.method
+	step.URL = exchange.url
+	if baseURL != "" && strings.HasPrefix(step.URL, baseURL) {
+		step.URL = "{{baseUrl}}" + strings.TrimPrefix(step.URL, baseURL)
+	}
+
+	var names []string
+	for name := range exchange.headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		values := exchange.headers[name]
+		if credentialHeaders[strings.ToLower(name)] || isCredentialName(name) {
+			values = []string{"{{" + strings.ToLower(recordedVariableName.ReplaceAllString(name, "_")) + "}}"}
+		}
+
+		step.Headers.Set(name, values...)
+	}
+
+	step.Body = string(exchange.body)
+	step.Assertions = []*Assertion{AssertStatus().EqualsNumber(exchange.status)}
+	if mediaType, _, err := mime.ParseMediaType(exchange.contentType); err == nil {
+		step.Assertions = append(step.Assertions, AssertHeader("Content-Type").Contains(mediaType))
+	}
+
+	return step
+}
@@ -0,0 +1,66 @@
+package runscope
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTrafficRecorder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"id": "1"}`)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	recorder := NewTrafficRecorder(nil)
+	client := &http.Client{Transport: recorder}
+
+	request, _ := http.NewRequest("POST", server.URL+"/users", strings.NewReader(`{"name": "jane"}`))
+	request.Header.Set("Authorization", "Bearer abc123")
+	request.Header.Set("Content-Type", "application/json")
+	if _, err := client.Do(request); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.Get(server.URL + "/users/1"); err != nil {
+		t.Fatal(err)
+	}
+
+	test := recorder.Test(&RecordingOptions{Name: "Users", BaseURL: server.URL})
+	if test.Name != "Users" || len(test.Steps) != 2 {
+		t.Fatalf("Expected test Users with 2 steps, actual %s with %d", test.Name, len(test.Steps))
+	}
+
+	create := test.Steps[0]
+	if create.Method != "POST" || create.URL != "{{baseUrl}}/users" || create.Body != `{"name": "jane"}` {
+		t.Errorf("Expected POST {{baseUrl}}/users with the recorded body, actual %s %s %s",
+			create.Method, create.URL, create.Body)
+	}
+
+	if value := create.Headers.Get("Authorization"); len(value) != 1 || value[0] != "{{authorization}}" {
+		t.Errorf("Expected Authorization {{authorization}}, actual %v", value)
+	}
+
+	if len(create.Assertions) != 2 || create.Assertions[0].Value != 201 ||
+		create.Assertions[1].Value != "application/json" {
+		t.Errorf("Expected status 201 and content type assertions, actual %v", create.Assertions)
+	}
+
+	if read := test.Steps[1]; len(read.Assertions) != 1 || read.Assertions[0].Value != 204 {
+		t.Errorf("Expected only a status 204 assertion, actual %v", read.Assertions)
+	}
+
+	recorder.Reset()
+	if test = recorder.Test(nil); len(test.Steps) != 0 || test.Name != "Recorded traffic" {
+		t.Errorf("Expected an empty test after reset, actual %d steps", len(test.Steps))
+	}
+}
@@ -0,0 +1,233 @@
+package runscope
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+// TriggerResult is the response of a trigger url, listing the runs that were queued. See https://www.runscope.com/docs/api-testing/integrations#trigger
+type TriggerResult struct {
+	Runs        []*TriggerRun `json:"runs"`
+	RunsStarted int           `json:"runs_started"`
+	RunsFailed  int           `json:"runs_failed"`
+	RunsTotal   int           `json:"runs_total"`
+}
+
+// TriggerRun is a single run queued by a trigger, one per test, environment, region and agent
+type TriggerRun struct {
+	TestID          string            `json:"test_id"`
+	TestName        string            `json:"test_name"`
+	TestURL         string            `json:"test_url"`
+	TestRunID       string            `json:"test_run_id"`
+	TestRunURL      string            `json:"test_run_url"`
+	BucketKey       BucketKey         `json:"bucket_key"`
+	EnvironmentID   string            `json:"environment_id"`
+	EnvironmentName string            `json:"environment_name"`
+	Region          string            `json:"region"`
+	Agent           string            `json:"agent"`
+	AgentExpired    bool              `json:"agent_expired"`
+	Status          string            `json:"status"`
+	URL             string            `json:"url"`
+	Variables       map[string]string `json:"variables"`
+}
+
+// ParseTriggerResult parses the body returned by a trigger url, for callers that trigger tests themselves, e.g. from
+// a deploy hook, and want to follow up on the queued runs
+func ParseTriggerResult(body []byte) (*TriggerResult, error) {
+	response := new(response)
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to Unmarshal response body: %v", err)
+	}
+
+	return getTriggerResultFromResponse(response.Data)
+}
+
+// Test returns the test the run belongs to, identified by its ID and bucket, which can be passed to ReadResult
+// along with TestRunID to read the result of the run
+func (run *TriggerRun) Test() *Test {
+	return &Test{ID: run.TestID, Name: run.TestName, Bucket: &Bucket{Key: run.BucketKey}}
+}
+
+// TriggerTest runs a test via its trigger url. When environment is nil the test's default environment is used, vars
+// override the environment's initial variables for this run only
+func (client *Client) TriggerTest(test *Test, environment *Environment, vars map[string]string) (*TriggerResult, error) {
+	triggerURL := test.TriggerURL
+	if triggerURL == "" {
+		readTest, err := client.ReadTest(test)
+		if err != nil {
+			return nil, err
+		}
+
+		triggerURL = readTest.TriggerURL
+	}
+
+	environmentID := ""
+	if environment != nil {
+		environmentID = environment.ID
+	}
+
+	return client.trigger("test", test.ID, triggerURL, environmentID, vars)
+}
+
+// TriggerBucket runs the tests of a bucket. When filter is nil every test is run via the bucket trigger url, otherwise
+// each test for which filter returns true is triggered individually. The runs of all triggers are combined into one
+// result
+func (client *Client) TriggerBucket(
+	bucket *Bucket, filter func(test *Test) bool, vars map[string]string) (*TriggerResult, error) {
+	if filter == nil {
+		triggerURL := bucket.TriggerURL
+		if triggerURL == "" {
+			readBucket, err := client.ReadBucket(bucket.Key)
+			if err != nil {
+				return nil, err
+			}
+
+			triggerURL = readBucket.TriggerURL
+		}
+
+		return client.trigger("bucket", bucket.Key.String(), triggerURL, "", vars)
+	}
+
+	tests, err := client.ListAllTests(&ListTestsInput{BucketKey: bucket.Key})
+	if err != nil {
+		return nil, err
+	}
+
+	combined := &TriggerResult{}
+	for _, test := range tests {
+		if !filter(test) {
+			continue
+		}
+
+		test.Bucket = bucket
+		result, err := client.TriggerTest(test, nil, vars)
+		if err != nil {
+			return combined, err
+		}
+
+		combined.add(result)
+	}
+
+	return combined, nil
+}
+
+// TriggerAndWait triggers a test and blocks until every queued run has finished, returning one result per run, for
+// example one per region. It returns ctx.Err() if ctx is done first
+func (client *Client) TriggerAndWait(
+	ctx context.Context, test *Test, environment *Environment, vars map[string]string) ([]*TestResult, error) {
+	triggered, err := client.TriggerTest(test, environment, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []*TestResult
+	for _, run := range triggered.Runs {
+		runTest := run.Test()
+		if runTest.Bucket.Key == "" {
+			runTest.Bucket = test.Bucket
+		}
+
+		result, err := client.WaitForResult(ctx, runTest, run.TestRunID, nil)
+		if err != nil {
+			return results, err
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// RerunResult triggers a test again with the environment, initial variables, region and agent of a previous run,
+// to retry the exact configuration of a failed run
+func (client *Client) RerunResult(test *Test, testRunID string) (*TriggerResult, error) {
+	result, err := client.ReadResult(test, testRunID)
+	if err != nil {
+		return nil, err
+	}
+
+	vars := make(map[string]string, len(result.InitialVariables)+2)
+	for name, value := range result.InitialVariables {
+		vars[name] = value
+	}
+
+	if result.Region != "" {
+		vars["runscope_region"] = result.Region
+	}
+
+	if result.Agent != "" {
+		vars["runscope_agent"] = result.Agent
+	}
+
+	var environment *Environment
+	if result.EnvironmentID != "" {
+		environment = &Environment{ID: result.EnvironmentID}
+	}
+
+	return client.TriggerTest(test, environment, vars)
+}
+
+func (result *TriggerResult) add(other *TriggerResult) {
+	result.Runs = append(result.Runs, other.Runs...)
+	result.RunsStarted += other.RunsStarted
+	result.RunsFailed += other.RunsFailed
+	result.RunsTotal += other.RunsTotal
+}
+
+func (client *Client) trigger(
+	resourceType string, resourceName string, triggerURL string, environmentID string, vars map[string]string) (*TriggerResult, error) {
+	DebugF(1, "triggering %s %s", resourceType, resourceName)
+
+	endpoint, err := url.Parse(triggerURL)
+	if err != nil {
+		return nil, fmt.Errorf("Error during parsing trigger URL: %s", err)
+	}
+
+	query := endpoint.Query()
+	for name, value := range vars {
+		query.Set(name, value)
+	}
+
+	if environmentID != "" {
+		query.Set("runscope_environment", environmentID)
+	}
+	endpoint.RawQuery = query.Encode()
+
+	DebugF(2, "	request: GET %s", endpoint.String())
+	req, err := http.NewRequest("GET", endpoint.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("Error during creation of request: %s", err)
+	}
+	req.Header.Add("Accept", "application/json")
+
+	resp, err := client.HTTP.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, _ := ioutil.ReadAll(resp.Body)
+	DebugF(2, "	response: %d %s", resp.StatusCode, string(bodyBytes))
+
+	if resp.StatusCode >= 300 {
+		errorResp := new(errorResponse)
+		if err = json.Unmarshal(bodyBytes, &errorResp); err != nil {
+			return nil, fmt.Errorf("Status: %s Error triggering %s: %s", resp.Status, resourceType, resourceName)
+		}
+
+		return nil, fmt.Errorf("Status: %s Error triggering %s: %s, reason: %q",
+			resp.Status, resourceType, resourceName, errorResp.ErrorMessage)
+	}
+
+	return ParseTriggerResult(bodyBytes)
+}
+
+func getTriggerResultFromResponse(response interface{}) (*TriggerResult, error) {
+	result := new(TriggerResult)
+	err := decode(result, response)
+	return result, err
+}
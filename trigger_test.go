@@ -0,0 +1,219 @@
+package runscope
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTriggerTest(t *testing.T) {
+	testPreCheck(t)
+	client := clientConfigure()
+	bucket, err := client.CreateBucket(&Bucket{Name: "test", Team: &Team{ID: teamID}})
+	defer client.DeleteBucket(bucket.Key)
+	if err != nil {
+		t.Error(err)
+	}
+
+	test, err := client.CreateTest(&Test{Name: "tf_test", Description: "This is a tf test", Bucket: bucket})
+	defer client.DeleteTest(test)
+	if err != nil {
+		t.Error(err)
+	}
+
+	result, err := client.TriggerTest(test, nil, map[string]string{"baseUrl": "https://example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.RunsStarted == 0 {
+		t.Errorf("Expected runs to be started, actual %d", result.RunsStarted)
+	}
+
+	if result.Runs[0].TestID != test.ID {
+		t.Errorf("Expected test id %s, actual %s", test.ID, result.Runs[0].TestID)
+	}
+}
+
+func TestTriggerAndWait(t *testing.T) {
+	testPreCheck(t)
+	client := clientConfigure()
+	bucket, err := client.CreateBucket(&Bucket{Name: "test", Team: &Team{ID: teamID}})
+	defer client.DeleteBucket(bucket.Key)
+	if err != nil {
+		t.Error(err)
+	}
+
+	test, err := client.CreateTest(&Test{Name: "tf_test", Description: "This is a tf test", Bucket: bucket})
+	defer client.DeleteTest(test)
+	if err != nil {
+		t.Error(err)
+	}
+
+	step := NewTestStep()
+	step.StepType = "request"
+	step.URL = "http://example.com"
+	step.Method = "GET"
+	if _, err = client.CreateTestStep(step, bucket.Key, test.ID); err != nil {
+		t.Error(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	results, err := client.TriggerAndWait(ctx, test, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, result := range results {
+		if !result.Done() {
+			t.Errorf("Expected result to be done, actual %s", result.Result)
+		}
+	}
+}
+
+func TestRerunResult(t *testing.T) {
+	testPreCheck(t)
+	client := clientConfigure()
+	bucket, err := client.CreateBucket(&Bucket{Name: "test", Team: &Team{ID: teamID}})
+	defer client.DeleteBucket(bucket.Key)
+	if err != nil {
+		t.Error(err)
+	}
+
+	test, err := client.CreateTest(&Test{Name: "tf_test", Description: "This is a tf test", Bucket: bucket})
+	defer client.DeleteTest(test)
+	if err != nil {
+		t.Error(err)
+	}
+
+	triggered, err := client.TriggerTest(test, nil, map[string]string{"baseUrl": "https://example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rerun, err := client.RerunResult(test, triggered.Runs[0].TestRunID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rerun.Runs[0].EnvironmentID != triggered.Runs[0].EnvironmentID {
+		t.Errorf("Expected environment %s, actual %s", triggered.Runs[0].EnvironmentID, rerun.Runs[0].EnvironmentID)
+	}
+
+	if rerun.Runs[0].Region != triggered.Runs[0].Region {
+		t.Errorf("Expected region %s, actual %s", triggered.Runs[0].Region, rerun.Runs[0].Region)
+	}
+}
+
+func TestTriggerBucket(t *testing.T) {
+	testPreCheck(t)
+	client := clientConfigure()
+	bucket, err := client.CreateBucket(&Bucket{Name: "test", Team: &Team{ID: teamID}})
+	defer client.DeleteBucket(bucket.Key)
+	if err != nil {
+		t.Error(err)
+	}
+
+	for _, name := range []string{"smoke_a", "smoke_b", "load"} {
+		test, err := client.CreateTest(&Test{Name: name, Description: "This is a tf test", Bucket: bucket})
+		if err != nil {
+			t.Error(err)
+		}
+		defer client.DeleteTest(test)
+	}
+
+	result, err := client.TriggerBucket(bucket, func(test *Test) bool {
+		return strings.HasPrefix(test.Name, "smoke")
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Runs) != 2 {
+		t.Errorf("Expected %d runs, actual %d", 2, len(result.Runs))
+	}
+}
+
+func TestTriggerResultAdd(t *testing.T) {
+	result := &TriggerResult{Runs: []*TriggerRun{{TestID: "a"}}, RunsStarted: 1, RunsTotal: 1}
+	result.add(&TriggerResult{Runs: []*TriggerRun{{TestID: "b"}}, RunsFailed: 1, RunsTotal: 1})
+
+	if len(result.Runs) != 2 || result.RunsStarted != 1 || result.RunsFailed != 1 || result.RunsTotal != 2 {
+		t.Errorf("Expected combined result, actual %#v", result)
+	}
+}
+
+func TestTriggerResultFromResponse(t *testing.T) {
+	responseBody := `
+{
+  "meta": {
+    "status": "success"
+  },
+  "data": {
+    "runs": [
+      {
+        "agent": null,
+        "agent_expired": false,
+        "bucket_key": "6knqzmsg6tc7",
+        "environment_id": "1eeb3695-5d0f-467c-9d51-8b773dce29ba",
+        "environment_name": "Test Settings",
+        "region": "us1",
+        "status": "init",
+        "test_id": "8e7afae4-23b6-492a-b4b9-75d515b5082b",
+        "test_name": "Smoke test",
+        "test_run_id": "cd5b1b4a-3c4e-4a48-b3c7-a7c2b7cb3d6a",
+        "test_run_url": "https://www.runscope.com/radar/6knqzmsg6tc7/8e7afae4-23b6-492a-b4b9-75d515b5082b/history/cd5b1b4a-3c4e-4a48-b3c7-a7c2b7cb3d6a",
+        "test_url": "https://www.runscope.com/radar/6knqzmsg6tc7/8e7afae4-23b6-492a-b4b9-75d515b5082b",
+        "url": "https://api.runscope.com/buckets/6knqzmsg6tc7/tests/8e7afae4-23b6-492a-b4b9-75d515b5082b/results/cd5b1b4a-3c4e-4a48-b3c7-a7c2b7cb3d6a",
+        "variables": {
+          "baseUrl": "https://example.com"
+        }
+      }
+    ],
+    "runs_failed": 0,
+    "runs_started": 1,
+    "runs_total": 1
+  },
+  "error": null
+}
+`
+	result, err := ParseTriggerResult([]byte(responseBody))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.RunsStarted != 1 {
+		t.Errorf("Expected runs started %d, actual %d", 1, result.RunsStarted)
+	}
+
+	if result.Runs[0].TestRunID != "cd5b1b4a-3c4e-4a48-b3c7-a7c2b7cb3d6a" {
+		t.Errorf("Expected test run id %s, actual %s", "cd5b1b4a-3c4e-4a48-b3c7-a7c2b7cb3d6a", result.Runs[0].TestRunID)
+	}
+
+	if result.Runs[0].Region != "us1" {
+		t.Errorf("Expected region %s, actual %s", "us1", result.Runs[0].Region)
+	}
+
+	run := result.Runs[0]
+	if run.EnvironmentName != "Test Settings" || run.TestName != "Smoke test" || run.Status != "init" {
+		t.Errorf("Expected environment, test name and status, actual %#v", run)
+	}
+
+	if run.Variables["baseUrl"] != "https://example.com" {
+		t.Errorf("Expected variable baseUrl %s, actual %s", "https://example.com", run.Variables["baseUrl"])
+	}
+
+	test := run.Test()
+	if test.ID != run.TestID || test.Bucket.Key != "6knqzmsg6tc7" {
+		t.Errorf("Expected test %s in bucket %s, actual %s %s", run.TestID, "6knqzmsg6tc7", test.ID, test.Bucket.Key)
+	}
+}
+
+func TestParseTriggerResultInvalid(t *testing.T) {
+	if _, err := ParseTriggerResult([]byte("<html>")); err == nil {
+		t.Error("Expected error for invalid body")
+	}
+}
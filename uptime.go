@@ -0,0 +1,110 @@
+package runscope
+
+import (
+	"sort"
+	"time"
+)
+
+// UptimeReport is the availability of a test over a time range for each region it runs in
+type UptimeReport struct {
+	From    time.Time
+	To      time.Time
+	Regions map[string]*RegionUptime
+}
+
+// RegionUptime is the availability of a test in a region. Availability is the percentage of the time range the test
+// was not down, it is down from the start of a failed run until the start of the next passed run
+type RegionUptime struct {
+	Region       string
+	Runs         int
+	Failed       int
+	Availability float64
+	Downtime     []*DowntimeInterval
+}
+
+// DowntimeInterval is a period in which the runs of a test failed, Failures is the number of failed runs in it
+type DowntimeInterval struct {
+	Start    time.Time
+	End      time.Time
+	Failures int
+}
+
+// Duration returns the length of the interval
+func (interval *DowntimeInterval) Duration() time.Duration {
+	return interval.End.Sub(interval.Start)
+}
+
+// Uptime computes the availability of a test in each region from its runs started between from and to, as reported
+// in monthly SLOs
+func (client *Client) Uptime(test *Test, from time.Time, to time.Time) (*UptimeReport, error) {
+	var results []*TestResult
+	iterator := client.IterateResults(test, &ResultFilter{Since: from, Before: to})
+	for iterator.Next() {
+		results = append(results, iterator.Result())
+	}
+
+	if err := iterator.Err(); err != nil {
+		return nil, err
+	}
+
+	return computeUptime(results, from, to), nil
+}
+
+func computeUptime(results []*TestResult, from time.Time, to time.Time) *UptimeReport {
+	report := &UptimeReport{From: from, To: to, Regions: map[string]*RegionUptime{}}
+
+	var runs []*TestResult
+	for _, result := range results {
+		if result.StartedAt != nil && (result.Result == TestResultPass || result.Result == TestResultFail) {
+			runs = append(runs, result)
+		}
+	}
+
+	sort.SliceStable(runs, func(i, j int) bool { return runs[i].StartedAt.Before(*runs[j].StartedAt) })
+
+	current := map[string]*DowntimeInterval{}
+	for _, run := range runs {
+		region := report.Regions[run.Region]
+		if region == nil {
+			region = &RegionUptime{Region: run.Region}
+			report.Regions[run.Region] = region
+		}
+
+		region.Runs++
+		interval := current[run.Region]
+		if run.Result == TestResultPass {
+			if interval != nil {
+				interval.End = *run.StartedAt
+				current[run.Region] = nil
+			}
+			continue
+		}
+
+		region.Failed++
+		if interval == nil {
+			interval = &DowntimeInterval{Start: *run.StartedAt}
+			region.Downtime = append(region.Downtime, interval)
+			current[run.Region] = interval
+		}
+		interval.Failures++
+	}
+
+	window := to.Sub(from)
+	for name, region := range report.Regions {
+		if interval := current[name]; interval != nil {
+			interval.End = to
+		}
+
+		var downtime time.Duration
+		for _, interval := range region.Downtime {
+			downtime += interval.Duration()
+		}
+
+		region.Availability = 100
+		if window > 0 {
+			region.Availability = 100 * (1 - float64(downtime)/float64(window))
+		}
+	}
+
+	return report
+}
@@ -0,0 +1,50 @@
+package runscope
+
+import (
+	"testing"
+	"time"
+)
+
+func TestComputeUptime(t *testing.T) {
+	from := time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(100 * time.Hour)
+	run := func(result string, region string, offset time.Duration) *TestResult {
+		run := aggregateRun(result, from, offset, time.Second)
+		run.Region = region
+		return run
+	}
+
+	results := []*TestResult{
+		run(TestResultPass, "us1", 0),
+		run(TestResultFail, "us1", 10*time.Hour),
+		run(TestResultFail, "us1", 11*time.Hour),
+		run(TestResultPass, "us1", 12*time.Hour),
+		run(TestResultFail, "us1", 95*time.Hour),
+		run(TestResultPass, "eu1", 0),
+		run(TestResultPass, "eu1", 50*time.Hour),
+		{Result: TestResultWorking, Region: "eu1"},
+	}
+
+	report := computeUptime(results, from, to)
+	us1 := report.Regions["us1"]
+	if us1.Runs != 5 || us1.Failed != 3 || len(us1.Downtime) != 2 {
+		t.Fatalf("Expected 5 runs with 3 failed in 2 intervals, actual %d %d %d", us1.Runs, us1.Failed, len(us1.Downtime))
+	}
+
+	if us1.Downtime[0].Duration() != 2*time.Hour || us1.Downtime[0].Failures != 2 {
+		t.Errorf("Expected first downtime of 2h with 2 failures, actual %s %d",
+			us1.Downtime[0].Duration(), us1.Downtime[0].Failures)
+	}
+
+	if !us1.Downtime[1].End.Equal(to) {
+		t.Errorf("Expected ongoing downtime to end at %s, actual %s", to, us1.Downtime[1].End)
+	}
+
+	if us1.Availability != 93 {
+		t.Errorf("Expected availability %f, actual %f", 93.0, us1.Availability)
+	}
+
+	if eu1 := report.Regions["eu1"]; eu1.Availability != 100 || eu1.Runs != 2 {
+		t.Errorf("Expected eu1 to be fully available, actual %f %d", eu1.Availability, eu1.Runs)
+	}
+}
@@ -0,0 +1,107 @@
+package runscope
+
+import (
+	"time"
+)
+
+// usagePeriod is the length of the billing period usage is estimated over
+const usagePeriod = 30 * 24 * time.Hour
+
+// Usage is the consumption of a team, the api doesn't report requests used against the plan so scheduled runs and
+// requests are estimated over a 30 day period from the schedules, regions and steps of every test
+type Usage struct {
+	Buckets   int
+	Tests     int
+	Schedules int
+	// ScheduledRuns is the number of runs the schedules start in a period, one per region of their environment
+	ScheduledRuns int
+	// ScheduledRequests is the number of request steps the scheduled runs execute in a period
+	ScheduledRequests int
+}
+
+// Usage estimates the consumption of a team, for capacity planning ahead of its plan limits
+func (client *Client) Usage(teamID string) (*Usage, error) {
+	buckets, err := client.ListBuckets(&ListBucketsInput{TeamID: teamID})
+	if err != nil {
+		return nil, err
+	}
+
+	usage := &Usage{Buckets: len(buckets)}
+	for _, bucket := range buckets {
+		shared, err := client.ListSharedEnvironment(bucket)
+		if err != nil {
+			return nil, err
+		}
+
+		tests, err := client.ListAllTests(&ListTestsInput{BucketKey: bucket.Key})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, test := range tests {
+			test.Bucket = bucket
+			detail, err := client.ReadTestFull(test)
+			if err != nil {
+				return nil, err
+			}
+
+			usage.add(detail, shared)
+		}
+	}
+
+	return usage, nil
+}
+
+// Remaining returns how many requests are left of allowance after the scheduled requests, negative when the
+// schedules alone exceed it
+func (usage *Usage) Remaining(allowance int) int {
+	return allowance - usage.ScheduledRequests
+}
+
+func (usage *Usage) add(detail *TestDetail, shared []*Environment) {
+	usage.Tests++
+	usage.Schedules += len(detail.Schedules)
+
+	requests := 0
+	for _, step := range detail.Steps {
+		requests += countRequestSteps(step)
+	}
+
+	environments := map[string]*Environment{}
+	for _, environment := range append(append([]*Environment{}, shared...), detail.Environments...) {
+		environments[environment.ID] = environment
+	}
+
+	for _, schedule := range detail.Schedules {
+		regions := 1
+		if environment, ok := environments[schedule.EnvironmentID]; ok && len(environment.Regions) > 0 {
+			regions = len(environment.Regions)
+		}
+
+		runs := scheduledRuns(schedule.Interval, regions)
+		usage.ScheduledRuns += runs
+		usage.ScheduledRequests += runs * requests
+	}
+}
+
+func countRequestSteps(step *TestStep) int {
+	count := 0
+	if step.StepType == StepTypeRequest {
+		count++
+	}
+
+	for _, nested := range step.Steps {
+		count += countRequestSteps(nested)
+	}
+
+	return count
+}
+
+func scheduledRuns(interval ScheduleInterval, regions int) int {
+	duration := interval.Duration()
+	if duration <= 0 {
+		return 0
+	}
+
+	return int(usagePeriod/duration) * regions
+}
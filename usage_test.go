@@ -0,0 +1,52 @@
+package runscope
+
+import (
+	"testing"
+)
+
+func TestUsageAdd(t *testing.T) {
+	shared := []*Environment{{ID: "shared", Regions: []string{"us1", "eu1"}}}
+	detail := &TestDetail{
+		Test: &Test{Name: "Smoke test"},
+		Steps: []*TestStep{
+			{StepType: StepTypeRequest},
+			{StepType: StepTypeCondition, Steps: []*TestStep{{StepType: StepTypeRequest}}},
+			{StepType: StepTypePause},
+		},
+		Schedules: []*Schedule{
+			{EnvironmentID: "shared", Interval: EveryHour},
+			{EnvironmentID: "test", Interval: EveryDay},
+		},
+		Environments: []*Environment{{ID: "test"}},
+	}
+
+	usage := &Usage{}
+	usage.add(detail, shared)
+
+	if usage.Tests != 1 || usage.Schedules != 2 {
+		t.Errorf("Expected 1 test with 2 schedules, actual %d %d", usage.Tests, usage.Schedules)
+	}
+
+	expectedRuns := 720*2 + 30
+	if usage.ScheduledRuns != expectedRuns || usage.ScheduledRequests != expectedRuns*2 {
+		t.Errorf("Expected %d runs and %d requests, actual %d %d",
+			expectedRuns, expectedRuns*2, usage.ScheduledRuns, usage.ScheduledRequests)
+	}
+
+	if usage.Remaining(5000) != 5000-expectedRuns*2 {
+		t.Errorf("Expected %d remaining, actual %d", 5000-expectedRuns*2, usage.Remaining(5000))
+	}
+}
+
+func TestUsage(t *testing.T) {
+	testPreCheck(t)
+	client := clientConfigure()
+	usage, err := client.Usage(teamID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if usage.Buckets == 0 {
+		t.Errorf("Expected some buckets got %d", usage.Buckets)
+	}
+}
@@ -0,0 +1,91 @@
+package runscope
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// Variable sources. See https://www.runscope.com/docs/api/steps#variables
+const (
+	VariableSourceResponseStatus  = "response_status"
+	VariableSourceResponseHeaders = "response_headers"
+	VariableSourceResponseJSON    = "response_json"
+	VariableSourceResponseXML     = "response_xml"
+	VariableSourceResponseText    = "response_text"
+	VariableSourceResponseTime    = "response_time_ms"
+	VariableSourceResponseSize    = "response_size_bytes"
+)
+
+var jsonPathPattern = regexp.MustCompile(`^([^.\[\]]+)?(\[\d+\])*(\.[^.\[\]]+(\[\d+\])*)*$`)
+
+// VariableBuilder builds a variable extracted from a step response, e.g. Extract("token").FromJSON("data.token")
+type VariableBuilder struct {
+	name string
+}
+
+// Extract starts building a variable with the given name
+func Extract(name string) *VariableBuilder {
+	return &VariableBuilder{name: name}
+}
+
+// FromJSON extracts the value at path from a json response body, e.g. "data.items[0].id"
+func (builder *VariableBuilder) FromJSON(path string) *Variable {
+	return &Variable{Name: builder.name, Source: VariableSourceResponseJSON, Property: path}
+}
+
+// FromXML extracts the value at the xpath from an xml response body
+func (builder *VariableBuilder) FromXML(xpath string) *Variable {
+	return &Variable{Name: builder.name, Source: VariableSourceResponseXML, Property: xpath}
+}
+
+// FromHeader extracts the value of the named response header
+func (builder *VariableBuilder) FromHeader(name string) *Variable {
+	return &Variable{Name: builder.name, Source: VariableSourceResponseHeaders, Property: name}
+}
+
+// FromText extracts the first capture group of the regular expression matched against the response body
+func (builder *VariableBuilder) FromText(expression string) *Variable {
+	return &Variable{Name: builder.name, Source: VariableSourceResponseText, Property: expression}
+}
+
+// FromStatus extracts the response status code
+func (builder *VariableBuilder) FromStatus() *Variable {
+	return &Variable{Name: builder.name, Source: VariableSourceResponseStatus}
+}
+
+// FromResponseTime extracts the response time in milliseconds
+func (builder *VariableBuilder) FromResponseTime() *Variable {
+	return &Variable{Name: builder.name, Source: VariableSourceResponseTime}
+}
+
+// FromResponseSize extracts the response size in bytes
+func (builder *VariableBuilder) FromResponseSize() *Variable {
+	return &Variable{Name: builder.name, Source: VariableSourceResponseSize}
+}
+
+// Validate checks the variable has a name and that its property is valid for the source, variables extracted from
+// sources without a constant are passed on unchecked
+func (variable *Variable) Validate() error {
+	if variable.Name == "" {
+		return fmt.Errorf("A variable with source %q must specify 'Name'", variable.Source)
+	}
+
+	switch variable.Source {
+	case VariableSourceResponseStatus, VariableSourceResponseTime, VariableSourceResponseSize:
+		return nil
+	case VariableSourceResponseJSON:
+		if !jsonPathPattern.MatchString(variable.Property) || variable.Property == "" {
+			return fmt.Errorf("Variable %s has invalid json path %q", variable.Name, variable.Property)
+		}
+	case VariableSourceResponseText:
+		if _, err := regexp.Compile(variable.Property); err != nil {
+			return fmt.Errorf("Variable %s has invalid regular expression %q: %s", variable.Name, variable.Property, err)
+		}
+	case VariableSourceResponseHeaders, VariableSourceResponseXML:
+		if variable.Property == "" {
+			return fmt.Errorf("Variable %s with source %q must specify 'Property'", variable.Name, variable.Source)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package runscope
+
+import (
+	"testing"
+)
+
+func TestVariableBuilder(t *testing.T) {
+	variable := Extract("token").FromJSON("data.token")
+	if variable.Name != "token" {
+		t.Errorf("Expected name %s, actual %s", "token", variable.Name)
+	}
+
+	if variable.Source != VariableSourceResponseJSON {
+		t.Errorf("Expected source %s, actual %s", VariableSourceResponseJSON, variable.Source)
+	}
+
+	if variable.Property != "data.token" {
+		t.Errorf("Expected property %s, actual %s", "data.token", variable.Property)
+	}
+}
+
+func TestVariableValidate(t *testing.T) {
+	valid := []*Variable{
+		Extract("id").FromJSON("data.items[0].id"),
+		Extract("first").FromJSON("[0]"),
+		Extract("location").FromHeader("Location"),
+		Extract("csrf").FromText(`name="csrf" value="([^"]+)"`),
+		Extract("status").FromStatus(),
+		{Name: "foo", Source: "response_unknown"},
+	}
+
+	for _, variable := range valid {
+		if err := variable.Validate(); err != nil {
+			t.Errorf("Expected variable %#v to be valid, actual error %s", variable, err)
+		}
+	}
+
+	invalid := []*Variable{
+		Extract("").FromStatus(),
+		Extract("id").FromJSON(""),
+		Extract("id").FromJSON("data..id"),
+		Extract("id").FromJSON("data.items[a]"),
+		Extract("id").FromJSON("data.items[0"),
+		Extract("csrf").FromText("(unclosed"),
+		Extract("location").FromHeader(""),
+	}
+
+	for _, variable := range invalid {
+		if err := variable.Validate(); err == nil {
+			t.Errorf("Expected variable %#v to be invalid", variable)
+		}
+	}
+}
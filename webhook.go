@@ -0,0 +1,98 @@
+package runscope
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// ResultNotification is the payload Runscope posts to a webhook url when a test run completes. See
+// https://www.runscope.com/docs/api-testing/notifications#webhooks
+type ResultNotification struct {
+	TestID           string                 `json:"test_id"`
+	TestName         string                 `json:"test_name"`
+	TestURL          string                 `json:"test_url"`
+	TestRunID        string                 `json:"test_run_id"`
+	TestRunURL       string                 `json:"test_run_url"`
+	TriggerURL       string                 `json:"trigger_url"`
+	TeamID           string                 `json:"team_id"`
+	TeamName         string                 `json:"team_name"`
+	BucketKey        BucketKey              `json:"bucket_key"`
+	BucketName       string                 `json:"bucket_name"`
+	EnvironmentID    string                 `json:"environment_uuid"`
+	EnvironmentName  string                 `json:"environment_name"`
+	Result           string                 `json:"result"`
+	StartedAt        *time.Time             `json:"started_at"`
+	FinishedAt       *time.Time             `json:"finished_at"`
+	Agent            string                 `json:"agent"`
+	AgentExpired     bool                   `json:"agent_expired"`
+	Region           string                 `json:"region"`
+	RegionName       string                 `json:"region_name"`
+	Variables        map[string]string      `json:"variables"`
+	InitialVariables map[string]string      `json:"initial_variables"`
+	Requests         []*NotificationRequest `json:"requests"`
+}
+
+// NotificationRequest summarises a request of the run reported by a ResultNotification
+type NotificationRequest struct {
+	StepType          string              `json:"step_type"`
+	Method            string              `json:"method"`
+	URL               string              `json:"url"`
+	Note              string              `json:"note"`
+	Result            string              `json:"result"`
+	ResponseStatus    int                 `json:"response_status_code"`
+	ResponseSizeBytes int                 `json:"response_size_bytes"`
+	ResponseTimeMs    int                 `json:"response_time_ms"`
+	Assertions        *NotificationCounts `json:"assertions"`
+	Scripts           *NotificationCounts `json:"scripts"`
+	Variables         *NotificationCounts `json:"variables"`
+}
+
+// NotificationCounts is the number of assertions, scripts or variables of a request that passed and failed
+type NotificationCounts struct {
+	Pass  int `json:"pass"`
+	Fail  int `json:"fail"`
+	Total int `json:"total"`
+}
+
+// ParseResultWebhook reads the ResultNotification posted by Runscope to a webhook receiver
+func ParseResultWebhook(r *http.Request) (*ResultNotification, error) {
+	if r.Body == nil {
+		return nil, fmt.Errorf("Error reading webhook: empty body")
+	}
+	defer r.Body.Close()
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading webhook: %s", err)
+	}
+
+	return parseResultNotification(body)
+}
+
+func parseResultNotification(body []byte) (*ResultNotification, error) {
+	var payload interface{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("Error reading webhook: %s", err)
+	}
+
+	notification := new(ResultNotification)
+	if err := decode(notification, payload); err != nil {
+		return nil, fmt.Errorf("Error reading webhook: %s", err)
+	}
+
+	return notification, nil
+}
+
+// Test returns the test the notification is about, which can be passed to ReadResult along with TestRunID to read the
+// details of the run
+func (notification *ResultNotification) Test() *Test {
+	return &Test{ID: notification.TestID, Name: notification.TestName, Bucket: &Bucket{Key: notification.BucketKey}}
+}
+
+// Passed reports whether every request of the run passed
+func (notification *ResultNotification) Passed() bool {
+	return notification.Result == TestResultPass
+}
@@ -0,0 +1,113 @@
+package runscope
+
+import (
+	"crypto/subtle"
+	"net"
+	"net/http"
+)
+
+// WebhookEvent is passed to the callbacks of a WebhookHandler
+type WebhookEvent func(notification *ResultNotification) error
+
+// WebhookHandler is an http.Handler receiving the webhook notifications of Runscope. Requests are only accepted when
+// they carry Secret in the query parameter SecretParameter or come from one of AllowedSources, a handler with neither
+// set rejects every request.
+// Notifications are passed to OnPass or OnFail depending on the result of the run, and to OnResult for any result.
+// A callback returning an error makes the handler respond with status 500, so that the notification can be retried
+type WebhookHandler struct {
+	Secret string
+	// SecretParameter defaults to "secret"
+	SecretParameter string
+	// AllowedSources are the networks notifications may come from, matched against the remote address of the request
+	AllowedSources []*net.IPNet
+	OnPass         WebhookEvent
+	OnFail         WebhookEvent
+	OnResult       WebhookEvent
+}
+
+func (handler *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !handler.verify(r) {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+
+	notification, err := ParseResultWebhook(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := handler.dispatch(notification); err != nil {
+		ErrorF(1, "Error handling webhook for test run %s: %s", notification.TestRunID, err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// verify accepts requests carrying the secret or coming from an allowed source, a handler without either accepts
+// none
+func (handler *WebhookHandler) verify(r *http.Request) bool {
+	if handler.Secret != "" {
+		parameter := handler.SecretParameter
+		if parameter == "" {
+			parameter = "secret"
+		}
+
+		secret := r.URL.Query().Get(parameter)
+		if subtle.ConstantTimeCompare([]byte(secret), []byte(handler.Secret)) == 1 {
+			return true
+		}
+	}
+
+	if len(handler.AllowedSources) == 0 {
+		return false
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+
+	for _, network := range handler.AllowedSources {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (handler *WebhookHandler) dispatch(notification *ResultNotification) error {
+	var event WebhookEvent
+	switch notification.Result {
+	case TestResultPass:
+		event = handler.OnPass
+	case TestResultFail:
+		event = handler.OnFail
+	}
+
+	if event != nil {
+		if err := event(notification); err != nil {
+			return err
+		}
+	}
+
+	if handler.OnResult != nil {
+		return handler.OnResult(notification)
+	}
+
+	return nil
+}
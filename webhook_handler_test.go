@@ -0,0 +1,93 @@
+package runscope
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func serveWebhook(handler http.Handler, target string, remoteAddr string) int {
+	request := httptest.NewRequest("POST", target, strings.NewReader(resultWebhookPayload))
+	request.RemoteAddr = remoteAddr
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	return recorder.Code
+}
+
+func TestWebhookHandlerSecret(t *testing.T) {
+	var failed, results int
+	handler := &WebhookHandler{
+		Secret:   "s3cret",
+		OnPass:   func(*ResultNotification) error { t.Error("Expected failed run not to call OnPass"); return nil },
+		OnFail:   func(*ResultNotification) error { failed++; return nil },
+		OnResult: func(*ResultNotification) error { results++; return nil },
+	}
+
+	if code := serveWebhook(handler, "/runscope?secret=wrong", "192.0.2.1:1234"); code != http.StatusForbidden {
+		t.Errorf("Expected status %d for a wrong secret, actual %d", http.StatusForbidden, code)
+	}
+
+	if code := serveWebhook(handler, "/runscope?secret=s3cret", "192.0.2.1:1234"); code != http.StatusNoContent {
+		t.Errorf("Expected status %d, actual %d", http.StatusNoContent, code)
+	}
+
+	if failed != 1 || results != 1 {
+		t.Errorf("Expected OnFail and OnResult to be called once, actual %d %d", failed, results)
+	}
+}
+
+func TestWebhookHandlerAllowedSources(t *testing.T) {
+	_, network, _ := net.ParseCIDR("198.51.100.0/24")
+	handler := &WebhookHandler{AllowedSources: []*net.IPNet{network}}
+
+	if code := serveWebhook(handler, "/runscope", "192.0.2.1:1234"); code != http.StatusForbidden {
+		t.Errorf("Expected status %d for an unknown source, actual %d", http.StatusForbidden, code)
+	}
+
+	if code := serveWebhook(handler, "/runscope", "198.51.100.7:1234"); code != http.StatusNoContent {
+		t.Errorf("Expected status %d, actual %d", http.StatusNoContent, code)
+	}
+}
+
+func TestWebhookHandlerSecretOrSource(t *testing.T) {
+	_, network, _ := net.ParseCIDR("198.51.100.0/24")
+	handler := &WebhookHandler{Secret: "s3cret", AllowedSources: []*net.IPNet{network}}
+
+	if code := serveWebhook(handler, "/runscope?secret=s3cret", "192.0.2.1:1234"); code != http.StatusNoContent {
+		t.Errorf("Expected status %d for the secret from an unknown source, actual %d", http.StatusNoContent, code)
+	}
+
+	if code := serveWebhook(handler, "/runscope", "198.51.100.7:1234"); code != http.StatusNoContent {
+		t.Errorf("Expected status %d for an allowed source without the secret, actual %d", http.StatusNoContent, code)
+	}
+
+	if code := serveWebhook(handler, "/runscope?secret=wrong", "192.0.2.1:1234"); code != http.StatusForbidden {
+		t.Errorf("Expected status %d, actual %d", http.StatusForbidden, code)
+	}
+}
+
+func TestWebhookHandlerUnconfigured(t *testing.T) {
+	if code := serveWebhook(&WebhookHandler{}, "/runscope", "198.51.100.7:1234"); code != http.StatusForbidden {
+		t.Errorf("Expected status %d without secret or allowed sources, actual %d", http.StatusForbidden, code)
+	}
+}
+
+func TestWebhookHandlerCallbackError(t *testing.T) {
+	RegisterLogHandlers(func(int, string, ...interface{}) {}, defaultHandler, func(int, string, ...interface{}) {})
+	defer RegisterLogHandlers(defaultHandler, defaultHandler, defaultHandler)
+
+	handler := &WebhookHandler{Secret: "s3cret", OnFail: func(*ResultNotification) error { return errors.New("pager down") }}
+	if code := serveWebhook(handler, "/runscope?secret=s3cret", "192.0.2.1:1234"); code != http.StatusInternalServerError {
+		t.Errorf("Expected status %d, actual %d", http.StatusInternalServerError, code)
+	}
+
+	request := httptest.NewRequest("GET", "/runscope", nil)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status %d, actual %d", http.StatusMethodNotAllowed, recorder.Code)
+	}
+}
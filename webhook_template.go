@@ -0,0 +1,155 @@
+package runscope
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Payload templates of a WebhookIntegration for receivers with a fixed schema
+const (
+	// MicrosoftTeamsPayloadTemplate posts a message card to a Microsoft Teams incoming webhook
+	MicrosoftTeamsPayloadTemplate = `{
+  "@type": "MessageCard",
+  "@context": "https://schema.org/extensions",
+  "summary": "{{test_name}} {{result}}",
+  "title": "{{test_name}} {{result}} in {{environment_name}}",
+  "text": "Run in {{region_name}} of bucket {{bucket_name}}",
+  "potentialAction": [
+    {"@type": "OpenUri", "name": "View run", "targets": [{"os": "default", "uri": "{{test_run_url}}"}]}
+  ]
+}`
+	// OpsGeniePayloadTemplate creates an alert with the Opsgenie alert api, one alert per test and environment
+	OpsGeniePayloadTemplate = `{
+  "message": "{{test_name}} {{result}} in {{environment_name}}",
+  "alias": "runscope-{{test_id}}-{{environment_uuid}}",
+  "description": "{{test_run_url}}",
+  "source": "Runscope",
+  "details": {"bucket": "{{bucket_name}}", "region": "{{region}}"}
+}`
+)
+
+var webhookPlaceholder = regexp.MustCompile(`{{\s*([A-Za-z0-9_.-]+)\s*}}`)
+
+// sampleNotification is rendered by WebhookIntegration.Validate to check the payload of a template
+var sampleNotification = &ResultNotification{
+	TestID:          "8e7afae4-23b6-492a-b4b9-75d515b5082b",
+	TestName:        "Sample \"test\"",
+	TestRunID:       "cd5b1b4a-3c4e-4a48-b3c7-a7c2b7cb3d6a",
+	TestRunURL:      "https://www.runscope.com/radar/z3n32gktzx94/8e7afae4/history/cd5b1b4a",
+	BucketKey:       "z3n32gktzx94",
+	BucketName:      "Sample",
+	EnvironmentName: "Sample",
+	Result:          TestResultFail,
+	Region:          "us1",
+	RegionName:      "US Virginia",
+}
+
+// Validate checks the PayloadTemplate of the webhook locally: placeholders are closed and name a field of
+// ResultNotification, e.g. {{test_name}}, or a variable, e.g. {{variables.userId}}, and a json payload renders to
+// valid json
+func (webhook *WebhookIntegration) Validate() error {
+	if webhook.PayloadTemplate == "" {
+		return nil
+	}
+
+	fields, err := notificationFields(&ResultNotification{})
+	if err != nil {
+		return err
+	}
+
+	placeholders := webhookPlaceholder.FindAllStringSubmatch(webhook.PayloadTemplate, -1)
+	if strings.Count(webhook.PayloadTemplate, "{{") != len(placeholders) ||
+		strings.Count(webhook.PayloadTemplate, "}}") != len(placeholders) {
+		return fmt.Errorf("Error validating payload template: unbalanced or invalid placeholder")
+	}
+
+	for _, placeholder := range placeholders {
+		name := placeholder[1]
+		if _, ok := fields[name]; ok && name != "requests" && name != "variables" && name != "initial_variables" {
+			continue
+		}
+
+		if strings.HasPrefix(name, "variables.") || strings.HasPrefix(name, "initial_variables.") {
+			continue
+		}
+
+		return fmt.Errorf("Error validating payload template: unknown field %q", name)
+	}
+
+	_, err = webhook.RenderPayload(sampleNotification)
+	return err
+}
+
+// RenderPayload returns the body the webhook posts for a notification. Placeholders are replaced by the value of
+// the field, strings are escaped for use within a json string when the payload is json and missing values render
+// empty. Without PayloadTemplate it's the notification itself
+func (webhook *WebhookIntegration) RenderPayload(notification *ResultNotification) ([]byte, error) {
+	if webhook.PayloadTemplate == "" {
+		return json.Marshal(notification)
+	}
+
+	fields, err := notificationFields(notification)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonPayload := webhook.ContentType == "" || strings.Contains(webhook.ContentType, "json")
+	payload := webhookPlaceholder.ReplaceAllStringFunc(webhook.PayloadTemplate, func(placeholder string) string {
+		return payloadValue(notificationField(fields, webhookPlaceholder.FindStringSubmatch(placeholder)[1]), jsonPayload)
+	})
+
+	if jsonPayload && !json.Valid([]byte(payload)) {
+		return nil, fmt.Errorf("Error rendering payload template: payload is not valid json")
+	}
+
+	return []byte(payload), nil
+}
+
+// notificationFields returns the fields of the notification by their json name
+func notificationFields(notification *ResultNotification) (map[string]interface{}, error) {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]interface{}{}
+	return fields, json.Unmarshal(body, &fields)
+}
+
+func notificationField(fields map[string]interface{}, name string) interface{} {
+	parts := strings.SplitN(name, ".", 2)
+	value := fields[parts[0]]
+	if len(parts) == 1 {
+		return value
+	}
+
+	if variables, ok := value.(map[string]interface{}); ok {
+		return variables[parts[1]]
+	}
+
+	return nil
+}
+
+func payloadValue(value interface{}, jsonPayload bool) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case string:
+		if !jsonPayload {
+			return value
+		}
+
+		quoted, _ := json.Marshal(value)
+		return string(quoted[1 : len(quoted)-1])
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(value)
+	default:
+		encoded, _ := json.Marshal(value)
+		return string(encoded)
+	}
+}
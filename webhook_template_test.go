@@ -0,0 +1,58 @@
+package runscope
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestWebhookIntegrationValidate(t *testing.T) {
+	for _, template := range []string{"", MicrosoftTeamsPayloadTemplate, OpsGeniePayloadTemplate,
+		`{"run": "{{ test_run_id }}", "user": "{{variables.userId}}", "expired": {{agent_expired}}}`} {
+		if err := (&WebhookIntegration{PayloadTemplate: template}).Validate(); err != nil {
+			t.Errorf("Expected template %s to be valid, actual %s", template, err)
+		}
+	}
+
+	for _, template := range []string{
+		`{"test": "{{test_nme}}"}`,
+		`{"test": "{{test_name}"}`,
+		`{"requests": {{requests}}}`,
+		`{"test": {{test_name}}}`,
+	} {
+		if err := (&WebhookIntegration{PayloadTemplate: template}).Validate(); err == nil {
+			t.Errorf("Expected template %s to be invalid", template)
+		}
+	}
+
+	text := &WebhookIntegration{ContentType: "text/plain", PayloadTemplate: "{{test_name}} {{result}}"}
+	if err := text.Validate(); err != nil {
+		t.Errorf("Expected text template to be valid, actual %s", err)
+	}
+}
+
+func TestWebhookIntegrationRenderPayload(t *testing.T) {
+	notification, err := parseResultNotification([]byte(resultWebhookPayload))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	webhook := &WebhookIntegration{PayloadTemplate: OpsGeniePayloadTemplate}
+	payload, err := webhook.RenderPayload(notification)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	alert := map[string]interface{}{}
+	if err := json.Unmarshal(payload, &alert); err != nil {
+		t.Fatal(err)
+	}
+
+	if alert["message"] != "Smoke test fail in prod" {
+		t.Errorf("Expected message %q, actual %q", "Smoke test fail in prod", alert["message"])
+	}
+
+	webhook = &WebhookIntegration{ContentType: "text/plain", PayloadTemplate: "{{test_name}}: {{variables.userId}}"}
+	if payload, _ = webhook.RenderPayload(notification); string(payload) != "Smoke test: 1" {
+		t.Errorf("Expected payload %q, actual %q", "Smoke test: 1", payload)
+	}
+}
@@ -0,0 +1,97 @@
+package runscope
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+const resultWebhookPayload = `
+{
+  "test_id": "8e7afae4-23b6-492a-b4b9-75d515b5082b",
+  "test_name": "Smoke test",
+  "test_run_id": "cd5b1b4a-3c4e-4a48-b3c7-a7c2b7cb3d6a",
+  "test_run_url": "https://www.runscope.com/radar/z3n32gktzx94/8e7afae4/history/cd5b1b4a",
+  "team_id": "2a4b3a4e-2b5e-4c3f-8a1e-0d1f7e6c5b4a",
+  "team_name": "Acme",
+  "bucket_key": "z3n32gktzx94",
+  "bucket_name": "Production",
+  "environment_uuid": "1eeb3695-5d0f-467c-9d51-8b773dce29ba",
+  "environment_name": "prod",
+  "result": "fail",
+  "started_at": 1494023235.0,
+  "finished_at": 1494023236.5,
+  "agent": null,
+  "agent_expired": null,
+  "region": "us1",
+  "region_name": "US Virginia - None",
+  "variables": {
+    "userId": "1"
+  },
+  "initial_variables": {
+    "baseUrl": "https://example.com"
+  },
+  "requests": [
+    {
+      "step_type": "request",
+      "method": "GET",
+      "url": "https://example.com/users/1",
+      "result": "fail",
+      "response_status_code": 503,
+      "response_size_bytes": 20,
+      "response_time_ms": 134,
+      "note": "",
+      "assertions": {"pass": 0, "fail": 1, "total": 1},
+      "scripts": {"pass": 0, "fail": 0, "total": 0},
+      "variables": {"pass": 1, "fail": 0, "total": 1}
+    }
+  ]
+}
+`
+
+func TestParseResultWebhook(t *testing.T) {
+	request, err := http.NewRequest("POST", "https://hooks.example.com/runscope", strings.NewReader(resultWebhookPayload))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	notification, err := ParseResultWebhook(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if notification.Passed() || notification.TestName != "Smoke test" || notification.BucketKey != "z3n32gktzx94" {
+		t.Errorf("Expected failed Smoke test in z3n32gktzx94, actual %s %s %s",
+			notification.Result, notification.TestName, notification.BucketKey)
+	}
+
+	if notification.FinishedAt.Sub(*notification.StartedAt) != 1500*time.Millisecond {
+		t.Errorf("Expected duration %s, actual %s", 1500*time.Millisecond,
+			notification.FinishedAt.Sub(*notification.StartedAt))
+	}
+
+	if notification.Variables["userId"] != "1" || notification.InitialVariables["baseUrl"] != "https://example.com" {
+		t.Errorf("Expected variables, actual %v %v", notification.Variables, notification.InitialVariables)
+	}
+
+	request0 := notification.Requests[0]
+	if request0.ResponseStatus != 503 || request0.Assertions.Fail != 1 || request0.Variables.Pass != 1 {
+		t.Errorf("Expected 503 with a failed assertion, actual %d %v", request0.ResponseStatus, request0.Assertions)
+	}
+
+	if test := notification.Test(); test.ID != notification.TestID || test.Bucket.Key != notification.BucketKey {
+		t.Errorf("Expected test %s in bucket %s, actual %v", notification.TestID, notification.BucketKey, test)
+	}
+}
+
+func TestParseResultWebhookInvalid(t *testing.T) {
+	request, err := http.NewRequest("POST", "https://hooks.example.com/runscope", strings.NewReader("not json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ParseResultWebhook(request); err == nil {
+		t.Error("Expected an error for an invalid payload")
+	}
+}